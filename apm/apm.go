@@ -0,0 +1,166 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apm
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/mapping"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// ApplicationInsights is the type of a binding that provides the Azure Application Insights Java agent.
+	ApplicationInsights = "ApplicationInsights"
+
+	// NewRelic is the type of a binding that provides the New Relic Java agent.
+	NewRelic = "NewRelic"
+)
+
+// kind describes the binding of an APM agent.  Each binding must have "uri" and "sha256" credentials and may have a
+// "version" credential.  Credentials listed in env are contributed as default launch environment variables.  Secrets,
+// such as license keys and connection strings, are deliberately not listed and must be provided at launch.
+type kind struct {
+	id   string
+	name string
+	env  map[string]string
+}
+
+var kinds = map[string]kind{
+	ApplicationInsights: {
+		id:   "applicationinsights-javaagent",
+		name: "Azure Application Insights Java Agent",
+		env: map[string]string{
+			"role-name":     "APPLICATIONINSIGHTS_ROLE_NAME",
+			"sampling-rate": "APPLICATIONINSIGHTS_SAMPLING_PERCENTAGE",
+		},
+	},
+	NewRelic: {
+		id:   "newrelic-javaagent",
+		name: "New Relic Java Agent",
+		env: map[string]string{
+			"app-name":  "NEW_RELIC_APP_NAME",
+			"log-level": "NEW_RELIC_LOG_LEVEL",
+		},
+	},
+}
+
+// Agent represents an APM agent, contributed from a binding, attached to a Spring Boot application.
+type Agent struct {
+	// Type is the type of the binding.
+	Type string
+
+	configuration Configuration
+	layer         layers.DependencyLayer
+	layers        layers.Layers
+}
+
+// Configuration is the launch environment, keyed by name, that configures an Agent.
+type Configuration map[string]string
+
+func (c Configuration) Identity() (string, string) {
+	return "Agent Configuration", fmt.Sprintf("(%d variables)", len(c))
+}
+
+// Contribute makes the contribution to launch.
+func (a Agent) Contribute() error {
+	err := a.layer.Contribute(func(artifact string, layer layers.DependencyLayer) error {
+		layer.Logger.Body("Copying to %s", layer.Root)
+
+		destination := filepath.Join(layer.Root, layer.ArtifactName())
+		if err := helper.CopyFile(artifact, destination); err != nil {
+			return err
+		}
+
+		return layer.AppendLaunchEnv("JAVA_OPTS", " -javaagent:%s", destination)
+	}, layers.Launch)
+	if err := mapping.Verification(a.layer.Dependency, err); err != nil {
+		return err
+	}
+
+	if len(a.configuration) == 0 {
+		return nil
+	}
+
+	return a.layers.Layer(a.layer.Dependency.ID+"-configuration").Contribute(a.configuration, func(layer layers.Layer) error {
+		for k, v := range a.configuration {
+			if err := layer.DefaultLaunchEnv(k, v); err != nil {
+				return err
+			}
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewAgents creates an Agent for each APM binding, ordered by type.  A type with more than one binding is ignored.
+func NewAgents(build build.Build) ([]Agent, error) {
+	var types []string
+	for t := range kinds {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	var agents []Agent
+	for _, t := range types {
+		k := kinds[t]
+
+		c, ok := build.Services.FindServiceCredentials(t, "uri", "sha256")
+		if !ok {
+			continue
+		}
+
+		dep := buildpack.Dependency{
+			ID:     k.id,
+			Name:   k.name,
+			URI:    fmt.Sprintf("%s", c["uri"]),
+			SHA256: fmt.Sprintf("%s", c["sha256"]),
+			Stacks: buildpack.Stacks{build.Stack},
+		}
+
+		v := "0.0.0"
+		if s, ok := c["version"]; ok {
+			v = fmt.Sprintf("%s", s)
+		}
+
+		if err := dep.Version.UnmarshalText([]byte(v)); err != nil {
+			return nil, fmt.Errorf("invalid %s binding version %s: %w", t, v, err)
+		}
+
+		dep, _, err := mapping.Map(build, dep)
+		if err != nil {
+			return nil, err
+		}
+
+		configuration := Configuration{}
+		for cred, env := range k.env {
+			if s, ok := c[cred]; ok {
+				configuration[env] = fmt.Sprintf("%s", s)
+			}
+		}
+
+		agents = append(agents, Agent{t, configuration, build.Layers.DependencyLayer(dep), build.Layers})
+	}
+
+	return agents, nil
+}
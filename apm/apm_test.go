@@ -0,0 +1,124 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apm_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/apm"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestAPM(t *testing.T) {
+	spec.Run(t, "APM", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			f      *test.BuildFactory
+			server *httptest.Server
+			sha    string
+		)
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+
+			b, err := ioutil.ReadFile(filepath.Join("testdata", "stub-javaagent.jar"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			s := sha256.Sum256(b)
+			sha = hex.EncodeToString(s[:])
+
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(b)
+			}))
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		it("returns no agents without bindings", func() {
+			g.Expect(apm.NewAgents(f.Build)).To(gomega.BeEmpty())
+		})
+
+		it("ignores bindings without uri and sha256", func() {
+			f.AddService("test-newrelic", map[string]interface{}{"app-name": "test-app"}, apm.NewRelic)
+
+			g.Expect(apm.NewAgents(f.Build)).To(gomega.BeEmpty())
+		})
+
+		it("returns error for invalid version", func() {
+			f.AddService("test-newrelic", map[string]interface{}{
+				"uri":     server.URL + "/newrelic.jar",
+				"sha256":  sha,
+				"version": "test-version",
+			}, apm.NewRelic)
+
+			_, err := apm.NewAgents(f.Build)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid NewRelic binding version test-version")))
+		})
+
+		it("contributes agents and configuration", func() {
+			f.AddService("test-newrelic", map[string]interface{}{
+				"uri":         server.URL + "/newrelic.jar",
+				"sha256":      sha,
+				"app-name":    "test-app",
+				"license-key": "test-license-key",
+			}, apm.NewRelic)
+			f.AddService("test-applicationinsights", map[string]interface{}{
+				"uri":     server.URL + "/applicationinsights-agent.jar",
+				"sha256":  sha,
+				"version": "3.0.0",
+			}, apm.ApplicationInsights)
+
+			a, err := apm.NewAgents(f.Build)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(a).To(gomega.HaveLen(2))
+			g.Expect(a[0].Type).To(gomega.Equal(apm.ApplicationInsights))
+			g.Expect(a[1].Type).To(gomega.Equal(apm.NewRelic))
+
+			for _, agent := range a {
+				g.Expect(agent.Contribute()).To(gomega.Succeed())
+			}
+
+			layer := f.Build.Layers.Layer("applicationinsights-javaagent")
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(layer).To(test.HaveAppendLaunchEnvironment("JAVA_OPTS", " -javaagent:%s",
+				filepath.Join(layer.Root, "applicationinsights-agent.jar")))
+			g.Expect(f.Build.Layers.Layer("applicationinsights-javaagent-configuration").Root).
+				NotTo(gomega.BeADirectory())
+
+			layer = f.Build.Layers.Layer("newrelic-javaagent")
+			g.Expect(layer).To(test.HaveAppendLaunchEnvironment("JAVA_OPTS", " -javaagent:%s",
+				filepath.Join(layer.Root, "newrelic.jar")))
+
+			layer = f.Build.Layers.Layer("newrelic-javaagent-configuration")
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(layer).To(test.HaveDefaultLaunchEnvironment("NEW_RELIC_APP_NAME", "test-app"))
+			g.Expect(filepath.Join(layer.Root, "env.launch", "NEW_RELIC_LICENSE_KEY.default")).NotTo(gomega.BeAnExistingFile())
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,210 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package applicationjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// DefaultPort is the environment variable that configures a default server.port.
+	DefaultPort = "BP_SPRING_BOOT_DEFAULT_PORT"
+
+	// DefaultProfiles is the environment variable that configures a default comma-separated list of
+	// spring.profiles.active.
+	DefaultProfiles = "BP_SPRING_BOOT_DEFAULT_PROFILES"
+
+	// Defaults is the name of the file in the defaults layer that contains the default properties.
+	Defaults = "defaults.json"
+
+	// Layer is the name of the layer containing the default properties.
+	Layer = "application-json-defaults"
+
+	// Merger is the id of the buildpack provided helper that merges the default properties into
+	// $SPRING_APPLICATION_JSON at launch.
+	Merger = "application-json-merger"
+
+	// Variable is the environment variable that Spring Boot reads inline JSON properties from.
+	Variable = "SPRING_APPLICATION_JSON"
+)
+
+// Flatten returns the properties of a JSON object keyed by their dotted names, as Spring Boot flattens them.  Arrays
+// are not flattened.  An empty string has no properties.
+func Flatten(s string) (map[string]interface{}, error) {
+	p := make(map[string]interface{})
+
+	if strings.TrimSpace(s) == "" {
+		return p, nil
+	}
+
+	var j map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &j); err != nil {
+		return nil, fmt.Errorf("invalid JSON object %s: %w", s, err)
+	}
+
+	flatten("", j, p)
+	return p, nil
+}
+
+// Merge merges JSON objects into a single JSON object with flattened keys.  Properties of later objects take
+// precedence over those of earlier ones.  If there are no properties, an empty string is returned.
+func Merge(objects ...string) (string, error) {
+	p := make(map[string]interface{})
+
+	for _, o := range objects {
+		f, err := Flatten(o)
+		if err != nil {
+			return "", err
+		}
+
+		for k, v := range f {
+			p[k] = v
+		}
+	}
+
+	if len(p) == 0 {
+		return "", nil
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// ApplicationJSON contributes default properties and a helper that merges them beneath $SPRING_APPLICATION_JSON at
+// launch, so that setting $SPRING_APPLICATION_JSON on the platform does not discard them.
+type ApplicationJSON struct {
+	// Properties are the default properties.
+	Properties map[string]string `toml:"properties"`
+
+	layer  layers.Layer
+	merger layers.HelperLayer
+}
+
+func (a ApplicationJSON) Identity() (string, string) {
+	k := make([]string, 0, len(a.Properties))
+	for n := range a.Properties {
+		k = append(k, n)
+	}
+	sort.Strings(k)
+
+	return "Spring Application JSON Defaults", strings.Join(k, ", ")
+}
+
+// Contribute makes the contribution to launch.
+func (a ApplicationJSON) Contribute() error {
+	if err := a.layer.Contribute(a, func(layer layers.Layer) error {
+		b, err := json.Marshal(a.Properties)
+		if err != nil {
+			return err
+		}
+
+		if err := helper.WriteFile(filepath.Join(layer.Root, Defaults), 0644, "%s\n", b); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch); err != nil {
+		return err
+	}
+
+	defaults := filepath.Join(a.layer.Root, Defaults)
+
+	return a.merger.Contribute(func(artifact string, layer layers.HelperLayer) error {
+		layer.Logger.Body("Copying to %s", layer.Root)
+
+		destination := filepath.Join(layer.Root, "bin", Merger)
+		if err := helper.CopyFile(artifact, destination); err != nil {
+			return err
+		}
+
+		return layer.WriteProfile(Merger, `%s="$("%s" "%s")" || exit 1
+export %[1]s
+`, Variable, destination, defaults)
+	}, layers.Launch)
+}
+
+// MergeFiles merges the JSON objects in files beneath a JSON object, typically the value of $SPRING_APPLICATION_JSON.
+func MergeFiles(s string, files ...string) (string, error) {
+	var o []string
+
+	for _, f := range files {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return "", err
+		}
+		o = append(o, string(b))
+	}
+
+	return Merge(append(o, s)...)
+}
+
+// NewApplicationJSON creates a new ApplicationJSON instance.  OK is true if $BP_SPRING_BOOT_DEFAULT_PORT or
+// $BP_SPRING_BOOT_DEFAULT_PROFILES is set.
+func NewApplicationJSON(build build.Build) (ApplicationJSON, bool, error) {
+	p := make(map[string]string)
+
+	if s, ok := config.Lookup(DefaultPort); ok {
+		if i, err := strconv.Atoi(s); err != nil || i < 0 || i > 65535 {
+			return ApplicationJSON{}, false, fmt.Errorf("invalid %s %s: must be a port", DefaultPort, s)
+		}
+		p["server.port"] = s
+	}
+
+	if s, ok := config.Lookup(DefaultProfiles); ok && strings.TrimSpace(s) != "" {
+		p["spring.profiles.active"] = s
+	}
+
+	if len(p) == 0 {
+		return ApplicationJSON{}, false, nil
+	}
+
+	return ApplicationJSON{
+		p,
+		build.Layers.Layer(Layer),
+		build.Layers.HelperLayer(Merger, "Spring Application JSON Merger"),
+	}, true, nil
+}
+
+func flatten(prefix string, in map[string]interface{}, out map[string]interface{}) {
+	for k, v := range in {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+
+		if m, ok := v.(map[string]interface{}); ok {
+			flatten(k, m, out)
+		} else {
+			out[k] = v
+		}
+	}
+}
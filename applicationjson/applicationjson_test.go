@@ -0,0 +1,127 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package applicationjson_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/applicationjson"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestApplicationJSON(t *testing.T) {
+	spec.Run(t, "ApplicationJSON", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		when("Merge", func() {
+
+			it("returns empty string without properties", func() {
+				g.Expect(applicationjson.Merge("", "{}")).To(gomega.BeEmpty())
+			})
+
+			it("flattens nested objects", func() {
+				g.Expect(applicationjson.Merge(`{"server": {"port": 8080, "servlet": {"context-path": "/test"}}}`)).
+					To(gomega.MatchJSON(`{"server.port": 8080, "server.servlet.context-path": "/test"}`))
+			})
+
+			it("prefers later objects", func() {
+				g.Expect(applicationjson.Merge(
+					`{"server.port": "8080", "spring.profiles.active": "test-profile"}`,
+					`{"server": {"port": 9090}, "test": ["test-1", "test-2"]}`,
+				)).To(gomega.MatchJSON(`{
+  "server.port": 9090,
+  "spring.profiles.active": "test-profile",
+  "test": ["test-1", "test-2"]
+}`))
+			})
+
+			it("returns error for invalid JSON", func() {
+				_, err := applicationjson.Merge(`["test"]`)
+				g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid JSON object")))
+			})
+		})
+
+		it("merges files beneath a JSON object", func() {
+			root := test.ScratchDir(t, "applicationjson")
+			test.WriteFile(t, filepath.Join(root, "defaults.json"), `{"server.port": "8080", "spring.profiles.active": "test-profile"}`)
+
+			g.Expect(applicationjson.MergeFiles(`{"spring": {"profiles": {"active": "platform-profile"}}}`, filepath.Join(root, "defaults.json"))).
+				To(gomega.MatchJSON(`{"server.port": "8080", "spring.profiles.active": "platform-profile"}`))
+		})
+
+		when("NewApplicationJSON", func() {
+
+			var f *test.BuildFactory
+
+			it.Before(func() {
+				f = test.NewBuildFactory(t)
+			})
+
+			it("returns false without defaults", func() {
+				_, ok, err := applicationjson.NewApplicationJSON(f.Build)
+				g.Expect(ok).To(gomega.BeFalse())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			it("returns defaults", func() {
+				defer test.ReplaceEnv(t, applicationjson.DefaultPort, "9090")()
+				defer test.ReplaceEnv(t, applicationjson.DefaultProfiles, "test-1,test-2")()
+
+				a, ok, err := applicationjson.NewApplicationJSON(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(a.Properties).To(gomega.Equal(map[string]string{
+					"server.port":            "9090",
+					"spring.profiles.active": "test-1,test-2",
+				}))
+			})
+
+			it("returns error for invalid port", func() {
+				defer test.ReplaceEnv(t, applicationjson.DefaultPort, "70000")()
+
+				_, _, err := applicationjson.NewApplicationJSON(f.Build)
+				g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid BP_SPRING_BOOT_DEFAULT_PORT 70000")))
+			})
+
+			it("contributes defaults and merger", func() {
+				defer test.ReplaceEnv(t, applicationjson.DefaultPort, "9090")()
+				test.TouchFile(t, f.Build.Buildpack.Root, "bin", applicationjson.Merger)
+
+				a, _, err := applicationjson.NewApplicationJSON(f.Build)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(a.Contribute()).To(gomega.Succeed())
+
+				layer := f.Build.Layers.Layer(applicationjson.Layer)
+				g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+				g.Expect(filepath.Join(layer.Root, applicationjson.Defaults)).To(test.HaveContent(`{"server.port":"9090"}
+`))
+
+				merger := f.Build.Layers.Layer(applicationjson.Merger)
+				g.Expect(merger).To(test.HaveLayerMetadata(false, false, true))
+				g.Expect(filepath.Join(merger.Root, "bin", applicationjson.Merger)).To(gomega.BeARegularFile())
+				g.Expect(merger).To(test.HaveProfile(applicationjson.Merger, `SPRING_APPLICATION_JSON="$("%s" "%s")" || exit 1
+export SPRING_APPLICATION_JSON
+`, filepath.Join(merger.Root, "bin", applicationjson.Merger), filepath.Join(layer.Root, applicationjson.Defaults)))
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,336 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bindings
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+)
+
+const (
+	// Enabled is the environment variable that contributes the bindings translator when set to true.
+	Enabled = "BP_SPRING_BOOT_BINDINGS_TRANSLATOR"
+
+	// Translator is the id of the buildpack provided helper that translates bindings to $SPRING_APPLICATION_JSON at
+	// launch.
+	Translator = "bindings-translator"
+
+	// ServiceBindingRoot is the environment variable that contains the root of the Kubernetes Service Binding
+	// bindings.
+	ServiceBindingRoot = "SERVICE_BINDING_ROOT"
+
+	// CNBBindings is the environment variable that contains the root of the deprecated CNB bindings.  It is used when
+	// $SERVICE_BINDING_ROOT is not set.
+	CNBBindings = "CNB_BINDINGS"
+
+	// SpringCloudBindings is the name of the library that, when present, translates bindings itself.
+	SpringCloudBindings = "spring-cloud-bindings"
+)
+
+// Binding is a binding mounted into the container at launch.
+type Binding struct {
+	// Name is the name of the binding, the name of its directory.
+	Name string
+
+	// Type is the type of the binding, e.g. mysql.
+	Type string
+
+	// Secret are the entries of the binding.
+	Secret map[string]string
+}
+
+// ReadBindings reads the bindings in a directory, ordered by name.  Both Kubernetes Service Binding bindings, whose
+// type is in a type file beside the entries, and CNB bindings, whose type is in metadata/kind and entries in secret,
+// are read.  An empty or nonexistent root has no bindings.
+func ReadBindings(root string) ([]Binding, error) {
+	if root == "" {
+		return nil, nil
+	}
+
+	if ok, err := helper.FileExists(root); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, nil
+	}
+
+	d, err := readDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var b []Binding
+	for _, c := range d {
+		p := filepath.Join(root, c)
+
+		if i, err := os.Stat(p); err != nil {
+			return nil, err
+		} else if !i.IsDir() {
+			continue
+		}
+
+		binding, err := readBinding(p)
+		if err != nil {
+			return nil, err
+		}
+
+		b = append(b, binding)
+	}
+
+	return b, nil
+}
+
+// Properties returns the Spring properties of a collection of bindings.  Every entry is exposed as
+// k8s.bindings.<name>.<entry>, as spring-cloud-bindings does, and the entries of well-known types are mapped to the
+// properties that Spring Boot auto-configuration consumes.
+func Properties(bindings []Binding) map[string]string {
+	p := make(map[string]string)
+
+	for _, b := range bindings {
+		for k, v := range b.Secret {
+			p[fmt.Sprintf("k8s.bindings.%s.%s", b.Name, k)] = v
+		}
+
+		if m, ok := mappers[b.Type]; ok {
+			m(b.Secret, p)
+		}
+	}
+
+	return p
+}
+
+// Translate reads the bindings in a directory and returns their Spring properties as $SPRING_APPLICATION_JSON, or an
+// empty string if there are none.
+func Translate(root string) (string, error) {
+	b, err := ReadBindings(root)
+	if err != nil {
+		return "", err
+	}
+
+	p := Properties(b)
+	if len(p) == 0 {
+		return "", nil
+	}
+
+	j, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+
+	return string(j), nil
+}
+
+// Root returns the root of the bindings at launch, or an empty string if there is none.
+func Root() string {
+	if r, ok := os.LookupEnv(ServiceBindingRoot); ok {
+		return r
+	}
+
+	return os.Getenv(CNBBindings)
+}
+
+// BindingsTranslator represents the helper that translates bindings to $SPRING_APPLICATION_JSON at launch.  The
+// properties are merged beneath an existing $SPRING_APPLICATION_JSON, which takes precedence.  If the bindings cannot
+// be translated, a warning is printed and $SPRING_APPLICATION_JSON is left unchanged, rather than failing the launch.
+type BindingsTranslator struct {
+	layer layers.HelperLayer
+}
+
+// Contribute makes the contribution to launch.
+func (b BindingsTranslator) Contribute() error {
+	return b.layer.Contribute(func(artifact string, layer layers.HelperLayer) error {
+		layer.Logger.Body("Copying to %s", layer.Root)
+
+		destination := filepath.Join(layer.Root, "bin", Translator)
+		if err := helper.CopyFile(artifact, destination); err != nil {
+			return err
+		}
+
+		if err := layer.WriteProfile(Translator, `if BINDINGS_JSON="$("%s")"; then
+  if [ -n "${BINDINGS_JSON}" ]; then
+    export SPRING_APPLICATION_JSON="${BINDINGS_JSON}"
+  fi
+else
+  echo "Warning: unable to translate bindings to SPRING_APPLICATION_JSON" >&2
+fi
+unset BINDINGS_JSON
+`, destination); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewBindingsTranslator creates a new BindingsTranslator instance.  OK is true if $BP_SPRING_BOOT_BINDINGS_TRANSLATOR
+// is true and spring-cloud-bindings is not in the class path.
+func NewBindingsTranslator(build build.Build, classPath []string) (BindingsTranslator, bool, error) {
+	if e, err := config.LookupBool(Enabled, false); err != nil {
+		return BindingsTranslator{}, false, err
+	} else if !e {
+		return BindingsTranslator{}, false, nil
+	}
+
+	if _, ok := springboot.FindJARDependency(classPath, SpringCloudBindings); ok {
+		return BindingsTranslator{}, false, nil
+	}
+
+	return BindingsTranslator{build.Layers.HelperLayer(Translator, "Bindings Translator")}, true, nil
+}
+
+type mapper func(secret map[string]string, properties map[string]string)
+
+var mappers = map[string]mapper{
+	"mongodb":    mapping(map[string][]string{"uri": {"spring.data.mongodb.uri"}}),
+	"mysql":      dataSource("mysql"),
+	"postgresql": dataSource("postgresql"),
+	"redis": mapping(map[string][]string{
+		"host":     {"spring.redis.host", "spring.data.redis.host"},
+		"password": {"spring.redis.password", "spring.data.redis.password"},
+		"port":     {"spring.redis.port", "spring.data.redis.port"},
+	}),
+}
+
+func mapping(m map[string][]string) mapper {
+	return func(secret map[string]string, properties map[string]string) {
+		for k, names := range m {
+			if v, ok := secret[k]; ok {
+				for _, n := range names {
+					properties[n] = v
+				}
+			}
+		}
+	}
+}
+
+func dataSource(scheme string) mapper {
+	return func(secret map[string]string, properties map[string]string) {
+		mapping(map[string][]string{
+			"jdbc-url": {"spring.datasource.url"},
+			"password": {"spring.datasource.password"},
+			"username": {"spring.datasource.username"},
+		})(secret, properties)
+
+		if _, ok := secret["jdbc-url"]; ok {
+			return
+		}
+
+		if h, ok := secret["host"]; ok {
+			u := fmt.Sprintf("jdbc:%s://%s", scheme, h)
+			if p, ok := secret["port"]; ok {
+				u = fmt.Sprintf("%s:%s", u, p)
+			}
+			u = fmt.Sprintf("%s/%s", u, secret["database"])
+
+			properties["spring.datasource.url"] = u
+		}
+	}
+}
+
+func readBinding(path string) (Binding, error) {
+	b := Binding{Name: filepath.Base(path), Secret: make(map[string]string)}
+
+	if ok, err := helper.FileExists(filepath.Join(path, "metadata", "kind")); err != nil {
+		return Binding{}, err
+	} else if ok {
+		if b.Type, err = readFile(filepath.Join(path, "metadata", "kind")); err != nil {
+			return Binding{}, err
+		}
+
+		if err := readEntries(filepath.Join(path, "secret"), b.Secret); err != nil {
+			return Binding{}, err
+		}
+
+		return b, nil
+	}
+
+	if err := readEntries(path, b.Secret); err != nil {
+		return Binding{}, err
+	}
+
+	b.Type = b.Secret["type"]
+	delete(b.Secret, "type")
+	delete(b.Secret, "provider")
+
+	return b, nil
+}
+
+func readEntries(path string, entries map[string]string) error {
+	if ok, err := helper.FileExists(path); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	d, err := readDir(path)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range d {
+		p := filepath.Join(path, c)
+
+		if i, err := os.Stat(p); err != nil {
+			return err
+		} else if i.IsDir() {
+			continue
+		}
+
+		if entries[c], err = readFile(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readDir returns the names of the entries of a directory, ordered by name, without hidden entries such as the ..data
+// links of Kubernetes volumes.
+func readDir(path string) ([]string, error) {
+	d, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var n []string
+	for _, i := range d {
+		if !strings.HasPrefix(i.Name(), ".") {
+			n = append(n, i.Name())
+		}
+	}
+
+	return n, nil
+}
+
+func readFile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bindings_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/bindings"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestBindings(t *testing.T) {
+	spec.Run(t, "Bindings", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var root string
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "bindings")
+		})
+
+		when("ReadBindings", func() {
+
+			it("returns no bindings for empty root", func() {
+				g.Expect(bindings.ReadBindings("")).To(gomega.BeEmpty())
+			})
+
+			it("returns no bindings for nonexistent root", func() {
+				g.Expect(bindings.ReadBindings(filepath.Join(root, "test-nonexistent"))).To(gomega.BeEmpty())
+			})
+
+			it("reads Kubernetes Service Binding bindings", func() {
+				test.WriteFile(t, filepath.Join(root, "test-binding", "type"), "mysql\n")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "provider"), "test-provider")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "username"), "test-username\n")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "..data", "username"), "test-username")
+				test.TouchFile(t, root, "test-file")
+
+				g.Expect(bindings.ReadBindings(root)).To(gomega.Equal([]bindings.Binding{
+					{Name: "test-binding", Type: "mysql", Secret: map[string]string{"username": "test-username"}},
+				}))
+			})
+
+			it("reads CNB bindings", func() {
+				test.WriteFile(t, filepath.Join(root, "test-binding", "metadata", "kind"), "redis")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "metadata", "provider"), "test-provider")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "secret", "host"), "test-host")
+
+				g.Expect(bindings.ReadBindings(root)).To(gomega.Equal([]bindings.Binding{
+					{Name: "test-binding", Type: "redis", Secret: map[string]string{"host": "test-host"}},
+				}))
+			})
+
+			it("follows links", func() {
+				test.WriteFile(t, filepath.Join(root, "..data", "password"), "test-password")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "type"), "redis")
+				g.Expect(os.Symlink(filepath.Join(root, "..data", "password"), filepath.Join(root, "test-binding", "password"))).To(gomega.Succeed())
+
+				b, err := bindings.ReadBindings(root)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(b[0].Secret).To(gomega.Equal(map[string]string{"password": "test-password"}))
+			})
+		})
+
+		when("Properties", func() {
+
+			it("exposes every entry", func() {
+				g.Expect(bindings.Properties([]bindings.Binding{
+					{Name: "test-binding", Type: "test-type", Secret: map[string]string{"test-key": "test-value"}},
+				})).To(gomega.Equal(map[string]string{"k8s.bindings.test-binding.test-key": "test-value"}))
+			})
+
+			it("maps data sources", func() {
+				p := bindings.Properties([]bindings.Binding{
+					{Name: "db", Type: "postgresql", Secret: map[string]string{
+						"database": "test-database",
+						"host":     "test-host",
+						"password": "test-password",
+						"port":     "5432",
+						"username": "test-username",
+					}},
+				})
+
+				g.Expect(p).To(gomega.HaveKeyWithValue("spring.datasource.url", "jdbc:postgresql://test-host:5432/test-database"))
+				g.Expect(p).To(gomega.HaveKeyWithValue("spring.datasource.username", "test-username"))
+				g.Expect(p).To(gomega.HaveKeyWithValue("spring.datasource.password", "test-password"))
+			})
+
+			it("prefers jdbc-url", func() {
+				p := bindings.Properties([]bindings.Binding{
+					{Name: "db", Type: "mysql", Secret: map[string]string{
+						"host":     "test-host",
+						"jdbc-url": "jdbc:mysql://test-jdbc-host/test-database",
+					}},
+				})
+
+				g.Expect(p).To(gomega.HaveKeyWithValue("spring.datasource.url", "jdbc:mysql://test-jdbc-host/test-database"))
+			})
+
+			it("maps redis", func() {
+				p := bindings.Properties([]bindings.Binding{
+					{Name: "cache", Type: "redis", Secret: map[string]string{"host": "test-host", "port": "6379"}},
+				})
+
+				g.Expect(p).To(gomega.HaveKeyWithValue("spring.redis.host", "test-host"))
+				g.Expect(p).To(gomega.HaveKeyWithValue("spring.data.redis.port", "6379"))
+			})
+		})
+
+		it("translates bindings to JSON", func() {
+			test.WriteFile(t, filepath.Join(root, "test-binding", "type"), "mongodb")
+			test.WriteFile(t, filepath.Join(root, "test-binding", "uri"), "mongodb://test-host/test-database")
+
+			g.Expect(bindings.Translate(root)).To(gomega.MatchJSON(`{
+  "k8s.bindings.test-binding.uri": "mongodb://test-host/test-database",
+  "spring.data.mongodb.uri": "mongodb://test-host/test-database"
+}`))
+		})
+
+		it("translates no bindings to an empty string", func() {
+			g.Expect(bindings.Translate(root)).To(gomega.BeEmpty())
+		})
+
+		it("uses $SERVICE_BINDING_ROOT before $CNB_BINDINGS", func() {
+			defer test.ReplaceEnv(t, bindings.CNBBindings, "test-cnb-bindings")()
+			g.Expect(bindings.Root()).To(gomega.Equal("test-cnb-bindings"))
+
+			defer test.ReplaceEnv(t, bindings.ServiceBindingRoot, "test-service-binding-root")()
+			g.Expect(bindings.Root()).To(gomega.Equal("test-service-binding-root"))
+		})
+
+		when("NewBindingsTranslator", func() {
+
+			var f *test.BuildFactory
+
+			it.Before(func() {
+				f = test.NewBuildFactory(t)
+			})
+
+			it("returns false by default", func() {
+				_, ok, err := bindings.NewBindingsTranslator(f.Build, nil)
+				g.Expect(ok).To(gomega.BeFalse())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			it("returns false when spring-cloud-bindings is present", func() {
+				defer test.ReplaceEnv(t, bindings.Enabled, "true")()
+
+				_, ok, err := bindings.NewBindingsTranslator(f.Build, []string{"/test-lib/spring-cloud-bindings-1.7.0.jar"})
+				g.Expect(ok).To(gomega.BeFalse())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			it("contributes translator", func() {
+				defer test.ReplaceEnv(t, bindings.Enabled, "true")()
+				test.TouchFile(t, f.Build.Buildpack.Root, "bin", bindings.Translator)
+
+				b, ok, err := bindings.NewBindingsTranslator(f.Build, []string{"/test-lib/test-1.2.3.jar"})
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(b.Contribute()).To(gomega.Succeed())
+
+				layer := f.Build.Layers.Layer(bindings.Translator)
+				g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+				g.Expect(filepath.Join(layer.Root, "bin", bindings.Translator)).To(gomega.BeARegularFile())
+				g.Expect(layer).To(test.HaveProfile(bindings.Translator, `if BINDINGS_JSON="$("%s")"; then
+  if [ -n "${BINDINGS_JSON}" ]; then
+    export SPRING_APPLICATION_JSON="${BINDINGS_JSON}"
+  fi
+else
+  echo "Warning: unable to translate bindings to SPRING_APPLICATION_JSON" >&2
+fi
+unset BINDINGS_JSON
+`, filepath.Join(layer.Root, "bin", bindings.Translator)))
+			})
+
+			it("normalizes modification times of translator", func() {
+				defer test.ReplaceEnv(t, bindings.Enabled, "true")()
+				test.TouchFile(t, f.Build.Buildpack.Root, "bin", bindings.Translator)
+
+				b, _, err := bindings.NewBindingsTranslator(f.Build, []string{"/test-lib/test-1.2.3.jar"})
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(b.Contribute()).To(gomega.Succeed())
+
+				i, err := os.Stat(filepath.Join(f.Build.Layers.Layer(bindings.Translator).Root, "bin", bindings.Translator))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(i.ModTime()).To(gomega.BeTemporally("==", reproducible.DefaultTime))
+			})
+
+			it("warns and leaves SPRING_APPLICATION_JSON unchanged when translation fails", func() {
+				defer test.ReplaceEnv(t, bindings.Enabled, "true")()
+				test.WriteFileWithPerm(t, filepath.Join(f.Build.Buildpack.Root, "bin", bindings.Translator), 0755,
+					"#!/bin/sh\nexit 1\n")
+
+				b, _, err := bindings.NewBindingsTranslator(f.Build, nil)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(b.Contribute()).To(gomega.Succeed())
+
+				layer := f.Build.Layers.Layer(bindings.Translator)
+				c := exec.Command("sh", "-c", `. "$0" && printf '%s' "${SPRING_APPLICATION_JSON}"`,
+					filepath.Join(layer.Root, "profile.d", bindings.Translator))
+				c.Env = append(os.Environ(), `SPRING_APPLICATION_JSON={"test-key":"test-value"}`)
+
+				out, err := c.Output()
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(string(out)).To(gomega.Equal(`{"test-key":"test-value"}`))
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -19,11 +19,20 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/cloudfoundry/libcfbuildpack/v2/build"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/spring-boot-cnb/apm"
+	"github.com/cloudfoundry/spring-boot-cnb/applicationjson"
+	"github.com/cloudfoundry/spring-boot-cnb/bindings"
+	"github.com/cloudfoundry/spring-boot-cnb/classpath"
 	"github.com/cloudfoundry/spring-boot-cnb/cli"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/events"
+	"github.com/cloudfoundry/spring-boot-cnb/otel"
 	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/cloudfoundry/spring-boot-cnb/truststore"
 )
 
 func main() {
@@ -33,6 +42,12 @@ func main() {
 		os.Exit(101)
 	}
 
+	build, err = events.Configure(build, os.Stdout)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Failed to initialize Build: %s\n", err)
+		os.Exit(101)
+	}
+
 	if code, err := b(build); err != nil {
 		build.Logger.TerminalError(build.Buildpack, err.Error())
 		os.Exit(code)
@@ -44,39 +59,156 @@ func main() {
 func b(build build.Build) (int, error) {
 	var ps []buildpackplan.Plan
 
-	if s, ok, err := springboot.NewSpringBoot(build); err != nil {
+	t := time.Now()
+	e, err := events.NewLogger(build.Logger, os.Stdout)
+	if err != nil {
+		return build.Failure(102), err
+	}
+
+	if err := config.Check(build.Logger); err != nil {
+		return build.Failure(102), err
+	}
+
+	m, mOk, err := springboot.NewMultiApplication(build)
+	if err != nil {
 		return build.Failure(102), err
-	} else if ok {
+	}
+
+	var (
+		s        springboot.SpringBoot
+		sOk, cOk bool
+		c        cli.Command
+	)
+
+	if !mOk {
+		s, sOk, err = springboot.NewSpringBoot(build)
+		if err != nil {
+			return build.Failure(102), err
+		}
+
+		c, cOk, err = cli.NewCommand(build)
+		if err != nil {
+			return build.Failure(102), err
+		}
+	}
+
+	if sOk || cOk || mOk {
 		build.Logger.Title(build.Buildpack)
+	}
+
+	if sOk && cOk {
+		force, err := config.LookupBool(cli.Force, false)
+		if err != nil {
+			return build.Failure(102), err
+		}
+
+		if force {
+			build.Logger.BodyWarning("Found both a Spring Boot application and Groovy files. %s is true, contributing the Spring Boot CLI.", cli.Force)
+			sOk = false
+		} else {
+			build.Logger.BodyWarning("Found both a Spring Boot application and Groovy files. Contributing the Spring Boot application, set %s to true to contribute the Spring Boot CLI instead.", cli.Force)
+			cOk = false
+		}
+	}
 
-		if err = s.Contribute(); err != nil {
+	if sOk {
+		if err = e.Time("contribute", s.Contribute); err != nil {
 			return build.Failure(103), err
 		}
 
-		p, err := s.Plan()
-		if err != nil {
+		if err := classpath.NewClassPathVerifier(build).Contribute(); err != nil {
 			return build.Failure(103), err
 		}
 
-		ps = append(ps, p)
+		if t, ok, err := bindings.NewBindingsTranslator(build, s.Metadata.ClassPath); err != nil {
+			return build.Failure(102), err
+		} else if ok {
+			if err := t.Contribute(); err != nil {
+				return build.Failure(103), err
+			}
+		}
 	}
 
-	if c, ok, err := cli.NewCommand(build); err != nil {
-		return build.Failure(102), err
-	} else if ok {
-		build.Logger.Title(build.Buildpack)
+	if mOk {
+		if err = e.Time("contribute", m.Contribute); err != nil {
+			return build.Failure(103), err
+		}
+
+		ps = append(ps, m.Plan())
+	}
 
-		if l, err := cli.NewCLI(build); err != nil {
+	if sOk || mOk {
+		if a, ok, err := applicationjson.NewApplicationJSON(build); err != nil {
 			return build.Failure(102), err
-		} else {
-			if err := l.Contribute(); err != nil {
+		} else if ok {
+			if err := a.Contribute(); err != nil {
+				return build.Failure(103), err
+			}
+		}
+
+		if t, ok, err := truststore.NewTrustStore(build); err != nil {
+			return build.Failure(102), err
+		} else if ok {
+			if err := t.Contribute(); err != nil {
+				return build.Failure(103), err
+			}
+		}
+
+		if o, ok, err := otel.NewOpenTelemetry(build); err != nil {
+			return build.Failure(102), err
+		} else if ok {
+			if err := o.Contribute(); err != nil {
 				return build.Failure(103), err
 			}
 		}
 
-		if err = c.Contribute(); err != nil {
+		if a, err := apm.NewAgents(build); err != nil {
+			return build.Failure(102), err
+		} else {
+			for _, agent := range a {
+				if err := agent.Contribute(); err != nil {
+					return build.Failure(103), err
+				}
+			}
+		}
+
+	}
+
+	if sOk {
+		p, err := s.Plan()
+		if err != nil {
+			return build.Failure(103), err
+		}
+
+		ps = append(ps, p)
+	}
+
+	if cOk {
+		l, err := cli.NewCLI(build)
+		if err != nil {
+			return build.Failure(102), err
+		}
+
+		if err := l.Contribute(); err != nil {
 			return build.Failure(103), err
 		}
+
+		if err = e.Time("contribute-cli", c.Contribute); err != nil {
+			return build.Failure(103), err
+		}
+
+		p, err := c.Plan(l)
+		if err != nil {
+			return build.Failure(103), err
+		}
+
+		ps = append(ps, p)
+	}
+
+	d := time.Since(t)
+	e.Timing("build", d)
+	if len(ps) > 0 {
+		build.Logger.Header("Completed in %s", d.Round(time.Millisecond))
 	}
 
 	return build.Success(ps...)
@@ -17,17 +17,22 @@
 package main
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/cloudfoundry/libcfbuildpack/v2/build"
 	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/classpath"
+	"github.com/cloudfoundry/spring-boot-cnb/cli"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
 	"github.com/onsi/gomega"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 )
 
 func TestBuild(t *testing.T) {
-	spec.Run(t, "Build", func(t *testing.T, _ spec.G, it spec.S) {
+	spec.Run(t, "Build", func(t *testing.T, when spec.G, it spec.S) {
 
 		g := gomega.NewWithT(t)
 
@@ -36,5 +41,65 @@ func TestBuild(t *testing.T) {
 
 			g.Expect(b(f.Build)).To(gomega.Equal(build.SuccessStatusCode))
 		})
+
+		it("contributes multiple Spring Boot applications", func() {
+			f := test.NewBuildFactory(t)
+			defer test.ReplaceEnv(t, springboot.Applications, "apps/*")()
+			for _, a := range []string{"a", "b"} {
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "apps", a, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+			}
+
+			g.Expect(b(f.Build)).To(gomega.Equal(build.SuccessStatusCode))
+
+			l, err := ioutil.ReadFile(filepath.Join(f.Build.Layers.Root, "launch.toml"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(string(l)).To(gomega.ContainSubstring(`type = "web-a"`))
+			g.Expect(string(l)).To(gomega.ContainSubstring(`type = "web-b"`))
+		})
+
+		when("Spring Boot application and Groovy files", func() {
+
+			var f *test.BuildFactory
+
+			it.Before(func() {
+				f = test.NewBuildFactory(t)
+				test.TouchFile(t, f.Build.Buildpack.Root, "bin", classpath.Verifier)
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test.Application
+Spring-Boot-Version: test-version`)
+				test.CopyFile(t, filepath.Join("..", "springboot", "testdata", "main_class", "test", "Application.class"),
+					filepath.Join(f.Build.Application.Root, "test-classes", "test", "Application.class"))
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test.groovy"), "class X {")
+			})
+
+			it("contributes Spring Boot application", func() {
+				g.Expect(b(f.Build)).To(gomega.Equal(build.SuccessStatusCode))
+
+				l, err := ioutil.ReadFile(filepath.Join(f.Build.Layers.Root, "launch.toml"))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(string(l)).To(gomega.ContainSubstring(`type = "spring-boot"`))
+				g.Expect(string(l)).NotTo(gomega.ContainSubstring(`type = "spring-boot-cli"`))
+			})
+
+			it("contributes Spring Boot CLI when forced", func() {
+				defer test.ReplaceEnv(t, cli.Force, "true")()
+				f.AddDependency(cli.Dependency, filepath.Join("..", "cli", "testdata", "stub-spring-boot-cli.tar.gz"))
+
+				g.Expect(b(f.Build)).To(gomega.Equal(build.SuccessStatusCode))
+
+				l, err := ioutil.ReadFile(filepath.Join(f.Build.Layers.Root, "launch.toml"))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(string(l)).To(gomega.ContainSubstring(`type = "spring-boot-cli"`))
+				g.Expect(string(l)).NotTo(gomega.ContainSubstring(`type = "spring-boot"` + "\n"))
+			})
+		})
 	}, spec.Report(report.Terminal{}))
 }
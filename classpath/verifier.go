@@ -0,0 +1,134 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package classpath
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// Enabled is the environment variable that enables verification of $CLASSPATH at launch when set to true.
+	Enabled = "BPL_SPRING_BOOT_CLASSPATH_VERIFY"
+
+	// Verifier is the id of the buildpack provided helper that verifies $CLASSPATH at launch.
+	Verifier = "classpath-verifier"
+)
+
+// ClassPathVerifier represents the helper that verifies $CLASSPATH at launch.
+type ClassPathVerifier struct {
+	layer layers.HelperLayer
+}
+
+// Contribute makes the contribution to launch.
+func (c ClassPathVerifier) Contribute() error {
+	return c.layer.Contribute(func(artifact string, layer layers.HelperLayer) error {
+		layer.Logger.Body("Copying to %s", layer.Root)
+
+		destination := filepath.Join(layer.Root, "bin", Verifier)
+		if err := helper.CopyFile(artifact, destination); err != nil {
+			return err
+		}
+
+		if err := layer.WriteProfile(Verifier, `if [ "${%s:-false}" = "true" ]; then
+  "%s" || exit 1
+fi
+`, Enabled, destination); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewClassPathVerifier creates a new ClassPathVerifier instance.
+func NewClassPathVerifier(build build.Build) ClassPathVerifier {
+	return ClassPathVerifier{build.Layers.HelperLayer(Verifier, "Class Path Verifier")}
+}
+
+// Verify checks that every entry of a class path exists and is readable, returning an error that lists every entry that
+// is not.  A wildcard entry (e.g. lib/*), which the JVM expands to the JARs in a directory, is verified by its
+// directory.
+func Verify(classpath string) error {
+	var m []string
+
+	for _, e := range filepath.SplitList(classpath) {
+		if e == "" {
+			continue
+		}
+
+		p := e
+		if filepath.Base(e) == "*" {
+			p = filepath.Dir(e)
+		}
+
+		if err := readable(p); err != nil {
+			m = append(m, fmt.Sprintf("  %s: %s", e, reason(err)))
+		}
+	}
+
+	if len(m) > 0 {
+		return fmt.Errorf("%d $CLASSPATH entries are missing or unreadable:\n%s\n"+
+			"Check for misconfigured image slices or volumes mounted over the application", len(m), strings.Join(m, "\n"))
+	}
+
+	return nil
+}
+
+func readable(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	i, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if i.IsDir() {
+		_, err = f.Readdirnames(1)
+	} else {
+		_, err = f.Read(make([]byte, 1))
+	}
+
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+func reason(err error) string {
+	switch {
+	case os.IsNotExist(err):
+		return "does not exist"
+	case os.IsPermission(err):
+		return "is not readable"
+	default:
+		return err.Error()
+	}
+}
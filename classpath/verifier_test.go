@@ -0,0 +1,110 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package classpath_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/classpath"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestVerifier(t *testing.T) {
+	spec.Run(t, "Verifier", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var root string
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "classpath")
+		})
+
+		it("contributes verifier", func() {
+			f := test.NewBuildFactory(t)
+			test.TouchFile(t, f.Build.Buildpack.Root, "bin", classpath.Verifier)
+
+			g.Expect(classpath.NewClassPathVerifier(f.Build).Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer(classpath.Verifier)
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(filepath.Join(layer.Root, "bin", classpath.Verifier)).To(gomega.BeARegularFile())
+			g.Expect(layer).To(test.HaveProfile(classpath.Verifier, `if [ "${%s:-false}" = "true" ]; then
+  "%s" || exit 1
+fi
+`, classpath.Enabled, filepath.Join(layer.Root, "bin", classpath.Verifier)))
+		})
+
+		it("normalizes modification times of verifier", func() {
+			f := test.NewBuildFactory(t)
+			test.TouchFile(t, f.Build.Buildpack.Root, "bin", classpath.Verifier)
+
+			g.Expect(classpath.NewClassPathVerifier(f.Build).Contribute()).To(gomega.Succeed())
+
+			i, err := os.Stat(filepath.Join(f.Build.Layers.Layer(classpath.Verifier).Root, "bin", classpath.Verifier))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(i.ModTime()).To(gomega.BeTemporally("==", reproducible.DefaultTime))
+		})
+
+		it("passes when all entries exist", func() {
+			test.WriteFile(t, filepath.Join(root, "test-classes", "test.class"), "test")
+			test.WriteFile(t, filepath.Join(root, "test.jar"), "test")
+
+			g.Expect(classpath.Verify(strings.Join([]string{
+				filepath.Join(root, "test-classes"),
+				filepath.Join(root, "test.jar"),
+			}, string(filepath.ListSeparator)))).To(gomega.Succeed())
+		})
+
+		it("passes with wildcard entries of existing directories", func() {
+			test.WriteFile(t, filepath.Join(root, "test-lib", "test.jar"), "test")
+
+			g.Expect(classpath.Verify(filepath.Join(root, "test-lib", "*"))).To(gomega.Succeed())
+		})
+
+		it("lists wildcard entries of missing directories", func() {
+			err := classpath.Verify(filepath.Join(root, "missing", "*"))
+
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(filepath.Join(root, "missing", "*") + ": does not exist")))
+		})
+
+		it("passes with empty entries", func() {
+			g.Expect(classpath.Verify("")).To(gomega.Succeed())
+		})
+
+		it("lists missing entries", func() {
+			test.WriteFile(t, filepath.Join(root, "test.jar"), "test")
+
+			err := classpath.Verify(strings.Join([]string{
+				filepath.Join(root, "test.jar"),
+				filepath.Join(root, "missing-1.jar"),
+				filepath.Join(root, "missing-2.jar"),
+			}, string(filepath.ListSeparator)))
+
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("2 $CLASSPATH entries are missing or unreadable")))
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(filepath.Join(root, "missing-1.jar") + ": does not exist")))
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(filepath.Join(root, "missing-2.jar") + ": does not exist")))
+		})
+	}, spec.Report(report.Terminal{}))
+}
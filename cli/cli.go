@@ -17,39 +17,83 @@
 package cli
 
 import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
 	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
 	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
 	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/mapping"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
 )
 
-// Dependency indicates that an application qualifies to have the Spring Boot CLI run its .groovy files.
-const Dependency = "spring-boot-cli"
+const (
+	// Dependency indicates that an application qualifies to have the Spring Boot CLI run its .groovy files.
+	Dependency = "spring-boot-cli"
+
+	// Mirror is the environment variable containing the base URI of a mirror of the Spring Boot CLI artifact.
+	Mirror = "BP_SPRING_BOOT_CLI_MIRROR"
+)
 
 // CLI represents a Spring Boot CLI application.
 type CLI struct {
 	layer layers.DependencyLayer
 }
 
-// Contribute makes the contribution to launch.
+// Contribute makes the contribution to build and launch, prepending the CLI's bin directory to $PATH so that other
+// buildpacks and custom process types can invoke spring.  The layer is also marked cache, so that later builds with
+// the same dependency (e.g. version and SHA256) reuse the expanded distribution rather than downloading it again.
 func (c CLI) Contribute() error {
-	return c.layer.Contribute(func(artifact string, layer layers.DependencyLayer) error {
+	err := c.layer.Contribute(func(artifact string, layer layers.DependencyLayer) error {
 		layer.Logger.Body("Expanding to %s", layer.Root)
 
-		return helper.ExtractTarGz(artifact, layer.Root, 1)
-	}, layers.Launch)
+		if err := helper.ExtractTarGz(artifact, layer.Root, 1); err != nil {
+			return err
+		}
+
+		if err := layer.PrependPathSharedEnv("PATH", filepath.Join(layer.Root, "bin")); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Build, layers.Cache, layers.Launch)
+
+	return mapping.Verification(c.layer.Dependency, err)
 }
 
 // NewCLI creates a new CLI instance.
 func NewCLI(build build.Build) (CLI, error) {
-	deps, err := build.Buildpack.Dependencies()
+	dep, ok, err := mapping.Best(build, Dependency)
 	if err != nil {
 		return CLI{}, err
 	}
 
-	dep, err := deps.Best(Dependency, "", build.Stack)
-	if err != nil {
-		return CLI{}, err
+	if !ok {
+		if dep, err = mirror(build, dep); err != nil {
+			return CLI{}, err
+		}
 	}
 
 	return CLI{build.Layers.DependencyLayer(dep)}, nil
 }
+
+// mirror rewrites the URI of the dependency to the same artifact under the configured mirror.  The SHA256 is unchanged
+// so a mirrored artifact is still verified.
+func mirror(build build.Build, dep buildpack.Dependency) (buildpack.Dependency, error) {
+	m, ok := config.Lookup(Mirror)
+	if !ok {
+		return dep, nil
+	}
+
+	if m == "" {
+		return buildpack.Dependency{}, fmt.Errorf("invalid %s: must not be empty", Mirror)
+	}
+
+	dep.URI = fmt.Sprintf("%s/%s", strings.TrimSuffix(m, "/"), path.Base(dep.URI))
+	build.Logger.Debug("Mirroring %s to %s", dep.ID, dep.URI)
+	return dep, nil
+}
@@ -17,11 +17,19 @@
 package cli_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/buildpacks/libbuildpack/v2/services"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
 	"github.com/cloudfoundry/libcfbuildpack/v2/test"
 	"github.com/cloudfoundry/spring-boot-cnb/cli"
+	"github.com/cloudfoundry/spring-boot-cnb/mapping"
 	"github.com/onsi/gomega"
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
@@ -47,8 +55,113 @@ func TestCLI(t *testing.T) {
 			g.Expect(a.Contribute()).To(gomega.Succeed())
 
 			layer := f.Build.Layers.Layer("spring-boot-cli")
-			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(layer).To(test.HaveLayerMetadata(true, true, true))
 			g.Expect(filepath.Join(layer.Root, "bin", "spring")).To(gomega.BeARegularFile())
+			g.Expect(layer).To(test.HavePrependPathSharedEnvironment("PATH", filepath.Join(layer.Root, "bin")))
+		})
+
+		it("reuses cached cli without downloading", func() {
+			f.AddDependency(cli.Dependency, filepath.Join("testdata", "stub-spring-boot-cli.tar.gz"))
+
+			a, err := cli.NewCLI(f.Build)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(a.Contribute()).To(gomega.Succeed())
+
+			deps, err := f.Build.Buildpack.Dependencies()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			d, err := deps.Best(cli.Dependency, "", f.Build.Stack)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			download := f.Build.Layers.Layer(d.SHA256)
+			g.Expect(os.RemoveAll(download.Root)).To(gomega.Succeed())
+			g.Expect(os.RemoveAll(download.Metadata)).To(gomega.Succeed())
+
+			a, err = cli.NewCLI(f.Build)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(a.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("spring-boot-cli")
+			g.Expect(filepath.Join(layer.Root, "bin", "spring")).To(gomega.BeARegularFile())
+		})
+
+		when("offline", func() {
+
+			var (
+				dep     buildpack.Dependency
+				fixture string
+			)
+
+			it.Before(func() {
+				var err error
+				fixture, err = filepath.Abs(filepath.Join("testdata", "stub-spring-boot-cli.tar.gz"))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				b, err := ioutil.ReadFile(fixture)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				s := sha256.Sum256(b)
+
+				dep = buildpack.Dependency{
+					ID:     cli.Dependency,
+					Name:   "Spring Boot CLI",
+					URI:    "https://localhost/stub-spring-boot-cli.tar.gz",
+					SHA256: hex.EncodeToString(s[:]),
+					Stacks: buildpack.Stacks{f.Build.Stack},
+				}
+				g.Expect(dep.Version.UnmarshalText([]byte("1.0"))).To(gomega.Succeed())
+
+				f.AddDependencyWithDependency(dep, fixture)
+			})
+
+			it("downloads cli from dependency-mapping binding", func() {
+				f.AddService(mapping.Service, services.Credentials{dep.SHA256: fmt.Sprintf("file://%s", fixture)})
+
+				a, err := cli.NewCLI(f.Build)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(a.Contribute()).To(gomega.Succeed())
+
+				layer := f.Build.Layers.Layer("spring-boot-cli")
+				g.Expect(filepath.Join(layer.Root, "bin", "spring")).To(gomega.BeARegularFile())
+			})
+
+			it("downloads cli from mirror", func() {
+				defer test.ReplaceEnv(t, cli.Mirror, fmt.Sprintf("file://%s/", filepath.Dir(fixture)))()
+
+				a, err := cli.NewCLI(f.Build)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(a.Contribute()).To(gomega.Succeed())
+
+				layer := f.Build.Layers.Layer("spring-boot-cli")
+				g.Expect(filepath.Join(layer.Root, "bin", "spring")).To(gomega.BeARegularFile())
+			})
+
+			it("prefers dependency-mapping binding to mirror", func() {
+				defer test.ReplaceEnv(t, cli.Mirror, "file:///does-not-exist")()
+				f.AddService(mapping.Service, services.Credentials{dep.SHA256: fmt.Sprintf("file://%s", fixture)})
+
+				a, err := cli.NewCLI(f.Build)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(a.Contribute()).To(gomega.Succeed())
+			})
+
+			it("describes checksum mismatch of mirrored cli", func() {
+				other := filepath.Join(test.ScratchDir(t, "mirror"), "stub-spring-boot-cli.tar.gz")
+				test.WriteFile(t, other, "other")
+				defer test.ReplaceEnv(t, cli.Mirror, fmt.Sprintf("file://%s", filepath.Dir(other)))()
+
+				a, err := cli.NewCLI(f.Build)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(a.Contribute()).To(gomega.MatchError(gomega.ContainSubstring("failed checksum verification")))
+			})
+
+			it("returns error for empty mirror", func() {
+				defer test.ReplaceEnv(t, cli.Mirror, "")()
+
+				_, err := cli.NewCLI(f.Build)
+				g.Expect(err).To(gomega.MatchError("invalid BP_SPRING_BOOT_CLI_MIRROR: must not be empty"))
+			})
 		})
 	}, spec.Report(report.Terminal{}))
 }
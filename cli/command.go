@@ -17,59 +17,245 @@
 package cli
 
 import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 
 	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
 	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
 	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/events"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+)
+
+const (
+	// ConfigPattern is the environment variable that overrides the pattern used to identify configuration files.
+	ConfigPattern = config.CLIConfigPattern
+
+	// Exclude is the environment variable that contains globs, relative to the application root and separated by
+	// commas, of paths excluded from Groovy file detection.
+	Exclude = "BP_SPRING_BOOT_CLI_EXCLUDE"
+
+	// Force is the environment variable that, when set to true, contributes the Spring Boot CLI rather than the Spring
+	// Boot application when an application contains both.
+	Force = "BP_SPRING_BOOT_CLI_FORCE"
+
+	// Lib is the directory, relative to the application root, whose JARs are appended to the launch $CLASSPATH.
+	Lib = "lib"
+
+	// OrderFile is the name of the file that lists Groovy files in the order they should be passed to the CLI.
+	OrderFile = ".spring-cli-order"
+
+	// POGOPattern is the environment variable that overrides the pattern used to identify POGO files.
+	POGOPattern = config.CLIPOGOPattern
+
+	// Shell is the environment variable that contributes a shell process type, starting an interactive Spring Boot CLI
+	// shell with the launch $CLASSPATH, when set to true.
+	Shell = "BP_SPRING_BOOT_CLI_SHELL"
+
+	// Test is the environment variable that contributes a test process type, running the Groovy files' tests, when
+	// set to true.
+	Test = "BP_SPRING_BOOT_CLI_TEST"
 )
 
 var (
-	beans   = regexp.MustCompile("beans[\\s]*{")
-	logback = regexp.MustCompile(fmt.Sprintf(".*ch%[1]sqos%[1]slogback%[1]s.*.groovy", string(filepath.Separator)))
-	pogo    = regexp.MustCompile("class [\\w]+[\\s\\w]*{")
+	beans      = "beans[\\s]*{"
+	extensions = []string{".groovy", ".gvy", ".gy"}
+	logback    = regexp.MustCompile(fmt.Sprintf(".*ch%[1]sqos%[1]slogback%[1]s.*", string(filepath.Separator)))
+	pogo       = "class [\\w]+[\\s\\w]*{"
+	prefix     = regexp.MustCompile(`^([\d]+)[-_.]`)
+	shebang    = []byte("#!/usr/bin/env spring")
 )
 
 // Command represents a Spring Boot CLI Command.
 type Command struct {
 	groovyFiles groovyFiles
+	hashes      map[string]string
+	lib         []string
 	layer       layers.Layer
 	layers      layers.Layers
+	root        string
+	shell       bool
+	test        bool
 }
 
-// Contribute makes the contribution to launch.
+// Contribute makes the contribution to launch.  The layer metadata includes the SHA256 of each Groovy file, so an
+// unchanged set of scripts reuses the layer and a changed one is reported file by file.
 func (c Command) Contribute() error {
-	if err := c.layer.Contribute(c.groovyFiles, func(layer layers.Layer) error {
-		return layer.AppendLaunchEnv("GROOVY_FILES", " %s", strings.Join(c.groovyFiles, " "))
+	var previous commandMetadata
+	if err := c.layer.ReadMetadata(&previous); err != nil {
+		c.layer.Logger.Debug("Ignoring invalid layer metadata: %s", err)
+	} else if previous.Hashes != nil {
+		c.report(previous.Hashes)
+	}
+
+	if err := c.layer.Contribute(commandMetadata{c.groovyFiles, c.hashes, c.lib}, func(layer layers.Layer) error {
+		if err := layer.AppendLaunchEnv("GROOVY_FILES", " %s", strings.Join(c.groovyFiles, " ")); err != nil {
+			return err
+		}
+
+		if len(c.lib) > 0 {
+			if err := layer.WriteProfile("classpath", `export CLASSPATH="${CLASSPATH:+${CLASSPATH}%c}%s"
+`, filepath.ListSeparator, strings.Join(c.lib, string(filepath.ListSeparator))); err != nil {
+				return err
+			}
+		}
+
+		return reproducible.Normalize(layer.Root)
 	}, layers.Launch); err != nil {
 		return err
 	}
 
 	command := "spring run -cp $CLASSPATH $GROOVY_FILES"
 
+	p := layers.Processes{
+		{Type: "dev", Command: "spring run --watch -cp $CLASSPATH $GROOVY_FILES"},
+		{Type: "spring-boot-cli", Command: command},
+		{Type: "task", Command: command},
+	}
+
+	if c.shell {
+		p = append(p, layers.Process{Type: "shell", Command: "spring shell -cp $CLASSPATH"})
+	}
+
+	if c.test {
+		p = append(p, layers.Process{Type: "test", Command: "spring test -cp $CLASSPATH $GROOVY_FILES"})
+	}
+
 	return c.layers.WriteApplicationMetadata(layers.Metadata{
-		Processes: layers.Processes{
-			{Type: "spring-boot-cli", Command: command},
-			{Type: "task", Command: command},
-			{Type: "web", Command: command},
-		},
+		Processes: append(p, layers.Process{Type: "web", Command: command}),
 	})
 }
 
-type groovyFiles []string
+// Plan returns the dependency information for this application, the version of the CLI that runs it and the Groovy
+// files and lib JARs, relative to the application root and with their SHA256, that it runs.
+func (c Command) Plan(cli CLI) (buildpackplan.Plan, error) {
+	var g []map[string]interface{}
+	for _, f := range c.groovyFiles {
+		r := c.relative(f)
+		g = append(g, map[string]interface{}{"path": r, "sha256": c.hashes[r]})
+	}
 
-func (g groovyFiles) Identity() (string, string) {
-	return "Groovy Files", fmt.Sprintf("(%d files)", len(g))
+	var l []map[string]interface{}
+	for _, f := range c.lib {
+		h, err := hash(f)
+		if err != nil {
+			return buildpackplan.Plan{}, err
+		}
+
+		l = append(l, map[string]interface{}{"path": c.relative(f), "sha256": h})
+	}
+
+	p := buildpackplan.Plan{
+		Name:     Dependency,
+		Version:  cli.layer.Dependency.Version.Original(),
+		Metadata: buildpackplan.Metadata{"groovy-files": g},
+	}
+
+	if len(l) > 0 {
+		p.Metadata["lib"] = l
+	}
+
+	return p, nil
+}
+
+func (c Command) relative(path string) string {
+	if r, err := filepath.Rel(c.root, path); err == nil {
+		return r
+	}
+
+	return path
 }
 
+// report logs the Groovy files, relative to the application root, that were added, modified, or removed since the
+// previous build.
+func (c Command) report(previous map[string]string) {
+	var added, modified, removed []string
+
+	for p, h := range c.hashes {
+		if ph, ok := previous[p]; !ok {
+			added = append(added, p)
+		} else if ph != h {
+			modified = append(modified, p)
+		}
+	}
+
+	for p := range previous {
+		if _, ok := c.hashes[p]; !ok {
+			removed = append(removed, p)
+		}
+	}
+
+	if len(added)+len(modified)+len(removed) == 0 {
+		return
+	}
+
+	sort.Strings(added)
+	sort.Strings(modified)
+	sort.Strings(removed)
+
+	c.layer.Logger.Body("Groovy files changed: %d added, %d modified, %d removed", len(added), len(modified),
+		len(removed))
+	for _, p := range added {
+		c.layer.Logger.Body("  Added %s", p)
+	}
+	for _, p := range modified {
+		c.layer.Logger.Body("  Modified %s", p)
+	}
+	for _, p := range removed {
+		c.layer.Logger.Body("  Removed %s", p)
+	}
+}
+
+type commandMetadata struct {
+	GroovyFiles []string          `toml:"groovy-files"`
+	Hashes      map[string]string `toml:"hashes"`
+	Lib         []string          `toml:"lib"`
+}
+
+func (c commandMetadata) Identity() (string, string) {
+	return "Groovy Files", fmt.Sprintf("(%d files, %d lib JARs)", len(c.GroovyFiles), len(c.Lib))
+}
+
+type groovyFiles []string
+
 // NewCommand creates a new Command instance.
 func NewCommand(build build.Build) (Command, bool, error) {
-	candidates, err := candidates(build.Application.Root)
+	c, err := config.NewConfig(build.Application.Root)
+	if err != nil {
+		return Command{}, false, err
+	}
+
+	pogo, err := pattern(POGOPattern, c.CLI.POGOPattern, pogo)
+	if err != nil {
+		return Command{}, false, err
+	}
+
+	beans, err := pattern(ConfigPattern, c.CLI.ConfigPattern, beans)
+	if err != nil {
+		return Command{}, false, err
+	}
+
+	exclusions, err := exclusions()
+	if err != nil {
+		return Command{}, false, err
+	}
+
+	candidates, err := candidates(build.Application.Root, exclusions)
 	if err != nil {
 		return Command{}, false, err
 	}
@@ -84,6 +270,10 @@ func NewCommand(build build.Build) (Command, bool, error) {
 			return true // invalid files do not count against analysis
 		}
 
+		if bytes.HasPrefix(b, shebang) {
+			return true
+		}
+
 		s := string(b)
 
 		return pogo.MatchString(s) || beans.MatchString(s)
@@ -91,10 +281,55 @@ func NewCommand(build build.Build) (Command, bool, error) {
 		return Command{}, false, nil
 	}
 
+	candidates, err = order(build.Application.Root, candidates)
+	if err != nil {
+		return Command{}, false, err
+	}
+
+	e, err := events.NewLogger(build.Logger, os.Stdout)
+	if err != nil {
+		return Command{}, false, err
+	}
+	e.Event("detected", events.Fields{"type": Dependency, "groovy-files": len(candidates)})
+
+	hashes := make(map[string]string, len(candidates))
+	for _, c := range candidates {
+		h, err := hash(c)
+		if err != nil {
+			return Command{}, false, err
+		}
+
+		r, err := filepath.Rel(build.Application.Root, c)
+		if err != nil {
+			return Command{}, false, err
+		}
+		hashes[r] = h
+	}
+
+	lib, err := filepath.Glob(filepath.Join(build.Application.Root, Lib, "*.jar"))
+	if err != nil {
+		return Command{}, false, err
+	}
+
+	sh, err := config.LookupBool(Shell, false)
+	if err != nil {
+		return Command{}, false, err
+	}
+
+	t, err := config.LookupBool(Test, false)
+	if err != nil {
+		return Command{}, false, err
+	}
+
 	return Command{
 		groovyFiles(candidates),
+		hashes,
+		lib,
 		build.Layers.Layer("command"),
 		build.Layers,
+		build.Application.Root,
+		sh,
+		t,
 	}, true, nil
 }
 
@@ -108,19 +343,192 @@ func all(candidates []string, predicate func(candidate string) bool) bool {
 	return true
 }
 
-func candidates(root string) ([]string, error) {
-	c, err := helper.FindFiles(root, regexp.MustCompile(`.+\.groovy`))
+func candidates(root string, exclusions springboot.Exclusions) ([]string, error) {
+	var c []string
+
+	if err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if r, err := filepath.Rel(root, path); err == nil && r != "." && exclusions.Excluded(r) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() || logback.MatchString(path) {
+			return nil
+		}
+
+		if isScript(path, info) {
+			c = append(c, path)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func exclusions() (springboot.Exclusions, error) {
+	s, ok := config.Lookup(Exclude)
+	if !ok {
+		return nil, nil
+	}
+
+	var e springboot.Exclusions
+	for _, g := range strings.Split(s, ",") {
+		g = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(g), "/"), "/")
+		if g == "" {
+			continue
+		}
+
+		if _, err := filepath.Match(g, ""); err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %s: %w", Exclude, g, err)
+		}
+
+		e = append(e, g)
+	}
+
+	return e, nil
+}
+
+func hash(file string) (string, error) {
+	s := sha256.New()
+
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(s, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(s.Sum(nil)), nil
+}
+
+// isScript returns whether a file is a Groovy script, either by extension or, for files that are extensionless or
+// executable, by a shebang.
+func isScript(path string, info os.FileInfo) bool {
+	x := filepath.Ext(path)
+
+	for _, e := range extensions {
+		if x == e {
+			return true
+		}
+	}
+
+	if x != "" && info.Mode()&0111 == 0 {
+		return false
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	b, err := bufio.NewReader(f).Peek(len(shebang))
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(b, shebang)
+}
+
+// order sorts candidates so that files listed in the OrderFile come first, in listed order, followed by files with a
+// numeric prefix in numeric order, followed by all remaining files in walk order.
+func order(root string, candidates []string) ([]string, error) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, aOk := ordinal(candidates[i])
+		b, bOk := ordinal(candidates[j])
+
+		if aOk && bOk {
+			return a < b
+		}
+
+		return aOk && !bOk
+	})
+
+	f := filepath.Join(root, OrderFile)
+	if exists, err := helper.FileExists(f); err != nil {
+		return nil, err
+	} else if !exists {
+		return candidates, nil
+	}
+
+	b, err := ioutil.ReadFile(f)
 	if err != nil {
 		return nil, err
 	}
 
-	i := 0
-	for _, s := range c {
-		if !logback.MatchString(s) {
-			c[i] = s
-			i++
+	var listed []string
+	seen := make(map[string]bool)
+	for _, l := range strings.Split(string(b), "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+
+		p := filepath.Join(root, l)
+		if !contains(candidates, p) {
+			return nil, fmt.Errorf("%s lists %s which is not a Groovy file", OrderFile, l)
+		}
+
+		if !seen[p] {
+			listed = append(listed, p)
+			seen[p] = true
+		}
+	}
+
+	for _, c := range candidates {
+		if !seen[c] {
+			listed = append(listed, c)
 		}
 	}
 
-	return c[:i], nil
+	return listed, nil
+}
+
+func ordinal(path string) (int, bool) {
+	m := prefix.FindStringSubmatch(filepath.Base(path))
+	if m == nil {
+		return 0, false
+	}
+
+	i, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+
+	return i, true
+}
+
+func contains(candidates []string, candidate string) bool {
+	for _, c := range candidates {
+		if c == candidate {
+			return true
+		}
+	}
+
+	return false
+}
+
+func pattern(key string, p string, def string) (*regexp.Regexp, error) {
+	if p == "" {
+		p = def
+	}
+
+	r, err := regexp.Compile(p)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile %s: %w", key, err)
+	}
+
+	return r, nil
 }
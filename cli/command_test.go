@@ -17,10 +17,12 @@
 package cli_test
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
 	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
 	"github.com/cloudfoundry/libcfbuildpack/v2/test"
 	"github.com/cloudfoundry/spring-boot-cnb/cli"
@@ -96,6 +98,81 @@ func TestCommand(t *testing.T) {
 				g.Expect(err).NotTo(gomega.HaveOccurred())
 			})
 
+			it("detects .gvy and .gy files", func() {
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test-1.gvy"), "class X {")
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test-2.gy"), "beans {")
+
+				_, ok, err := cli.NewCommand(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			it("detects shebang scripts", func() {
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test"), "#!/usr/bin/env spring\nx")
+
+				_, ok, err := cli.NewCommand(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			it("detects executable shebang scripts with an extension", func() {
+				test.WriteFileWithPerm(t, filepath.Join(f.Build.Application.Root, "test.sh"), 0755, "#!/usr/bin/env spring\nx")
+
+				_, ok, err := cli.NewCommand(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			it("ignores non-executable shebang files with an extension", func() {
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test.txt"), "#!/usr/bin/env spring\nx")
+
+				_, ok, err := cli.NewCommand(f.Build)
+				g.Expect(ok).To(gomega.BeFalse())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			it("uses configured POGO pattern", func() {
+				defer test.ReplaceEnv(t, cli.POGOPattern, "object [\\w]+")()
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test.groovy"), "object X")
+
+				_, ok, err := cli.NewCommand(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			it("uses configured config pattern", func() {
+				defer test.ReplaceEnv(t, cli.ConfigPattern, "config[\\s]*{")()
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test.groovy"), "beans {")
+
+				_, ok, err := cli.NewCommand(f.Build)
+				g.Expect(ok).To(gomega.BeFalse())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			it("returns error for invalid pattern", func() {
+				defer test.ReplaceEnv(t, cli.POGOPattern, "(")()
+
+				_, _, err := cli.NewCommand(f.Build)
+				g.Expect(err).To(gomega.HaveOccurred())
+			})
+
+			it("excludes configured paths", func() {
+				defer test.ReplaceEnv(t, cli.Exclude, "src/test/**,Jenkinsfile.groovy")()
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "src", "test", "groovy", "test.groovy"), "x")
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "Jenkinsfile.groovy"), "x")
+
+				_, ok, err := cli.NewCommand(f.Build)
+				g.Expect(ok).To(gomega.BeFalse())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			it("returns error for invalid exclude pattern", func() {
+				defer test.ReplaceEnv(t, cli.Exclude, "[")()
+
+				_, _, err := cli.NewCommand(f.Build)
+				g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid BP_SPRING_BOOT_CLI_EXCLUDE pattern [")))
+			})
+
 			it("detects invalid .groovy files", func() {
 				test.CopyFile(t, filepath.Join("testdata", "valid_app", "invalid.groovy"), filepath.Join(f.Build.Application.Root, "test.groovy"))
 
@@ -106,6 +183,68 @@ func TestCommand(t *testing.T) {
 
 		})
 
+		when("ordering", func() {
+
+			pogo := func(names ...string) {
+				for _, n := range names {
+					test.WriteFile(t, filepath.Join(f.Build.Application.Root, n), "class Test {}")
+				}
+			}
+
+			groovyFiles := func(names ...string) string {
+				p := []string{""}
+				for _, n := range names {
+					p = append(p, filepath.Join(f.Build.Application.Root, n))
+				}
+				return strings.Join(p, " ")
+			}
+
+			it("orders files with a numeric prefix numerically before other files", func() {
+				pogo("b.groovy", "10-ten.groovy", "2_two.groovy", "a.groovy", "1.one.groovy")
+
+				c, ok, err := cli.NewCommand(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(c.Contribute()).To(gomega.Succeed())
+
+				g.Expect(f.Build.Layers.Layer("command")).To(test.HaveAppendLaunchEnvironment("GROOVY_FILES",
+					groovyFiles("1.one.groovy", "2_two.groovy", "10-ten.groovy", "a.groovy", "b.groovy")))
+			})
+
+			it("keeps files with the same numeric prefix in walk order", func() {
+				pogo("1_b.groovy", "01-c.groovy", "1-a.groovy")
+
+				c, ok, err := cli.NewCommand(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(c.Contribute()).To(gomega.Succeed())
+
+				g.Expect(f.Build.Layers.Layer("command")).To(test.HaveAppendLaunchEnvironment("GROOVY_FILES",
+					groovyFiles("01-c.groovy", "1-a.groovy", "1_b.groovy")))
+			})
+
+			it("orders files listed in .spring-cli-order first", func() {
+				pogo("1-one.groovy", "a.groovy", "b.groovy", "d/c.groovy")
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, cli.OrderFile), "# comment\nb.groovy\n\nd/c.groovy\nb.groovy\n")
+
+				c, ok, err := cli.NewCommand(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(c.Contribute()).To(gomega.Succeed())
+
+				g.Expect(f.Build.Layers.Layer("command")).To(test.HaveAppendLaunchEnvironment("GROOVY_FILES",
+					groovyFiles("b.groovy", "d/c.groovy", "1-one.groovy", "a.groovy")))
+			})
+
+			it("returns error when .spring-cli-order lists a file that is not a Groovy file", func() {
+				pogo("a.groovy")
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, cli.OrderFile), "missing.groovy\n")
+
+				_, _, err := cli.NewCommand(f.Build)
+				g.Expect(err).To(gomega.MatchError(".spring-cli-order lists missing.groovy which is not a Groovy file"))
+			})
+		})
+
 		it("contributes command", func() {
 			test.CopyDirectory(t, filepath.Join("testdata", "valid_app"), f.Build.Application.Root)
 
@@ -129,8 +268,145 @@ func TestCommand(t *testing.T) {
 			command := "spring run -cp $CLASSPATH $GROOVY_FILES"
 			g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(layers.Metadata{
 				Processes: []layers.Process{
+					{Type: "dev", Command: "spring run --watch -cp $CLASSPATH $GROOVY_FILES"},
+					{Type: "spring-boot-cli", Command: command},
+					{Type: "task", Command: command},
+					{Type: "web", Command: command},
+				},
+			}))
+		})
+
+		it("reuses command layer when Groovy files are unchanged", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test.groovy"), "class X {")
+
+			c, _, err := cli.NewCommand(f.Build)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(c.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("command")
+			env := filepath.Join(layer.Root, "env.launch", "GROOVY_FILES.append")
+			g.Expect(os.Remove(env)).To(gomega.Succeed())
+
+			c, _, err = cli.NewCommand(f.Build)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(c.Contribute()).To(gomega.Succeed())
+
+			g.Expect(env).NotTo(gomega.BeAnExistingFile())
+		})
+
+		it("recontributes command layer when a Groovy file changes", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test.groovy"), "class X {")
+
+			c, _, err := cli.NewCommand(f.Build)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(c.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("command")
+			env := filepath.Join(layer.Root, "env.launch", "GROOVY_FILES.append")
+			g.Expect(os.Remove(env)).To(gomega.Succeed())
+
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test.groovy"), "class Y {")
+
+			c, _, err = cli.NewCommand(f.Build)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(c.Contribute()).To(gomega.Succeed())
+
+			g.Expect(env).To(gomega.BeARegularFile())
+
+			var m struct {
+				Hashes map[string]string `toml:"hashes"`
+			}
+			g.Expect(layer.ReadMetadata(&m)).To(gomega.Succeed())
+			g.Expect(m.Hashes).To(gomega.Equal(map[string]string{
+				"test.groovy": "0cbd55d546a9185e0e3fe2a08d4d2181dca4637e36cc85536d33f55fe12a84df",
+			}))
+		})
+
+		it("contributes lib JARs to $CLASSPATH", func() {
+			test.CopyDirectory(t, filepath.Join("testdata", "valid_app"), f.Build.Application.Root)
+			test.TouchFile(t, f.Build.Application.Root, "lib", "test-2.jar")
+			test.TouchFile(t, f.Build.Application.Root, "lib", "test-1.jar")
+			test.TouchFile(t, f.Build.Application.Root, "lib", "test.txt")
+
+			c, ok, err := cli.NewCommand(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(c.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("command")
+			g.Expect(layer).To(test.HaveProfile("classpath", `export CLASSPATH="${CLASSPATH:+${CLASSPATH}:}%s"
+`, strings.Join([]string{
+				filepath.Join(f.Build.Application.Root, "lib", "test-1.jar"),
+				filepath.Join(f.Build.Application.Root, "lib", "test-2.jar"),
+			}, string(filepath.ListSeparator))))
+		})
+
+		it("returns plan with CLI version and hashed Groovy files", func() {
+			f.AddDependencyWithVersion(cli.Dependency, "2.3.4", filepath.Join("testdata", "stub-spring-boot-cli.tar.gz"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test.groovy"), "class X {")
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "lib", "test.jar"), "test-jar")
+
+			c, ok, err := cli.NewCommand(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			l, err := cli.NewCLI(f.Build)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(c.Plan(l)).To(gomega.Equal(buildpackplan.Plan{
+				Name:    cli.Dependency,
+				Version: "2.3.4",
+				Metadata: buildpackplan.Metadata{
+					"groovy-files": []map[string]interface{}{
+						{"path": "test.groovy", "sha256": "9ee3e4c2747c5556c16371e6821a5bc225ee4748f763ab4448bddebeae267d46"},
+					},
+					"lib": []map[string]interface{}{
+						{"path": filepath.Join("lib", "test.jar"), "sha256": "8d61b038e4ca10d6a60b081e0c93d173e59885a207f5f0a8a9d539751898b4d7"},
+					},
+				},
+			}))
+		})
+
+		it("contributes shell process", func() {
+			defer test.ReplaceEnv(t, cli.Shell, "true")()
+			test.CopyDirectory(t, filepath.Join("testdata", "valid_app"), f.Build.Application.Root)
+
+			c, ok, err := cli.NewCommand(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(c.Contribute()).To(gomega.Succeed())
+
+			command := "spring run -cp $CLASSPATH $GROOVY_FILES"
+			g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(layers.Metadata{
+				Processes: []layers.Process{
+					{Type: "dev", Command: "spring run --watch -cp $CLASSPATH $GROOVY_FILES"},
+					{Type: "shell", Command: "spring shell -cp $CLASSPATH"},
+					{Type: "spring-boot-cli", Command: command},
+					{Type: "task", Command: command},
+					{Type: "web", Command: command},
+				},
+			}))
+		})
+
+		it("contributes test process", func() {
+			defer test.ReplaceEnv(t, cli.Test, "true")()
+			test.CopyDirectory(t, filepath.Join("testdata", "valid_app"), f.Build.Application.Root)
+
+			c, ok, err := cli.NewCommand(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(c.Contribute()).To(gomega.Succeed())
+
+			command := "spring run -cp $CLASSPATH $GROOVY_FILES"
+			g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(layers.Metadata{
+				Processes: []layers.Process{
+					{Type: "dev", Command: "spring run --watch -cp $CLASSPATH $GROOVY_FILES"},
 					{Type: "spring-boot-cli", Command: command},
 					{Type: "task", Command: command},
+					{Type: "test", Command: "spring test -cp $CLASSPATH $GROOVY_FILES"},
 					{Type: "web", Command: command},
 				},
 			}))
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cloudfoundry/spring-boot-cnb/health"
+)
+
+func main() {
+	if err := probe(); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func probe() error {
+	root := ""
+	if len(os.Args) > 1 {
+		root = os.Args[1]
+	} else if e, err := os.Executable(); err != nil {
+		return err
+	} else {
+		root = health.Root(e)
+	}
+
+	e, err := health.ReadEndpoint(root)
+	if err != nil {
+		return err
+	}
+
+	return e.Probe(5 * time.Second)
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/buildpacks/libbuildpack/v2/application"
+	bpLayers "github.com/buildpacks/libbuildpack/v2/layers"
+	bpLogger "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+)
+
+const usage = "Usage: inspect <application>"
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer, err io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%s", usage)
+	}
+
+	root, e := filepath.Abs(args[0])
+	if e != nil {
+		return e
+	}
+
+	scratch, e := ioutil.TempDir("", "inspect")
+	if e != nil {
+		return e
+	}
+	defer os.RemoveAll(scratch)
+
+	b := build.Build{Logger: logger.Logger{Logger: bpLogger.NewLogger(nil, err)}}
+	b.Application = application.Application{Root: root}
+	b.Layers = layers.NewLayers(bpLayers.NewLayers(scratch, b.Logger.Logger), bpLayers.NewLayers(scratch, b.Logger.Logger),
+		buildpack.Buildpack{}, b.Logger)
+
+	s, ok, e := springboot.NewSpringBootWithEvents(b, err)
+	if e != nil {
+		return e
+	} else if !ok {
+		return fmt.Errorf("%s is not a Spring Boot application: no Spring-Boot-Version in META-INF/MANIFEST.MF", root)
+	}
+
+	p, e := s.Plan()
+	if e != nil {
+		return e
+	}
+
+	j, e := json.MarshalIndent(map[string]interface{}{"name": p.Name, "metadata": p.Metadata}, "", "  ")
+	if e != nil {
+		return e
+	}
+
+	_, e = fmt.Fprintf(out, "%s\n", j)
+	return e
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestInspect(t *testing.T) {
+	spec.Run(t, "Inspect", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			out, err *bytes.Buffer
+			root     string
+		)
+
+		it.Before(func() {
+			out, err = &bytes.Buffer{}, &bytes.Buffer{}
+			root = test.ScratchDir(t, "application")
+		})
+
+		it("requires an application", func() {
+			g.Expect(run([]string{}, out, err)).To(gomega.MatchError(gomega.ContainSubstring("Usage")))
+		})
+
+		it("rejects non Spring Boot applications", func() {
+			g.Expect(run([]string{root}, out, err)).To(gomega.MatchError(gomega.ContainSubstring("is not a Spring Boot application")))
+		})
+
+		it("prints plan as JSON", func() {
+			test.WriteFile(t, filepath.Join(root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test.Application
+Spring-Boot-Version: test-version`)
+			test.CopyFile(t, filepath.Join("..", "..", "springboot", "testdata", "main_class", "test", "Application.class"),
+				filepath.Join(root, "test-classes", "test", "Application.class"))
+			test.CopyFile(t, filepath.Join("..", "..", "springboot", "testdata", "test-artifact-1-1.2.3.jar"),
+				filepath.Join(root, "test-lib", "test-artifact-1-1.2.3.jar"))
+
+			g.Expect(run([]string{root}, out, err)).To(gomega.Succeed())
+
+			var p struct {
+				Name     string                 `json:"name"`
+				Metadata map[string]interface{} `json:"metadata"`
+			}
+			g.Expect(json.Unmarshal(out.Bytes(), &p)).To(gomega.Succeed())
+
+			g.Expect(p.Name).To(gomega.Equal("spring-boot"))
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("start-class", "test.Application"))
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("version", "test-version"))
+			g.Expect(p.Metadata["dependencies"]).To(gomega.ConsistOf(gomega.HaveKeyWithValue("name", "test-artifact-1")))
+		})
+
+		it("writes JSON events to stderr", func() {
+			defer test.ReplaceEnv(t, "BP_LOG_FORMAT", "json")()
+			test.WriteFile(t, filepath.Join(root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test.Application
+Spring-Boot-Version: test-version`)
+			test.CopyFile(t, filepath.Join("..", "..", "springboot", "testdata", "main_class", "test", "Application.class"),
+				filepath.Join(root, "test-classes", "test", "Application.class"))
+
+			g.Expect(run([]string{root}, out, err)).To(gomega.Succeed())
+
+			var p map[string]interface{}
+			g.Expect(json.Unmarshal(out.Bytes(), &p)).To(gomega.Succeed())
+			g.Expect(err.String()).To(gomega.ContainSubstring(`"event":"detected"`))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/buildpacks/libbuildpack/v2/application"
+	bpLogger "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+)
+
+const usage = `Usage: spring-boot-tool <command> [arguments]
+
+Commands:
+  slices <application>  Print the slice that each file of an exploded Spring Boot application is contributed to, in
+                        the order slices are contributed
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer, err io.Writer) error {
+	if len(args) < 1 {
+		return fmt.Errorf("%s", usage)
+	}
+
+	switch args[0] {
+	case "slices":
+		if len(args) != 2 {
+			return fmt.Errorf("%s", usage)
+		}
+		return slices(args[1], out, err)
+	default:
+		return fmt.Errorf("unknown command %s\n%s", args[0], usage)
+	}
+}
+
+func slices(root string, out io.Writer, err io.Writer) error {
+	root, e := filepath.Abs(root)
+	if e != nil {
+		return e
+	}
+
+	md, ok, e := springboot.NewMetadata(application.Application{Root: root},
+		logger.Logger{Logger: bpLogger.NewLogger(nil, err)})
+	if e != nil {
+		return e
+	} else if !ok {
+		return fmt.Errorf("%s is not a Spring Boot application: no Spring-Boot-Version in META-INF/MANIFEST.MF", root)
+	}
+
+	c, e := springboot.ClassifySlices(root, md)
+	if e != nil {
+		return e
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+	for _, sl := range c {
+		for _, p := range sl.Paths {
+			if _, e := fmt.Fprintf(w, "%s\t%s\n", sl.Name, filepath.FromSlash(p)); e != nil {
+				return e
+			}
+		}
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestSpringBootTool(t *testing.T) {
+	spec.Run(t, "Spring Boot Tool", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var out, err *bytes.Buffer
+
+		it.Before(func() {
+			out, err = &bytes.Buffer{}, &bytes.Buffer{}
+		})
+
+		it("requires a command", func() {
+			g.Expect(run([]string{}, out, err)).To(gomega.MatchError(gomega.ContainSubstring("Usage")))
+		})
+
+		it("rejects unknown commands", func() {
+			g.Expect(run([]string{"test-command"}, out, err)).To(gomega.MatchError(gomega.ContainSubstring("unknown command test-command")))
+		})
+
+		when("slices", func() {
+
+			it("rejects non Spring Boot applications", func() {
+				root := test.ScratchDir(t, "application")
+
+				g.Expect(run([]string{"slices", root}, out, err)).To(gomega.MatchError(gomega.ContainSubstring("is not a Spring Boot application")))
+			})
+
+			it("prints slice of each file", func() {
+				root := test.ScratchDir(t, "application")
+				test.WriteFile(t, filepath.Join(root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: BOOT-INF/classes/
+Spring-Boot-Lib: BOOT-INF/lib/
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+				test.TouchFile(t, root, "BOOT-INF", "classes", "test.class")
+				test.TouchFile(t, root, "BOOT-INF", "lib", "test-1.2.3.jar")
+				test.TouchFile(t, root, "BOOT-INF", "lib", "test-4.5.6-SNAPSHOT.jar")
+				test.TouchFile(t, root, "org", "springframework", "boot", "loader", "JarLauncher.class")
+
+				g.Expect(run([]string{"slices", root}, out, err)).To(gomega.Succeed())
+				g.Expect(out.String()).To(gomega.Equal(`launch                 org/springframework/boot/loader/JarLauncher.class
+dependencies           BOOT-INF/lib/test-1.2.3.jar
+snapshot-dependencies  BOOT-INF/lib/test-4.5.6-SNAPSHOT.jar
+application            BOOT-INF/classes/test.class
+remainder              META-INF/MANIFEST.MF
+`))
+			})
+
+			it("omits excluded files", func() {
+				root := test.ScratchDir(t, "application")
+				test.WriteFile(t, filepath.Join(root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: BOOT-INF/classes/
+Spring-Boot-Lib: BOOT-INF/lib/
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+				test.TouchFile(t, root, "BOOT-INF", "classes", "test.class")
+				test.TouchFile(t, root, "BOOT-INF", "classes", "fixtures", "test.json")
+				defer test.ReplaceEnv(t, springboot.ExcludePatterns, "BOOT-INF/classes/fixtures")()
+
+				g.Expect(run([]string{"slices", root}, out, err)).To(gomega.Succeed())
+				g.Expect(out.String()).To(gomega.Equal(`application  BOOT-INF/classes/test.class
+remainder    META-INF/MANIFEST.MF
+`))
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
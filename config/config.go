@@ -0,0 +1,165 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/Masterminds/semver"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+)
+
+const (
+	// BuildpackYAML is the file, in the application root, whose spring-boot section configures the buildpack.
+	BuildpackYAML = "buildpack.yml"
+
+	// CLIConfigPattern is the environment variable that overrides the pattern used to identify configuration files.
+	CLIConfigPattern = "BP_SPRING_BOOT_CLI_CONFIG_PATTERN"
+
+	// CLIPOGOPattern is the environment variable that overrides the pattern used to identify POGO files.
+	CLIPOGOPattern = "BP_SPRING_BOOT_CLI_POGO_PATTERN"
+
+	// Process is the environment variable that configures the default process type.
+	Process = "BP_SPRING_BOOT_PROCESS"
+
+	// Slices is the environment variable that configures the slice strategy.
+	Slices = "BP_SPRING_BOOT_SLICES"
+
+	// Version is the environment variable that configures a constraint the Spring-Boot-Version must satisfy.
+	Version = "BP_SPRING_BOOT_VERSION"
+)
+
+const (
+	// SlicesDefault slices by the Spring-Boot-Layers-Index if present, and by file location otherwise.
+	SlicesDefault = "default"
+
+	// SlicesLocation slices by file location, ignoring any Spring-Boot-Layers-Index.
+	SlicesLocation = "location"
+
+	// SlicesNone does not slice the application.
+	SlicesNone = "none"
+)
+
+// Processes are the valid default process types.
+var Processes = []string{"spring-boot", "task", "web"}
+
+// Config is the configuration of the buildpack.  Environment variables take precedence over buildpack.yml.
+type Config struct {
+	// CLI is the configuration of Spring Boot CLI applications.
+	CLI CLI `yaml:"cli"`
+
+	// Process is the default process type.  Buildpack API 0.2 cannot mark a process type as the image default, but the
+	// launcher runs web when no process type is specified, so web is contributed to run the default process type.
+	// Empty means the buildpack chooses.
+	Process string `yaml:"process"`
+
+	// Slices is the slice strategy.
+	Slices string `yaml:"slices"`
+
+	// Version is a constraint the Spring-Boot-Version must satisfy.  Empty means any version.
+	Version string `yaml:"version"`
+}
+
+// CLI is the configuration of Spring Boot CLI applications.
+type CLI struct {
+	// ConfigPattern is the pattern used to identify configuration files.  Empty means the default pattern.
+	ConfigPattern string `yaml:"config-pattern"`
+
+	// POGOPattern is the pattern used to identify POGO files.  Empty means the default pattern.
+	POGOPattern string `yaml:"pogo-pattern"`
+}
+
+// Validate returns an error if any value is invalid.
+func (c Config) Validate() error {
+	if c.Version != "" {
+		if _, err := semver.NewConstraint(c.Version); err != nil {
+			return fmt.Errorf("invalid version %s: %w", c.Version, err)
+		}
+	}
+
+	if c.Process != "" && !contains(Processes, c.Process) {
+		return fmt.Errorf("invalid process %s: must be one of spring-boot, task, or web", c.Process)
+	}
+
+	if !contains([]string{SlicesDefault, SlicesLocation, SlicesNone}, c.Slices) {
+		return fmt.Errorf("invalid slices %s: must be one of %s, %s, or %s", c.Slices, SlicesDefault, SlicesLocation, SlicesNone)
+	}
+
+	if _, err := regexp.Compile(c.CLI.ConfigPattern); err != nil {
+		return fmt.Errorf("invalid cli config-pattern %s: %w", c.CLI.ConfigPattern, err)
+	}
+
+	if _, err := regexp.Compile(c.CLI.POGOPattern); err != nil {
+		return fmt.Errorf("invalid cli pogo-pattern %s: %w", c.CLI.POGOPattern, err)
+	}
+
+	return nil
+}
+
+type buildpackYAML struct {
+	SpringBoot Config `yaml:"spring-boot"`
+}
+
+// NewConfig creates a new Config instance from the buildpack.yml in an application root and the environment.  An
+// error is returned if the configuration is invalid.
+func NewConfig(root string) (Config, error) {
+	var y buildpackYAML
+
+	f := filepath.Join(root, BuildpackYAML)
+	if ok, err := helper.FileExists(f); err != nil {
+		return Config{}, err
+	} else if ok {
+		if err := helper.ReadBuildpackYaml(f, &y); err != nil {
+			return Config{}, fmt.Errorf("unable to read %s: %w", BuildpackYAML, err)
+		}
+	}
+
+	c := y.SpringBoot
+	override(&c.CLI.ConfigPattern, CLIConfigPattern)
+	override(&c.CLI.POGOPattern, CLIPOGOPattern)
+	override(&c.Process, Process)
+	override(&c.Slices, Slices)
+	override(&c.Version, Version)
+
+	if c.Slices == "" {
+		c.Slices = SlicesDefault
+	}
+
+	if err := c.Validate(); err != nil {
+		return Config{}, err
+	}
+
+	return c, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func override(value *string, key string) {
+	if s, ok := Lookup(key); ok {
+		*value = s
+	}
+}
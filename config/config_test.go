@@ -0,0 +1,112 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestConfig(t *testing.T) {
+	spec.Run(t, "Config", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var root string
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "config")
+		})
+
+		it("returns defaults without buildpack.yml", func() {
+			g.Expect(config.NewConfig(root)).To(gomega.Equal(config.Config{Slices: config.SlicesDefault}))
+		})
+
+		it("reads spring-boot section of buildpack.yml", func() {
+			test.WriteFile(t, filepath.Join(root, "buildpack.yml"), `
+spring-boot:
+  version: ">=2.2"
+  process: task
+  slices: location
+  cli:
+    config-pattern: "config[\\s]*{"
+    pogo-pattern: "object [\\w]+"
+other:
+  key: value
+`)
+
+			g.Expect(config.NewConfig(root)).To(gomega.Equal(config.Config{
+				CLI:     config.CLI{ConfigPattern: `config[\s]*{`, POGOPattern: `object [\w]+`},
+				Process: "task",
+				Slices:  config.SlicesLocation,
+				Version: ">=2.2",
+			}))
+		})
+
+		it("prefers environment variables", func() {
+			test.WriteFile(t, filepath.Join(root, "buildpack.yml"), "spring-boot:\n  process: task\n  slices: location\n")
+			defer test.ReplaceEnv(t, config.Process, "web")()
+			defer test.ReplaceEnv(t, config.Slices, config.SlicesNone)()
+
+			c, err := config.NewConfig(root)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(c.Process).To(gomega.Equal("web"))
+			g.Expect(c.Slices).To(gomega.Equal(config.SlicesNone))
+		})
+
+		it("returns error for malformed buildpack.yml", func() {
+			test.WriteFile(t, filepath.Join(root, "buildpack.yml"), "spring-boot: [")
+
+			_, err := config.NewConfig(root)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("unable to read buildpack.yml")))
+		})
+
+		it("returns error for invalid version", func() {
+			defer test.ReplaceEnv(t, config.Version, "test-version")()
+
+			_, err := config.NewConfig(root)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid version test-version")))
+		})
+
+		it("returns error for invalid process", func() {
+			defer test.ReplaceEnv(t, config.Process, "test-process")()
+
+			_, err := config.NewConfig(root)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid process test-process")))
+		})
+
+		it("returns error for invalid slices", func() {
+			defer test.ReplaceEnv(t, config.Slices, "test-slices")()
+
+			_, err := config.NewConfig(root)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid slices test-slices")))
+		})
+
+		it("returns error for invalid CLI pattern", func() {
+			defer test.ReplaceEnv(t, config.CLIPOGOPattern, "(")()
+
+			_, err := config.NewConfig(root)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid cli pogo-pattern")))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+)
+
+// Kind is the kind of value of an environment variable.
+type Kind int
+
+const (
+	// String is a value that is not validated.
+	String Kind = iota
+
+	// Bool is a value that must be parsable by strconv.ParseBool.
+	Bool
+
+	// Int is a value that must be parsable by strconv.Atoi.
+	Int
+
+	// Duration is a value that must be parsable by time.ParseDuration.
+	Duration
+)
+
+// Variable describes an environment variable consumed by the buildpack.
+type Variable struct {
+	// Kind is the kind of value.
+	Kind Kind
+
+	// Launch indicates that the variable is consumed when the image is launched rather than when it is built.
+	Launch bool
+
+	// Values are the valid values.  Empty means any value of Kind.
+	Values []string
+
+	// CaseInsensitive indicates that Values are matched regardless of case.
+	CaseInsensitive bool
+}
+
+// Variables are the environment variables consumed by the buildpack.
+var Variables = map[string]Variable{
+	"BP_LOG_FORMAT":                            {Values: []string{"text", "json"}},
+	"BP_LOG_LEVEL":                             {Values: []string{"DEBUG", "INFO"}, CaseInsensitive: true},
+	"BP_OTEL_ENABLED":                          {Kind: Bool},
+	"BP_SPRING_APPLICATION_PROPERTIES":         {},
+	"BP_SPRING_BOOT_ADDITIONAL_CLASSPATH":      {},
+	"BP_SPRING_BOOT_APPLICATIONS":              {},
+	"BP_SPRING_BOOT_BANNER":                    {Values: []string{"off", "console", "log"}},
+	"BP_SPRING_BOOT_BINDINGS_TRANSLATOR":       {Kind: Bool},
+	"BP_SPRING_BOOT_BUILT_ARTIFACT":            {},
+	"BP_SPRING_BOOT_CLI_CONFIG_PATTERN":        {},
+	"BP_SPRING_BOOT_CLI_EXCLUDE":               {},
+	"BP_SPRING_BOOT_CLI_FORCE":                 {Kind: Bool},
+	"BP_SPRING_BOOT_CLI_MIRROR":                {},
+	"BP_SPRING_BOOT_CLI_POGO_PATTERN":          {},
+	"BP_SPRING_BOOT_CLI_SHELL":                 {Kind: Bool},
+	"BP_SPRING_BOOT_CLI_TEST":                  {Kind: Bool},
+	"BP_SPRING_BOOT_COMMAND_TEMPLATE":          {},
+	"BP_SPRING_BOOT_DEFAULT_PORT":              {Kind: Int},
+	"BP_SPRING_BOOT_DEFAULT_PROFILES":          {},
+	"BP_SPRING_BOOT_DENY_LIST":                 {},
+	"BP_SPRING_BOOT_DENY_LIST_FILE":            {},
+	"BP_SPRING_BOOT_DEV":                       {Kind: Bool},
+	"BP_SPRING_BOOT_DUPLICATE_CLASSES":         {Kind: Bool},
+	"BP_SPRING_BOOT_ENABLED":                   {Kind: Bool},
+	"BP_SPRING_BOOT_ENFORCE_SUPPORTED":         {Kind: Bool},
+	"BP_SPRING_BOOT_EXCLUDE_PATTERNS":          {},
+	"BP_SPRING_BOOT_FINGERPRINT_DATABASE":      {},
+	"BP_SPRING_BOOT_GRACEFUL_SHUTDOWN":         {Kind: Bool},
+	"BP_SPRING_BOOT_JDK_MODULES":               {Kind: Bool},
+	"BP_SPRING_BOOT_LIB_PROVIDED":              {Kind: Bool},
+	"BP_SPRING_BOOT_MODULE":                    {},
+	"BP_SPRING_BOOT_NESTED_DEPENDENCIES":       {Kind: Bool},
+	"BP_SPRING_BOOT_PLAN_DEPENDENCY_LIMIT":     {Kind: Int},
+	"BP_SPRING_BOOT_PROCESS":                   {Values: Processes},
+	"BP_SPRING_BOOT_PROCESS_CONFLICT":          {Values: []string{"defer", "fail", "override"}},
+	"BP_SPRING_BOOT_REQUIRE_JDK":               {Kind: Bool},
+	"BP_SPRING_BOOT_RUNTIME_HINTS":             {Kind: Bool},
+	"BP_SPRING_BOOT_SCAN_TIMEOUT":              {Kind: Duration},
+	"BP_SPRING_BOOT_SHARED_DEPENDENCIES":       {Kind: Bool},
+	"BP_SPRING_BOOT_SHARED_DEPENDENCIES_LIMIT": {Kind: Int},
+	"BP_SPRING_BOOT_SLICES":                    {Values: []string{SlicesDefault, SlicesLocation, SlicesNone}},
+	"BP_SPRING_BOOT_STATSD_ADDRESS":            {},
+	"BP_SPRING_BOOT_UNREADABLE_JARS":           {Values: []string{"fail", "warn"}},
+	"BP_SPRING_BOOT_VERIFY_START_CLASS":        {Kind: Bool},
+	"BP_SPRING_BOOT_VERSION":                   {},
+	"BP_SPRING_BOOT_VULN_ENDPOINT":             {},
+	"BP_SPRING_BOOT_VULN_POLICY":               {Values: []string{"warn", "fail"}},
+	"BP_SPRING_BOOT_VULN_TIMEOUT":              {Kind: Duration},
+	"BP_SPRING_BOOT_WARN_NO_SECURITY":          {Kind: Bool},
+	"BP_SPRING_BOOT_WORKDIR":                   {},
+	"BPL_DEBUG_ENABLED":                        {Kind: Bool, Launch: true},
+	"BPL_DEBUG_PORT":                           {Launch: true},
+	"BPL_DEBUG_SUSPEND":                        {Kind: Bool, Launch: true},
+	"BPL_HEAP_DUMP_PATH":                       {Launch: true},
+	"BPL_JFR_ENABLED":                          {Kind: Bool, Launch: true},
+	"BPL_JMX_ENABLED":                          {Kind: Bool, Launch: true},
+	"BPL_JMX_PORT":                             {Launch: true},
+	"BPL_SPRING_BOOT_ARGS":                     {Launch: true},
+	"BPL_SPRING_BOOT_CLASSPATH_VERIFY":         {Kind: Bool, Launch: true},
+}
+
+// Lookup returns the value of an environment variable.
+func Lookup(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+// LookupBool returns the value of a boolean environment variable, or def if it is not set.
+func LookupBool(key string, def bool) (bool, error) {
+	s, ok := Lookup(key)
+	if !ok {
+		return def, nil
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %s: %w", key, s, err)
+	}
+
+	return b, nil
+}
+
+// LookupInt returns the value of an integer environment variable, or def if it is not set.
+func LookupInt(key string, def int) (int, error) {
+	s, ok := Lookup(key)
+	if !ok {
+		return def, nil
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %s: %w", key, s, err)
+	}
+
+	return i, nil
+}
+
+// LookupDuration returns the value of a duration environment variable, or def if it is not set.
+func LookupDuration(key string, def time.Duration) (time.Duration, error) {
+	s, ok := Lookup(key)
+	if !ok {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %s: %w", key, s, err)
+	}
+
+	return d, nil
+}
+
+// Check validates the environment variables consumed at build time and warns about unknown BP_SPRING_BOOT_* and
+// BPL_SPRING_BOOT_* names, which are likely typos.
+func Check(logger logger.Logger) error {
+	var names []string
+	for _, e := range os.Environ() {
+		names = append(names, strings.SplitN(e, "=", 2)[0])
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		v, ok := Variables[n]
+		if !ok {
+			if strings.HasPrefix(n, "BP_SPRING_BOOT_") || strings.HasPrefix(n, "BPL_SPRING_BOOT_") {
+				logger.BodyWarning("%s is not a known configuration variable and is ignored", n)
+			}
+			continue
+		}
+
+		if v.Launch {
+			continue
+		}
+
+		if err := v.validate(n, os.Getenv(n)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v Variable) validate(key string, value string) error {
+	switch v.Kind {
+	case Bool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("invalid %s %s: %w", key, value, err)
+		}
+	case Int:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("invalid %s %s: %w", key, value, err)
+		}
+	case Duration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("invalid %s %s: %w", key, value, err)
+		}
+	}
+
+	if len(v.Values) > 0 && !v.matches(value) {
+		return fmt.Errorf("invalid %s %s: must be one of %s", key, value, strings.Join(v.Values, ", "))
+	}
+
+	return nil
+}
+
+func (v Variable) matches(value string) bool {
+	for _, s := range v.Values {
+		if s == value || (v.CaseInsensitive && strings.EqualFold(s, value)) {
+			return true
+		}
+	}
+
+	return false
+}
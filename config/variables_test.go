@@ -0,0 +1,180 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/applicationjson"
+	"github.com/cloudfoundry/spring-boot-cnb/bindings"
+	"github.com/cloudfoundry/spring-boot-cnb/classpath"
+	"github.com/cloudfoundry/spring-boot-cnb/cli"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/events"
+	"github.com/cloudfoundry/spring-boot-cnb/otel"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestVariables(t *testing.T) {
+	spec.Run(t, "Variables", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("registers every variable the buildpack consumes", func() {
+			for _, n := range []string{
+				applicationjson.DefaultPort,
+				applicationjson.DefaultProfiles,
+				bindings.Enabled,
+				classpath.Enabled,
+				cli.ConfigPattern,
+				cli.Exclude,
+				cli.Force,
+				cli.Mirror,
+				cli.POGOPattern,
+				cli.Shell,
+				cli.Test,
+				events.Format,
+				events.Level,
+				events.StatsDAddress,
+				otel.Enabled,
+				springboot.AdditionalClassPath,
+				springboot.ApplicationProperties,
+				springboot.Applications,
+				springboot.BannerMode,
+				springboot.BuiltArtifact,
+				springboot.CommandTemplate,
+				springboot.DenyListEntries,
+				springboot.DenyListFile,
+				springboot.Dev,
+				springboot.DuplicateClassesEnabled,
+				springboot.EnforceSupported,
+				springboot.ExcludePatterns,
+				springboot.FingerprintDatabase,
+				springboot.NestedDependencies,
+				springboot.PlanDependencyLimit,
+				springboot.RuntimeHintsEnabled,
+				springboot.SharedDependenciesEnabled,
+				springboot.SharedDependenciesLimit,
+				springboot.StartClassVerification,
+				springboot.GracefulShutdownEnabled,
+				springboot.HeapDumpPath,
+				springboot.JDKModulesEnabled,
+				springboot.JFREnabled,
+				springboot.LibProvided,
+				springboot.Module,
+				springboot.ProcessConflict,
+				springboot.ProgramArgs,
+				springboot.ScanTimeout,
+				springboot.UnreadableJARs,
+				springboot.VulnerabilityEndpoint,
+				springboot.VulnerabilityPolicy,
+				springboot.VulnerabilityTimeout,
+				springboot.WarnNoSecurity,
+				springboot.WorkDir,
+				config.Process,
+				config.Slices,
+				config.Version,
+			} {
+				g.Expect(config.Variables).To(gomega.HaveKey(n))
+			}
+		})
+
+		it("returns default for unset bool", func() {
+			g.Expect(config.LookupBool("BP_SPRING_BOOT_DEV", true)).To(gomega.BeTrue())
+		})
+
+		it("returns error for invalid bool", func() {
+			defer test.ReplaceEnv(t, "BP_SPRING_BOOT_DEV", "test-value")()
+
+			_, err := config.LookupBool("BP_SPRING_BOOT_DEV", false)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid BP_SPRING_BOOT_DEV test-value")))
+		})
+
+		it("returns default for unset int", func() {
+			g.Expect(config.LookupInt("BP_SPRING_BOOT_PLAN_DEPENDENCY_LIMIT", 10)).To(gomega.Equal(10))
+		})
+
+		it("returns int", func() {
+			defer test.ReplaceEnv(t, "BP_SPRING_BOOT_PLAN_DEPENDENCY_LIMIT", "20")()
+
+			g.Expect(config.LookupInt("BP_SPRING_BOOT_PLAN_DEPENDENCY_LIMIT", 10)).To(gomega.Equal(20))
+		})
+
+		it("returns error for invalid int variable", func() {
+			defer test.ReplaceEnv(t, "BP_SPRING_BOOT_PLAN_DEPENDENCY_LIMIT", "test-value")()
+
+			g.Expect(config.Check(f.Build.Logger)).To(gomega.MatchError(gomega.ContainSubstring("invalid BP_SPRING_BOOT_PLAN_DEPENDENCY_LIMIT")))
+		})
+
+		it("returns default for unset duration", func() {
+			g.Expect(config.LookupDuration("BP_SPRING_BOOT_SCAN_TIMEOUT", time.Minute)).To(gomega.Equal(time.Minute))
+		})
+
+		it("returns duration", func() {
+			defer test.ReplaceEnv(t, "BP_SPRING_BOOT_SCAN_TIMEOUT", "90s")()
+
+			g.Expect(config.LookupDuration("BP_SPRING_BOOT_SCAN_TIMEOUT", time.Minute)).To(gomega.Equal(90 * time.Second))
+		})
+
+		it("returns error for invalid duration variable", func() {
+			defer test.ReplaceEnv(t, "BP_SPRING_BOOT_SCAN_TIMEOUT", "test-value")()
+
+			g.Expect(config.Check(f.Build.Logger)).To(gomega.MatchError(gomega.ContainSubstring("invalid BP_SPRING_BOOT_SCAN_TIMEOUT")))
+		})
+
+		it("tolerates unknown variables", func() {
+			defer test.ReplaceEnv(t, "BP_SPRING_BOOT_TEST_TYPO", "test-value")()
+
+			g.Expect(config.Check(f.Build.Logger)).To(gomega.Succeed())
+		})
+
+		it("returns error for invalid bool variable", func() {
+			defer test.ReplaceEnv(t, "BP_SPRING_BOOT_DUPLICATE_CLASSES", "test-value")()
+
+			g.Expect(config.Check(f.Build.Logger)).To(gomega.MatchError(gomega.ContainSubstring("invalid BP_SPRING_BOOT_DUPLICATE_CLASSES")))
+		})
+
+		it("returns error for invalid enumerated variable", func() {
+			defer test.ReplaceEnv(t, "BP_SPRING_BOOT_BANNER", "test-value")()
+
+			g.Expect(config.Check(f.Build.Logger)).To(gomega.MatchError("invalid BP_SPRING_BOOT_BANNER test-value: must be one of off, console, log"))
+		})
+
+		it("matches case-insensitive enumerated variable regardless of case", func() {
+			defer test.ReplaceEnv(t, "BP_LOG_LEVEL", "debug")()
+
+			g.Expect(config.Check(f.Build.Logger)).To(gomega.Succeed())
+		})
+
+		it("does not validate launch variables", func() {
+			defer test.ReplaceEnv(t, "BPL_DEBUG_ENABLED", "test-value")()
+
+			g.Expect(config.Check(f.Build.Logger)).To(gomega.Succeed())
+		})
+	}, spec.Report(report.Terminal{}))
+}
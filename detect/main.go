@@ -22,6 +22,9 @@ import (
 
 	"github.com/buildpacks/libbuildpack/v2/buildplan"
 	"github.com/cloudfoundry/libcfbuildpack/v2/detect"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/events"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
 )
 
 func main() {
@@ -39,10 +42,61 @@ func main() {
 	}
 }
 
+const (
+	// Enabled is the environment variable that disables detection when set to false.
+	Enabled = "BP_SPRING_BOOT_ENABLED"
+
+	// RequireJDK is the environment variable that, when set to true, requires a JDK rather than a JRE at build time
+	// (e.g. for AOT processing, CDS, or jdeps), while still requiring only a JRE at launch.
+	RequireJDK = "BP_SPRING_BOOT_REQUIRE_JDK"
+)
+
 func d(detect detect.Detect) (int, error) {
+	if e, err := config.LookupBool(Enabled, true); err != nil {
+		return detect.Error(102), err
+	} else if !e {
+		detect.Logger.Info("%s is false, skipping", Enabled)
+		return detect.Fail(), nil
+	}
+
+	if err := config.Check(detect.Logger); err != nil {
+		return detect.Error(102), err
+	}
+
+	if _, err := config.NewConfig(detect.Application.Root); err != nil {
+		return detect.Error(102), err
+	}
+
+	e, err := events.NewLogger(detect.Logger, os.Stdout)
+	if err != nil {
+		return detect.Error(102), err
+	}
+
+	if r, ok, err := springboot.Diagnose(detect.Application, detect.Logger); err != nil {
+		detect.Logger.Info("Unable to diagnose Spring Boot application: %s.  Passing in case it is built by another buildpack.", err)
+		e.Event("detect", events.Fields{"spring-boot": false, "reason": err.Error()})
+	} else if ok {
+		detect.Logger.Info("Spring Boot application not yet detected: %s.  Passing in case it is built by another buildpack.", r)
+		e.Event("detect", events.Fields{"spring-boot": false, "reason": string(r)})
+	} else {
+		e.Event("detect", events.Fields{"spring-boot": true})
+	}
+
+	j := buildplan.Required{Name: "jvm-application"}
+
+	if r, err := config.LookupBool(RequireJDK, false); err != nil {
+		return detect.Error(102), err
+	} else if r {
+		j.Metadata = buildplan.Metadata{"build": "jdk", "launch": "jre"}
+	}
+
 	return detect.Pass(buildplan.Plan{
+		Provides: []buildplan.Provided{
+			{Name: springboot.Dependency},
+		},
 		Requires: []buildplan.Required{
-			{Name: "jvm-application"},
+			j,
+			{Name: springboot.Dependency},
 		},
 	})
 }
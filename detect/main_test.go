@@ -17,6 +17,7 @@
 package main
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/buildpacks/libbuildpack/v2/buildplan"
@@ -38,11 +39,78 @@ func TestDetect(t *testing.T) {
 			f = test.NewDetectFactory(t)
 		})
 
-		it("always passes", func() {
+		it("fails when disabled", func() {
+			defer test.ReplaceEnv(t, Enabled, "false")()
+
+			g.Expect(d(f.Detect)).To(gomega.Equal(detect.FailStatusCode))
+		})
+
+		it("returns error when enabled is invalid", func() {
+			defer test.ReplaceEnv(t, Enabled, "test-value")()
+
+			_, err := d(f.Detect)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("returns error when configuration variable is invalid", func() {
+			defer test.ReplaceEnv(t, "BP_SPRING_BOOT_DEV", "test-value")()
+
+			_, err := d(f.Detect)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid BP_SPRING_BOOT_DEV test-value")))
+		})
+
+		it("returns error when configuration is invalid", func() {
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "buildpack.yml"), "spring-boot:\n  slices: test-slices\n")
+
+			_, err := d(f.Detect)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid slices test-slices")))
+		})
+
+		it("passes when enabled", func() {
+			defer test.ReplaceEnv(t, Enabled, "true")()
+
+			g.Expect(d(f.Detect)).To(gomega.Equal(detect.PassStatusCode))
+		})
+
+		it("passes by default", func() {
+			g.Expect(d(f.Detect)).To(gomega.Equal(detect.PassStatusCode))
+			g.Expect(f.Plans).To(test.HavePlans(buildplan.Plan{
+				Provides: []buildplan.Provided{
+					{Name: "spring-boot"},
+				},
+				Requires: []buildplan.Required{
+					{Name: "jvm-application"},
+					{Name: "spring-boot"},
+				},
+			}))
+		})
+
+		it("passes when application cannot be diagnosed", func() {
+			test.TouchFile(t, f.Detect.Application.Root, "META-INF", "MANIFEST.MF", "test-file")
+
 			g.Expect(d(f.Detect)).To(gomega.Equal(detect.PassStatusCode))
 			g.Expect(f.Plans).To(test.HavePlans(buildplan.Plan{
+				Provides: []buildplan.Provided{
+					{Name: "spring-boot"},
+				},
 				Requires: []buildplan.Required{
 					{Name: "jvm-application"},
+					{Name: "spring-boot"},
+				},
+			}))
+		})
+
+		it("requires JDK at build when configured", func() {
+			defer test.ReplaceEnv(t, RequireJDK, "true")()
+
+			g.Expect(d(f.Detect)).To(gomega.Equal(detect.PassStatusCode))
+			g.Expect(f.Plans).To(test.HavePlans(buildplan.Plan{
+				Provides: []buildplan.Provided{
+					{Name: "spring-boot"},
+				},
+				Requires: []buildplan.Required{
+					{Name: "jvm-application", Metadata: buildplan.Metadata{"build": "jdk", "launch": "jre"}},
+					{Name: "spring-boot"},
 				},
 			}))
 		})
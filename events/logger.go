@@ -0,0 +1,159 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	bp "github.com/buildpacks/libbuildpack/v2/layers"
+	bpLogger "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+const (
+	// Format is the environment variable that configures the log format.  Valid values are "text" and "json".
+	Format = "BP_LOG_FORMAT"
+
+	// Level is the environment variable that configures the log level.  Valid values are "DEBUG" and "INFO", in any
+	// case.  DEBUG enables debug logging as BP_DEBUG does.
+	Level = "BP_LOG_LEVEL"
+)
+
+var escapes = regexp.MustCompile("\x1b\\[[\\d;]*m")
+
+// Fields are the values associated with an event.
+type Fields map[string]interface{}
+
+// Logger is an extension to logger.Logger that emits machine-parsable events.
+type Logger struct {
+	logger.Logger
+
+	json   bool
+	writer io.Writer
+}
+
+// Event emits an event.  In json format, the event is written as a single line JSON object.  In text format, the
+// event is written to debug.
+func (l Logger) Event(name string, fields Fields) {
+	if !l.json {
+		l.Debug("Event %s: %v", name, fields)
+		return
+	}
+
+	e := map[string]interface{}{"event": name, "time": time.Now().UTC().Format(time.RFC3339Nano)}
+	for k, v := range fields {
+		e[k] = v
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		l.Debug("Unable to marshal event %s: %s", name, err)
+		return
+	}
+
+	_, _ = fmt.Fprintf(l.writer, "%s\n", b)
+}
+
+// IsJSON returns true if the log format is json.
+func (l Logger) IsJSON() bool {
+	return l.json
+}
+
+// NewLogger creates a new Logger instance that writes events to writer.
+func NewLogger(logger logger.Logger, writer io.Writer) (Logger, error) {
+	f, ok := config.Lookup(Format)
+	if !ok {
+		f = "text"
+	}
+
+	if f != "text" && f != "json" {
+		return Logger{}, fmt.Errorf("%s must be one of text or json: %s", Format, f)
+	}
+
+	return Logger{logger, f == "json", writer}, nil
+}
+
+// Configure replaces the loggers of a build so that all text logging is written as json events when the log format
+// is json, and so that debug logging is enabled when the log level is DEBUG.
+func Configure(b build.Build, writer io.Writer) (build.Build, error) {
+	l, err := NewLogger(b.Logger, writer)
+	if err != nil {
+		return build.Build{}, err
+	}
+
+	d := b.Logger.IsDebugEnabled()
+	if v, ok := config.Lookup(Level); ok && strings.EqualFold(v, "DEBUG") {
+		d = true
+	}
+
+	if !l.json && d == b.Logger.IsDebugEnabled() {
+		return b, nil
+	}
+
+	var debug, info io.Writer = nil, os.Stdout
+	if l.json {
+		info = lines{"info", writer}
+	}
+	if d && l.json {
+		debug = lines{"debug", writer}
+	} else if d {
+		debug = os.Stderr
+	}
+
+	b.Logger = logger.Logger{Logger: bpLogger.NewLogger(debug, info)}
+	b.Buildpack = buildpack.NewBuildpack(b.Buildpack.Buildpack, b.Logger)
+	b.Layers = layers.NewLayers(bp.NewLayers(b.Layers.Root, b.Logger.Logger), bp.NewLayers(b.Buildpack.CacheRoot, b.Logger.Logger), b.Buildpack, b.Logger)
+
+	return b, nil
+}
+
+// lines is a writer that writes each non-empty line as a json log event.
+type lines struct {
+	level  string
+	writer io.Writer
+}
+
+func (l lines) Write(p []byte) (int, error) {
+	for _, s := range bytes.Split(p, []byte("\n")) {
+		s = bytes.TrimSpace(escapes.ReplaceAll(s, nil))
+		if len(s) == 0 {
+			continue
+		}
+
+		b, err := json.Marshal(map[string]string{"event": "log", "level": l.level, "message": string(s)})
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := fmt.Fprintf(l.writer, "%s\n", b); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/events"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestLogger(t *testing.T) {
+	spec.Run(t, "Logger", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			b *bytes.Buffer
+			f *test.BuildFactory
+		)
+
+		it.Before(func() {
+			b = &bytes.Buffer{}
+			f = test.NewBuildFactory(t)
+		})
+
+		decode := func() []map[string]interface{} {
+			var e []map[string]interface{}
+			for _, l := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+				var m map[string]interface{}
+				g.Expect(json.Unmarshal([]byte(l), &m)).To(gomega.Succeed())
+				e = append(e, m)
+			}
+			return e
+		}
+
+		it("does not write events in text format", func() {
+			l, err := events.NewLogger(f.Build.Logger, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(l.IsJSON()).To(gomega.BeFalse())
+
+			l.Event("test-event", events.Fields{"test-key": "test-value"})
+			g.Expect(b.String()).To(gomega.BeEmpty())
+		})
+
+		it("returns error for invalid format", func() {
+			defer test.ReplaceEnv(t, events.Format, "test-value")()
+
+			_, err := events.NewLogger(f.Build.Logger, b)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("writes events in json format", func() {
+			defer test.ReplaceEnv(t, events.Format, "json")()
+
+			l, err := events.NewLogger(f.Build.Logger, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			l.Event("test-event", events.Fields{"test-key": "test-value"})
+
+			e := decode()
+			g.Expect(e).To(gomega.HaveLen(1))
+			g.Expect(e[0]).To(gomega.HaveKeyWithValue("event", "test-event"))
+			g.Expect(e[0]).To(gomega.HaveKeyWithValue("test-key", "test-value"))
+			g.Expect(e[0]).To(gomega.HaveKey("time"))
+		})
+
+		it("configures build to write text logging as json", func() {
+			defer test.ReplaceEnv(t, events.Format, "json")()
+
+			c, err := events.Configure(f.Build, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			c.Logger.Header("test-header")
+			c.Layers.Layer("test-layer").Logger.Body("test-body")
+
+			g.Expect(decode()).To(gomega.Equal([]map[string]interface{}{
+				{"event": "log", "level": "info", "message": "test-header"},
+				{"event": "log", "level": "info", "message": "test-body"},
+			}))
+		})
+
+		it("configures build to write debug logging as json when log level is DEBUG", func() {
+			defer test.ReplaceEnv(t, events.Format, "json")()
+			defer test.ReplaceEnv(t, events.Level, "DEBUG")()
+
+			c, err := events.Configure(f.Build, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			c.Logger.Debug("test-debug")
+
+			g.Expect(decode()).To(gomega.Equal([]map[string]interface{}{
+				{"event": "log", "level": "debug", "message": "test-debug"},
+			}))
+		})
+
+		it("enables debug logging in text format when log level is DEBUG", func() {
+			defer test.ReplaceEnv(t, events.Level, "DEBUG")()
+
+			c, err := events.Configure(f.Build, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(c.Logger.IsDebugEnabled()).To(gomega.BeTrue())
+			g.Expect(c.Layers.Layer("test-layer").Logger.IsDebugEnabled()).To(gomega.BeTrue())
+		})
+
+		it("writes layer debug logging as json when log level is DEBUG", func() {
+			defer test.ReplaceEnv(t, events.Format, "json")()
+			defer test.ReplaceEnv(t, events.Level, "DEBUG")()
+
+			c, err := events.Configure(f.Build, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(c.Layers.Layer("test-layer").OverrideLaunchEnv("TEST_KEY", "test-value")).To(gomega.Succeed())
+
+			g.Expect(decode()).To(gomega.ContainElement(gomega.And(
+				gomega.HaveKeyWithValue("level", "debug"),
+				gomega.HaveKeyWithValue("message", gomega.ContainSubstring("Writing environment variable")),
+			)))
+		})
+
+		it("enables debug logging when log level is lower case", func() {
+			defer test.ReplaceEnv(t, events.Level, "debug")()
+
+			c, err := events.Configure(f.Build, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(c.Logger.IsDebugEnabled()).To(gomega.BeTrue())
+		})
+
+		it("does not configure build in text format", func() {
+			c, err := events.Configure(f.Build, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(c.Logger).To(gomega.Equal(f.Build.Logger))
+			g.Expect(c.Layers).To(gomega.Equal(f.Build.Layers))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"sync/atomic"
+)
+
+// Progress reports, at debug level, how many of a total number of items have been processed, every interval items
+// and on completion.  It is safe for concurrent use.
+type Progress struct {
+	count    int64
+	interval int64
+	logger   Logger
+	name     string
+	total    int64
+}
+
+// Increment records that an item has been processed.
+func (p *Progress) Increment() {
+	n := atomic.AddInt64(&p.count, 1)
+	if n%p.interval == 0 || n == p.total {
+		p.logger.Debug("%s: %d/%d", p.name, n, p.total)
+	}
+}
+
+// Progress creates a new Progress instance that reports the processing of total items, named by name, every interval
+// items.
+func (l Logger) Progress(name string, total int, interval int) *Progress {
+	if interval < 1 {
+		interval = 1
+	}
+
+	return &Progress{interval: int64(interval), logger: l, name: name, total: int64(total)}
+}
@@ -0,0 +1,49 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events_test
+
+import (
+	"bytes"
+	"testing"
+
+	bpLogger "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/spring-boot-cnb/events"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestProgress(t *testing.T) {
+	spec.Run(t, "Progress", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		it("reports every interval and on completion", func() {
+			b := &bytes.Buffer{}
+			l, err := events.NewLogger(logger.Logger{Logger: bpLogger.NewLogger(b, nil)}, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p := l.Progress("test-name", 5, 2)
+			for i := 0; i < 5; i++ {
+				p.Increment()
+			}
+
+			g.Expect(b.String()).To(gomega.Equal("test-name: 2/5\ntest-name: 4/5\ntest-name: 5/5\n"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
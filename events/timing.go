@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+// StatsDAddress is the environment variable that contains the host:port of a StatsD server that timings are pushed to.
+const StatsDAddress = "BP_SPRING_BOOT_STATSD_ADDRESS"
+
+// Time calls f, recording its duration as a timing named name.
+func (l Logger) Time(name string, f func() error) error {
+	t := time.Now()
+	err := f()
+	l.Timing(name, time.Since(t))
+	return err
+}
+
+// Timing records a duration.  The timing is emitted as an event, written to debug, and pushed to StatsD if
+// BP_SPRING_BOOT_STATSD_ADDRESS is set.
+func (l Logger) Timing(name string, duration time.Duration) {
+	ms := duration.Milliseconds()
+
+	l.Event("timing", Fields{"name": name, "duration-ms": ms})
+	l.Debug("%s took %s", name, duration)
+
+	a, ok := config.Lookup(StatsDAddress)
+	if !ok {
+		return
+	}
+
+	if err := push(a, fmt.Sprintf("spring_boot.%s:%d|ms", strings.ReplaceAll(name, " ", "_"), ms)); err != nil {
+		l.Debug("Unable to push timing to %s: %s", a, err)
+	}
+}
+
+func push(address string, metric string) error {
+	c, err := net.DialTimeout("udp", address, time.Second)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	_, err = c.Write([]byte(metric))
+	return err
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package events_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/events"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestTiming(t *testing.T) {
+	spec.Run(t, "Timing", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			b *bytes.Buffer
+			f *test.BuildFactory
+		)
+
+		it.Before(func() {
+			b = &bytes.Buffer{}
+			f = test.NewBuildFactory(t)
+		})
+
+		it("returns error from timed function", func() {
+			l, err := events.NewLogger(f.Build.Logger, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(l.Time("test-name", func() error {
+				return fmt.Errorf("test-error")
+			})).To(gomega.MatchError("test-error"))
+		})
+
+		it("emits timing event", func() {
+			defer test.ReplaceEnv(t, events.Format, "json")()
+
+			l, err := events.NewLogger(f.Build.Logger, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			l.Timing("test-name", 1500*time.Millisecond)
+
+			var e map[string]interface{}
+			g.Expect(json.Unmarshal(b.Bytes(), &e)).To(gomega.Succeed())
+			g.Expect(e).To(gomega.HaveKeyWithValue("event", "timing"))
+			g.Expect(e).To(gomega.HaveKeyWithValue("name", "test-name"))
+			g.Expect(e).To(gomega.HaveKeyWithValue("duration-ms", float64(1500)))
+		})
+
+		it("pushes timing to StatsD", func() {
+			c, err := net.ListenPacket("udp", "127.0.0.1:0")
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			defer c.Close()
+			defer test.ReplaceEnv(t, events.StatsDAddress, c.LocalAddr().String())()
+
+			l, err := events.NewLogger(f.Build.Logger, b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			l.Timing("test-name", 1500*time.Millisecond)
+
+			p := make([]byte, 1024)
+			g.Expect(c.SetReadDeadline(time.Now().Add(5 * time.Second))).To(gomega.Succeed())
+			n, _, err := c.ReadFrom(p)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(string(p[:n])).To(gomega.Equal("spring_boot.test-name:1500|ms"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
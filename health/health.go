@@ -0,0 +1,129 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// Layer is the name of the layer containing the files that describe the health endpoint.
+	Layer = "health"
+
+	// Prober is the id of the buildpack provided helper that probes the health endpoint.
+	Prober = "health-probe"
+)
+
+// Endpoint describes the actuator health endpoint of an application.  Each field is written to a file of the same name
+// in the health layer, so that orchestration (e.g. Kubernetes probes) can be configured from the image alone.
+type Endpoint struct {
+	// Path is the path of the endpoint, e.g. /actuator/health.
+	Path string `toml:"path"`
+
+	// Port is the port the endpoint listens on.
+	Port string `toml:"port"`
+
+	// Scheme is either http or https.
+	Scheme string `toml:"scheme"`
+}
+
+func (e Endpoint) Identity() (string, string) {
+	return "Health Endpoint", e.URL()
+}
+
+// URL returns the URL of the endpoint on localhost.
+func (e Endpoint) URL() string {
+	return fmt.Sprintf("%s://localhost:%s%s", e.Scheme, e.Port, e.Path)
+}
+
+// Contribute writes the path, port, and scheme files to a layer marked launch.
+func (e Endpoint) Contribute(layer layers.Layer) error {
+	return layer.Contribute(e, func(layer layers.Layer) error {
+		for k, v := range map[string]string{"path": e.Path, "port": e.Port, "scheme": e.Scheme} {
+			if err := helper.WriteFile(filepath.Join(layer.Root, k), 0644, "%s\n", v); err != nil {
+				return err
+			}
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// Probe requests the endpoint, returning an error unless it responds with a 2xx status.
+func (e Endpoint) Probe(timeout time.Duration) error {
+	c := http.Client{Timeout: timeout}
+
+	resp, err := c.Get(e.URL())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s returned %s", e.URL(), resp.Status)
+	}
+
+	return nil
+}
+
+// ReadEndpoint reads an Endpoint from the files in a health layer.
+func ReadEndpoint(root string) (Endpoint, error) {
+	var e Endpoint
+
+	for k, v := range map[string]*string{"path": &e.Path, "port": &e.Port, "scheme": &e.Scheme} {
+		b, err := ioutil.ReadFile(filepath.Join(root, k))
+		if err != nil {
+			return Endpoint{}, err
+		}
+		*v = strings.TrimSpace(string(b))
+	}
+
+	return e, nil
+}
+
+// HealthProbe represents the helper that probes the health endpoint.  It is intended to be run as an exec probe by
+// orchestration and reads the Endpoint from the health layer beside its own layer.
+type HealthProbe struct {
+	layer layers.HelperLayer
+}
+
+// Contribute makes the contribution to launch.
+func (h HealthProbe) Contribute() error {
+	return h.layer.Contribute(func(artifact string, layer layers.HelperLayer) error {
+		layer.Logger.Body("Copying to %s", layer.Root)
+		return helper.CopyFile(artifact, filepath.Join(layer.Root, "bin", Prober))
+	}, layers.Launch)
+}
+
+// NewHealthProbe creates a new HealthProbe instance.
+func NewHealthProbe(layers layers.Layers) HealthProbe {
+	return HealthProbe{layers.HelperLayer(Prober, "Health Probe")}
+}
+
+// Root returns the root of the health layer, given the path of the health probe executable.
+func Root(executable string) string {
+	return filepath.Join(filepath.Dir(filepath.Dir(filepath.Dir(executable))), Layer)
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package health_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/health"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestHealth(t *testing.T) {
+	spec.Run(t, "Health", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("contributes endpoint files", func() {
+			e := health.Endpoint{Path: "/actuator/health", Port: "8081", Scheme: "https"}
+
+			layer := f.Build.Layers.Layer(health.Layer)
+			g.Expect(e.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(filepath.Join(layer.Root, "path")).To(test.HaveContent("/actuator/health\n"))
+			g.Expect(filepath.Join(layer.Root, "port")).To(test.HaveContent("8081\n"))
+			g.Expect(filepath.Join(layer.Root, "scheme")).To(test.HaveContent("https\n"))
+
+			g.Expect(health.ReadEndpoint(layer.Root)).To(gomega.Equal(e))
+		})
+
+		it("contributes health probe", func() {
+			test.TouchFile(t, f.Build.Buildpack.Root, "bin", health.Prober)
+
+			g.Expect(health.NewHealthProbe(f.Build.Layers).Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer(health.Prober)
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(filepath.Join(layer.Root, "bin", health.Prober)).To(gomega.BeARegularFile())
+			g.Expect(health.Root(filepath.Join(layer.Root, "bin", health.Prober))).
+				To(gomega.Equal(f.Build.Layers.Layer(health.Layer).Root))
+		})
+
+		when("Probe", func() {
+
+			var (
+				e      health.Endpoint
+				server *httptest.Server
+				status int
+			)
+
+			it.Before(func() {
+				status = http.StatusOK
+				server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.URL.Path != "/actuator/health" {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					w.WriteHeader(status)
+				}))
+
+				u, err := url.Parse(server.URL)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				e = health.Endpoint{Path: "/actuator/health", Port: u.Port(), Scheme: "http"}
+			})
+
+			it.After(func() {
+				server.Close()
+			})
+
+			it("passes when healthy", func() {
+				g.Expect(e.Probe(time.Second)).To(gomega.Succeed())
+			})
+
+			it("fails when unhealthy", func() {
+				status = http.StatusServiceUnavailable
+
+				g.Expect(e.Probe(time.Second)).To(gomega.MatchError(gomega.ContainSubstring("returned 503")))
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
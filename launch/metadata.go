@@ -0,0 +1,67 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package launch
+
+import (
+	"path/filepath"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+// Metadata is a counterpart to layers.Metadata whose processes can be resolved against those contributed by earlier
+// buildpacks before they are written.
+type Metadata struct {
+	// Processes is a collection of processes.
+	Processes Processes `toml:"processes"`
+
+	// Slices is a collection of slices.
+	Slices layers.Slices `toml:"slices"`
+}
+
+// Processes is a collection of Process instances.
+type Processes []Process
+
+// Process is a counterpart to layers.Process.
+type Process struct {
+	// Type is the type of the process.
+	Type string `toml:"type"`
+
+	// Command is the command of the process.
+	Command string `toml:"command"`
+
+	// Args are arguments to the command.
+	Args []string `toml:"args"`
+
+	// Direct indicates that the command is exec'd directly by the os (no profile.d scripts run).
+	Direct bool `toml:"direct"`
+}
+
+// WriteApplicationMetadata writes application metadata to the filesystem, normalizing the modification time of
+// launch.toml.
+func WriteApplicationMetadata(l layers.Layers, metadata Metadata) error {
+	var p layers.Processes
+	for _, c := range metadata.Processes {
+		p = append(p, layers.Process{Type: c.Type, Command: c.Command, Args: c.Args, Direct: c.Direct})
+	}
+
+	if err := l.WriteApplicationMetadata(layers.Metadata{Processes: p, Slices: metadata.Slices}); err != nil {
+		return err
+	}
+
+	return reproducible.Normalize(filepath.Join(l.Root, "launch.toml"))
+}
@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package launch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/launch"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestMetadata(t *testing.T) {
+	spec.Run(t, "Metadata", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("writes standard metadata", func() {
+			g.Expect(launch.WriteApplicationMetadata(f.Build.Layers, launch.Metadata{
+				Processes: launch.Processes{{Type: "test-type", Command: "test-command"}},
+				Slices:    layers.Slices{{Paths: []string{"test-path"}}},
+			})).To(gomega.Succeed())
+
+			g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(layers.Metadata{
+				Processes: layers.Processes{{Type: "test-type", Command: "test-command"}},
+				Slices:    layers.Slices{{Paths: []string{"test-path"}}},
+			}))
+		})
+
+		it("normalizes modification time of metadata", func() {
+			g.Expect(launch.WriteApplicationMetadata(f.Build.Layers, launch.Metadata{
+				Processes: launch.Processes{{Type: "test-type", Command: "test-command"}},
+			})).To(gomega.Succeed())
+
+			i, err := os.Stat(filepath.Join(f.Build.Layers.Root, "launch.toml"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(i.ModTime()).To(gomega.BeTemporally("==", reproducible.DefaultTime))
+		})
+	}, spec.Report(report.Terminal{}))
+}
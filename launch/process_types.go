@@ -0,0 +1,70 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package launch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+)
+
+// ProcessTypes returns the process types contributed by buildpacks that ran earlier, keyed by type, with the name of
+// the layers directory of the buildpack that contributed them.  Each buildpack writes its launch.toml to its own
+// layers directory beside this buildpack's.
+func ProcessTypes(l layers.Layers) (map[string]string, error) {
+	p := make(map[string]string)
+
+	root := filepath.Dir(l.Root)
+	d, err := ioutil.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return p, nil
+		}
+		return nil, err
+	}
+
+	for _, i := range d {
+		if !i.IsDir() || i.Name() == filepath.Base(l.Root) {
+			continue
+		}
+
+		f := filepath.Join(root, i.Name(), "launch.toml")
+		if _, err := os.Stat(f); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		var m struct {
+			Processes []struct {
+				Type string `toml:"type"`
+			} `toml:"processes"`
+		}
+		if _, err := toml.DecodeFile(f, &m); err != nil {
+			return nil, err
+		}
+
+		for _, c := range m.Processes {
+			p[c.Type] = i.Name()
+		}
+	}
+
+	return p, nil
+}
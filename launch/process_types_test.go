@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package launch_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/launch"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestProcessTypes(t *testing.T) {
+	spec.Run(t, "ProcessTypes", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("returns no process types without other buildpacks", func() {
+			g.Expect(launch.ProcessTypes(f.Build.Layers)).To(gomega.BeEmpty())
+		})
+
+		it("returns process types of other buildpacks", func() {
+			root := filepath.Dir(f.Build.Layers.Root)
+			test.WriteFile(t, filepath.Join(root, "other-buildpack", "launch.toml"), `[[processes]]
+type = "web"
+command = "test-command"
+
+[[processes]]
+type = "worker"
+command = "test-command"
+`)
+			test.WriteFile(t, filepath.Join(f.Build.Layers.Root, "launch.toml"), `[[processes]]
+type = "task"
+command = "test-command"
+`)
+
+			g.Expect(launch.ProcessTypes(f.Build.Layers)).To(gomega.Equal(map[string]string{
+				"web":    "other-buildpack",
+				"worker": "other-buildpack",
+			}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
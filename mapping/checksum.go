@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mapping
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+)
+
+// ChecksumSuffix is the suffix of the dependency-mapping credential, keyed by the SHA256 of a dependency, whose value
+// is the SHA256 of the mapped artifact.  It is required when the mapped artifact differs from the upstream one.
+const ChecksumSuffix = ".sha256"
+
+var sha256Pattern = regexp.MustCompile("^[0-9a-f]{64}$")
+
+// Checksum validates that the dependency declares a SHA256 to verify its artifact against.
+func Checksum(dependency buildpack.Dependency) error {
+	if dependency.SHA256 == "" {
+		return fmt.Errorf("%s declares no sha256 and cannot be verified", dependency.ID)
+	}
+
+	return nil
+}
+
+func userChecksum(dependency buildpack.Dependency, sha256 string) error {
+	if !sha256Pattern.MatchString(sha256) {
+		return fmt.Errorf("invalid %s%s %q for %s: must be 64 lowercase hexadecimal characters",
+			dependency.SHA256, ChecksumSuffix, sha256, dependency.ID)
+	}
+
+	return nil
+}
+
+// Verification returns a descriptive error if err is the failure to verify the artifact of the dependency against its
+// SHA256, otherwise err.
+func Verification(dependency buildpack.Dependency, err error) error {
+	if err == nil || !strings.Contains(err.Error(), "sha256 mismatch") {
+		return err
+	}
+
+	return fmt.Errorf("%s downloaded from %s failed checksum verification.  If it is mirrored, provide the SHA256 of "+
+		"the mirrored artifact as the %s%s credential of a %s binding: %w",
+		dependency.ID, dependency.URI, dependency.SHA256, ChecksumSuffix, Service, err)
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mapping_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/spring-boot-cnb/mapping"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestChecksum(t *testing.T) {
+	spec.Run(t, "Checksum", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		dep := buildpack.Dependency{ID: "test-id", URI: "https://mirror/test.jar", SHA256: "test-sha256"}
+
+		it("accepts dependency with SHA256", func() {
+			g.Expect(mapping.Checksum(dep)).To(gomega.Succeed())
+		})
+
+		it("does not change nil error", func() {
+			g.Expect(mapping.Verification(dep, nil)).To(gomega.Succeed())
+		})
+
+		it("does not change other errors", func() {
+			err := errors.New("could not download: 404")
+			g.Expect(mapping.Verification(dep, err)).To(gomega.Equal(err))
+		})
+
+		it("describes checksum mismatch", func() {
+			err := errors.New("dependency sha256 mismatch: expected sha256 test-sha256, actual sha256 other-sha256")
+
+			actual := mapping.Verification(dep, err)
+			g.Expect(errors.Is(actual, err)).To(gomega.BeTrue())
+			g.Expect(actual).To(gomega.MatchError("test-id downloaded from https://mirror/test.jar failed checksum " +
+				"verification.  If it is mirrored, provide the SHA256 of the mirrored artifact as the " +
+				"test-sha256.sha256 credential of a dependency-mapping binding: dependency sha256 mismatch: expected " +
+				"sha256 test-sha256, actual sha256 other-sha256"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
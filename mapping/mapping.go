@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mapping
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+)
+
+// Service is the filter used to find a binding that maps dependencies to the URIs to download them from.  Each
+// credential of the binding is keyed by the SHA256 of a dependency and has the URI (e.g. an internal mirror) as its
+// value.
+const Service = "dependency-mapping"
+
+// Map returns the dependency with its URI replaced by the URI that a dependency-mapping binding maps its SHA256 to.
+// The SHA256 is unchanged, so the mapped artifact is still verified, unless the binding provides the SHA256 of the
+// mapped artifact.  OK is true if the dependency was mapped.
+func Map(build build.Build, dependency buildpack.Dependency) (buildpack.Dependency, bool, error) {
+	if err := Checksum(dependency); err != nil {
+		return buildpack.Dependency{}, false, err
+	}
+
+	c, ok := build.Services.FindServiceCredentials(Service, dependency.SHA256)
+	if !ok {
+		return dependency, false, nil
+	}
+
+	dependency.URI = fmt.Sprintf("%s", c[dependency.SHA256])
+	build.Logger.Debug("Mapping %s to %s", dependency.ID, dependency.URI)
+
+	if s, ok := c[dependency.SHA256+ChecksumSuffix]; ok {
+		sha256 := fmt.Sprintf("%s", s)
+		if err := userChecksum(dependency, sha256); err != nil {
+			return buildpack.Dependency{}, false, err
+		}
+		dependency.SHA256 = sha256
+	}
+
+	return dependency, true, nil
+}
+
+// Best returns the best candidate dependency for the id and the build's stack, mapped by any dependency-mapping
+// binding.
+func Best(build build.Build, id string) (buildpack.Dependency, bool, error) {
+	deps, err := build.Buildpack.Dependencies()
+	if err != nil {
+		return buildpack.Dependency{}, false, err
+	}
+
+	dep, err := deps.Best(id, "", build.Stack)
+	if err != nil {
+		return buildpack.Dependency{}, false, err
+	}
+
+	return Map(build, dep)
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mapping_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/mapping"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestMapping(t *testing.T) {
+	spec.Run(t, "Mapping", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			dep buildpack.Dependency
+			f   *test.BuildFactory
+		)
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+			dep = buildpack.Dependency{ID: "test-id", URI: "https://upstream/test.jar", SHA256: "test-sha256"}
+		})
+
+		it("does not map without binding", func() {
+			actual, ok, err := mapping.Map(f.Build, dep)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(actual).To(gomega.Equal(dep))
+		})
+
+		it("does not map when binding has no entry for dependency", func() {
+			f.AddService(mapping.Service, map[string]interface{}{"other-sha256": "https://mirror/other.jar"})
+
+			actual, ok, err := mapping.Map(f.Build, dep)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(actual).To(gomega.Equal(dep))
+		})
+
+		it("maps URI", func() {
+			f.AddService(mapping.Service, map[string]interface{}{"test-sha256": "https://mirror/test.jar"})
+
+			actual, ok, err := mapping.Map(f.Build, dep)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(actual.URI).To(gomega.Equal("https://mirror/test.jar"))
+			g.Expect(actual.SHA256).To(gomega.Equal("test-sha256"))
+		})
+
+		it("maps URI from one of several bindings", func() {
+			f.AddService("mapping-1", map[string]interface{}{"other-sha256": "https://mirror/other.jar"}, mapping.Service)
+			f.AddService("mapping-2", map[string]interface{}{"test-sha256": "https://mirror/test.jar"}, mapping.Service)
+
+			actual, ok, err := mapping.Map(f.Build, dep)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(actual.URI).To(gomega.Equal("https://mirror/test.jar"))
+		})
+
+		it("maps SHA256", func() {
+			sha256 := "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+			f.AddService(mapping.Service, map[string]interface{}{
+				"test-sha256":        "https://mirror/test.jar",
+				"test-sha256.sha256": sha256,
+			})
+
+			actual, ok, err := mapping.Map(f.Build, dep)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(actual.SHA256).To(gomega.Equal(sha256))
+		})
+
+		it("returns error for invalid SHA256", func() {
+			f.AddService(mapping.Service, map[string]interface{}{
+				"test-sha256":        "https://mirror/test.jar",
+				"test-sha256.sha256": "test-value",
+			})
+
+			_, _, err := mapping.Map(f.Build, dep)
+			g.Expect(err).To(gomega.MatchError(
+				`invalid test-sha256.sha256 "test-value" for test-id: must be 64 lowercase hexadecimal characters`))
+		})
+
+		it("returns error for dependency without SHA256", func() {
+			dep.SHA256 = ""
+
+			_, _, err := mapping.Map(f.Build, dep)
+			g.Expect(err).To(gomega.MatchError("test-id declares no sha256 and cannot be verified"))
+		})
+
+		it("maps best dependency", func() {
+			f.AddDependency("test-id", "testdata/stub.jar")
+			deps, err := f.Build.Buildpack.Dependencies()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			f.AddService(mapping.Service, map[string]interface{}{deps[0].SHA256: "https://mirror/stub.jar"})
+
+			actual, ok, err := mapping.Best(f.Build, "test-id")
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(actual.URI).To(gomega.Equal("https://mirror/stub.jar"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
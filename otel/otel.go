@@ -0,0 +1,123 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otel
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/mapping"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// Dependency is the id of the OpenTelemetry Java agent dependency.
+	Dependency = "opentelemetry-javaagent"
+
+	// Enabled is the environment variable that enables contribution of the OpenTelemetry Java agent.
+	Enabled = "BP_OTEL_ENABLED"
+
+	// Service is the filter used to find a binding that overrides the OpenTelemetry Java agent of the buildpack.  The
+	// binding must have "uri" and "sha256" credentials and may have a "version" credential.
+	Service = "opentelemetry"
+)
+
+// OpenTelemetry represents the OpenTelemetry Java agent contributed to a Spring Boot application.
+type OpenTelemetry struct {
+	layer layers.DependencyLayer
+}
+
+// Contribute makes the contribution to launch.
+func (o OpenTelemetry) Contribute() error {
+	err := o.layer.Contribute(func(artifact string, layer layers.DependencyLayer) error {
+		layer.Logger.Body("Copying to %s", layer.Root)
+
+		destination := filepath.Join(layer.Root, layer.ArtifactName())
+		if err := helper.CopyFile(artifact, destination); err != nil {
+			return err
+		}
+
+		if err := layer.AppendLaunchEnv("JAVA_OPTS", " -javaagent:%s", destination); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+
+	return mapping.Verification(o.layer.Dependency, err)
+}
+
+// NewOpenTelemetry creates a new OpenTelemetry instance.  OK is true if $BP_OTEL_ENABLED is true.  The agent is taken
+// from an "opentelemetry" binding if one exists, otherwise from the buildpack's dependencies.
+func NewOpenTelemetry(build build.Build) (OpenTelemetry, bool, error) {
+	if ok, err := enabled(); err != nil {
+		return OpenTelemetry{}, false, err
+	} else if !ok {
+		return OpenTelemetry{}, false, nil
+	}
+
+	dep, ok, err := bound(build)
+	if err != nil {
+		return OpenTelemetry{}, false, err
+	}
+
+	if ok {
+		dep, _, err = mapping.Map(build, dep)
+	} else {
+		dep, _, err = mapping.Best(build, Dependency)
+	}
+	if err != nil {
+		return OpenTelemetry{}, false, err
+	}
+
+	return OpenTelemetry{build.Layers.DependencyLayer(dep)}, true, nil
+}
+
+func bound(build build.Build) (buildpack.Dependency, bool, error) {
+	c, ok := build.Services.FindServiceCredentials(Service, "uri", "sha256")
+	if !ok {
+		return buildpack.Dependency{}, false, nil
+	}
+
+	dep := buildpack.Dependency{
+		ID:     Dependency,
+		Name:   "OpenTelemetry Java Agent",
+		URI:    fmt.Sprintf("%s", c["uri"]),
+		SHA256: fmt.Sprintf("%s", c["sha256"]),
+		Stacks: buildpack.Stacks{build.Stack},
+	}
+
+	v := "0.0.0"
+	if s, ok := c["version"]; ok {
+		v = fmt.Sprintf("%s", s)
+	}
+
+	if err := dep.Version.UnmarshalText([]byte(v)); err != nil {
+		return buildpack.Dependency{}, false, fmt.Errorf("invalid %s binding version %s: %w", Service, v, err)
+	}
+
+	return dep, true, nil
+}
+
+func enabled() (bool, error) {
+	return config.LookupBool(Enabled, false)
+}
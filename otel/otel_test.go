@@ -0,0 +1,161 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package otel_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/mapping"
+	"github.com/cloudfoundry/spring-boot-cnb/otel"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestOpenTelemetry(t *testing.T) {
+	spec.Run(t, "OpenTelemetry", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("returns false by default", func() {
+			_, ok, err := otel.NewOpenTelemetry(f.Build)
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns false when disabled", func() {
+			defer test.ReplaceEnv(t, otel.Enabled, "false")()
+
+			_, ok, err := otel.NewOpenTelemetry(f.Build)
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns error for invalid value", func() {
+			defer test.ReplaceEnv(t, otel.Enabled, "test-value")()
+
+			_, _, err := otel.NewOpenTelemetry(f.Build)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("contributes agent from buildpack dependency", func() {
+			defer test.ReplaceEnv(t, otel.Enabled, "true")()
+			f.AddDependency(otel.Dependency, filepath.Join("testdata", "stub-opentelemetry-javaagent.jar"))
+
+			o, ok, err := otel.NewOpenTelemetry(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(o.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer(otel.Dependency)
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(filepath.Join(layer.Root, "stub-opentelemetry-javaagent.jar")).To(gomega.BeARegularFile())
+			g.Expect(layer).To(test.HaveAppendLaunchEnvironment("JAVA_OPTS", " -javaagent:%s",
+				filepath.Join(layer.Root, "stub-opentelemetry-javaagent.jar")))
+		})
+
+		it("normalizes modification times of agent", func() {
+			defer test.ReplaceEnv(t, otel.Enabled, "true")()
+			f.AddDependency(otel.Dependency, filepath.Join("testdata", "stub-opentelemetry-javaagent.jar"))
+
+			o, _, err := otel.NewOpenTelemetry(f.Build)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(o.Contribute()).To(gomega.Succeed())
+
+			i, err := os.Stat(filepath.Join(f.Build.Layers.Layer(otel.Dependency).Root, "stub-opentelemetry-javaagent.jar"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(i.ModTime()).To(gomega.BeTemporally("==", reproducible.DefaultTime))
+		})
+
+		it("contributes agent from binding", func() {
+			defer test.ReplaceEnv(t, otel.Enabled, "true")()
+
+			b, err := ioutil.ReadFile(filepath.Join("testdata", "stub-opentelemetry-javaagent.jar"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			s := sha256.Sum256(b)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(b)
+			}))
+			defer server.Close()
+
+			f.AddService("opentelemetry", map[string]interface{}{
+				"uri":     server.URL + "/bound-javaagent.jar",
+				"sha256":  hex.EncodeToString(s[:]),
+				"version": "1.2.3",
+			})
+
+			o, ok, err := otel.NewOpenTelemetry(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(o.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer(otel.Dependency)
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(filepath.Join(layer.Root, "bound-javaagent.jar")).To(gomega.BeARegularFile())
+			g.Expect(layer).To(test.HaveAppendLaunchEnvironment("JAVA_OPTS", " -javaagent:%s",
+				filepath.Join(layer.Root, "bound-javaagent.jar")))
+		})
+
+		it("contributes agent from dependency-mapping binding", func() {
+			defer test.ReplaceEnv(t, otel.Enabled, "true")()
+
+			b, err := ioutil.ReadFile(filepath.Join("testdata", "stub-opentelemetry-javaagent.jar"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			s := sha256.Sum256(b)
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write(b)
+			}))
+			defer server.Close()
+
+			f.AddService("opentelemetry", map[string]interface{}{
+				"uri":    "https://localhost/bound-javaagent.jar",
+				"sha256": hex.EncodeToString(s[:]),
+			})
+			f.AddService(mapping.Service, map[string]interface{}{hex.EncodeToString(s[:]): server.URL + "/mirrored-javaagent.jar"})
+
+			o, ok, err := otel.NewOpenTelemetry(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(o.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer(otel.Dependency)
+			g.Expect(layer).To(test.HaveAppendLaunchEnvironment("JAVA_OPTS", " -javaagent:%s",
+				filepath.Join(layer.Root, "mirrored-javaagent.jar")))
+		})
+	}, spec.Report(report.Terminal{}))
+}
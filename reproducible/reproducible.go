@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reproducible
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// SourceDateEpoch is the environment variable that overrides the modification time used for contributed files.
+const SourceDateEpoch = "SOURCE_DATE_EPOCH"
+
+// DefaultTime is the modification time used for contributed files when SOURCE_DATE_EPOCH is not set.  It is the
+// earliest time representable in a ZIP archive so that files copied into JARs remain stable as well.
+var DefaultTime = time.Date(1980, time.January, 1, 0, 0, 1, 0, time.UTC)
+
+// Time returns the modification time to use for contributed files.
+func Time() (time.Time, error) {
+	s, ok := os.LookupEnv(SourceDateEpoch)
+	if !ok {
+		return DefaultTime, nil
+	}
+
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(i, 0).UTC(), nil
+}
+
+// Normalize sets the access and modification times of all files and directories in a directory structure to a fixed
+// value so that identical contributions result in byte-identical layers.  Symlinks are not modified.
+func Normalize(root string) error {
+	t, err := Time()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil
+	}
+
+	var dirs []string
+	if err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+
+		if info.IsDir() {
+			dirs = append(dirs, path)
+			return nil
+		}
+
+		return os.Chtimes(path, t, t)
+	}); err != nil {
+		return err
+	}
+
+	// directories are updated deepest first, after their contents, so that the updates are not overwritten
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chtimes(dirs[i], t, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reproducible_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestReproducible(t *testing.T) {
+	spec.Run(t, "Reproducible", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var root string
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "reproducible")
+		})
+
+		modTime := func(path string) time.Time {
+			i, err := os.Stat(path)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			return i.ModTime().UTC()
+		}
+
+		it("normalizes files and directories to default time", func() {
+			test.TouchFile(t, root, "test-directory", "test-file")
+
+			g.Expect(reproducible.Normalize(root)).To(gomega.Succeed())
+
+			g.Expect(modTime(filepath.Join(root, "test-directory", "test-file"))).To(gomega.Equal(reproducible.DefaultTime))
+			g.Expect(modTime(filepath.Join(root, "test-directory"))).To(gomega.Equal(reproducible.DefaultTime))
+			g.Expect(modTime(root)).To(gomega.Equal(reproducible.DefaultTime))
+		})
+
+		it("normalizes to SOURCE_DATE_EPOCH", func() {
+			defer test.ReplaceEnv(t, reproducible.SourceDateEpoch, "1500000000")()
+			test.TouchFile(t, root, "test-file")
+
+			g.Expect(reproducible.Normalize(root)).To(gomega.Succeed())
+
+			g.Expect(modTime(filepath.Join(root, "test-file"))).To(gomega.Equal(time.Unix(1500000000, 0).UTC()))
+		})
+
+		it("returns error for invalid SOURCE_DATE_EPOCH", func() {
+			defer test.ReplaceEnv(t, reproducible.SourceDateEpoch, "test-value")()
+
+			g.Expect(reproducible.Normalize(root)).NotTo(gomega.Succeed())
+		})
+
+		it("ignores missing directories", func() {
+			g.Expect(reproducible.Normalize(filepath.Join(root, "test-directory"))).To(gomega.Succeed())
+		})
+	}, spec.Report(report.Terminal{}))
+}
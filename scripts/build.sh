@@ -9,3 +9,7 @@ fi
 
 GOOS="linux" go build -ldflags='-s -w' -o bin/build build/main.go
 GOOS="linux" go build -ldflags='-s -w' -o bin/detect detect/main.go
+GOOS="linux" go build -ldflags='-s -w' -o bin/application-json-merger cmd/application-json-merger/main.go
+GOOS="linux" go build -ldflags='-s -w' -o bin/bindings-translator cmd/bindings-translator/main.go
+GOOS="linux" go build -ldflags='-s -w' -o bin/classpath-verifier cmd/classpath-verifier/main.go
+GOOS="linux" go build -ldflags='-s -w' -o bin/health-probe cmd/health-probe/main.go
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+const (
+	// AdditionalClassPath is the environment variable that configures additional class path entries.  Entries are
+	// separated by the os.PathListSeparator.
+	AdditionalClassPath = "BP_SPRING_BOOT_ADDITIONAL_CLASSPATH"
+
+	// ClassPathService is the filter used to find a binding that provides additional class path entries.  Each
+	// credential of the binding is a list of entries separated by the os.PathListSeparator.
+	ClassPathService = "classpath"
+)
+
+// NewAdditionalClassPath returns the additional class path entries configured by $BP_SPRING_BOOT_ADDITIONAL_CLASSPATH
+// and a "classpath" binding, in that order.  Relative entries are resolved against the application root.
+func NewAdditionalClassPath(build build.Build) []string {
+	var s []string
+
+	if v, ok := config.Lookup(AdditionalClassPath); ok {
+		s = append(s, v)
+	}
+
+	if c, ok := build.Services.FindServiceCredentials(ClassPathService); ok {
+		var keys []string
+		for k := range c {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			s = append(s, fmt.Sprintf("%s", c[k]))
+		}
+	}
+
+	var entries []string
+	for _, v := range s {
+		for _, e := range filepath.SplitList(v) {
+			if e == "" {
+				continue
+			}
+
+			if !filepath.IsAbs(e) {
+				e = filepath.Join(build.Application.Root, e)
+			}
+
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
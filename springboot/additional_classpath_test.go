@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestAdditionalClassPath(t *testing.T) {
+	spec.Run(t, "AdditionalClassPath", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("returns no entries by default", func() {
+			g.Expect(springboot.NewAdditionalClassPath(f.Build)).To(gomega.BeEmpty())
+		})
+
+		it("returns entries from environment variable", func() {
+			defer test.ReplaceEnv(t, springboot.AdditionalClassPath, strings.Join([]string{
+				"/test-1.jar", "", "test-config",
+			}, string(filepath.ListSeparator)))()
+
+			g.Expect(springboot.NewAdditionalClassPath(f.Build)).To(gomega.Equal([]string{
+				"/test-1.jar",
+				filepath.Join(f.Build.Application.Root, "test-config"),
+			}))
+		})
+
+		it("returns entries from binding in key order", func() {
+			f.AddService("classpath", map[string]interface{}{
+				"b": "/test-2.jar",
+				"a": strings.Join([]string{"/test-1.jar", "/test-3.jar"}, string(filepath.ListSeparator)),
+			})
+
+			g.Expect(springboot.NewAdditionalClassPath(f.Build)).To(gomega.Equal([]string{
+				"/test-1.jar", "/test-3.jar", "/test-2.jar",
+			}))
+		})
+
+		it("returns environment variable entries before binding entries", func() {
+			defer test.ReplaceEnv(t, springboot.AdditionalClassPath, "/test-1.jar")()
+			f.AddService("classpath", map[string]interface{}{"classpath": "/test-2.jar"})
+
+			g.Expect(springboot.NewAdditionalClassPath(f.Build)).To(gomega.Equal([]string{"/test-1.jar", "/test-2.jar"}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+// BannerMode is the environment variable that configures the Spring Boot banner mode.  Valid values are "off",
+// "console", and "log".
+const BannerMode = "BP_SPRING_BOOT_BANNER"
+
+// Banner configures how the Spring Boot banner is displayed at startup.
+type Banner struct {
+	// Mode is the banner mode.
+	Mode string `toml:"mode"`
+}
+
+func (b Banner) Identity() (string, string) {
+	return "Banner", b.Mode
+}
+
+// Contribute appends the banner configuration to $JAVA_OPTS in a layer marked launch.  When the mode is "off", startup
+// information logging is suppressed as well.
+func (b Banner) Contribute(layer layers.Layer) error {
+	return layer.Contribute(b, func(layer layers.Layer) error {
+		opts := fmt.Sprintf(" -Dspring.main.banner-mode=%s", b.Mode)
+		if b.Mode == "off" {
+			opts += " -Dspring.main.log-startup-info=false"
+		}
+
+		if err := layer.AppendLaunchEnv("JAVA_OPTS", "%s", opts); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewBanner creates a new Banner instance.  OK is true if $BP_SPRING_BOOT_BANNER is set.
+func NewBanner() (Banner, bool, error) {
+	s, ok := config.Lookup(BannerMode)
+	if !ok {
+		return Banner{}, false, nil
+	}
+
+	switch s {
+	case "off", "console", "log":
+		return Banner{s}, true, nil
+	default:
+		return Banner{}, false, fmt.Errorf("invalid %s %s: must be one of off, console, or log", BannerMode, s)
+	}
+}
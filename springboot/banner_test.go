@@ -0,0 +1,81 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestBanner(t *testing.T) {
+	spec.Run(t, "Banner", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("returns false by default", func() {
+			_, ok, err := springboot.NewBanner()
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns error for invalid mode", func() {
+			defer test.ReplaceEnv(t, springboot.BannerMode, "test-value")()
+
+			_, _, err := springboot.NewBanner()
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("suppresses banner and startup info when off", func() {
+			defer test.ReplaceEnv(t, springboot.BannerMode, "off")()
+
+			b, ok, err := springboot.NewBanner()
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			layer := f.Build.Layers.Layer("banner")
+			g.Expect(b.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(layer).To(test.HaveAppendLaunchEnvironment("JAVA_OPTS",
+				" -Dspring.main.banner-mode=off -Dspring.main.log-startup-info=false"))
+		})
+
+		it("configures banner mode", func() {
+			defer test.ReplaceEnv(t, springboot.BannerMode, "log")()
+
+			b, ok, err := springboot.NewBanner()
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			layer := f.Build.Layers.Layer("banner")
+			g.Expect(b.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveAppendLaunchEnvironment("JAVA_OPTS", " -Dspring.main.banner-mode=log"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
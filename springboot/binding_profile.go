@@ -0,0 +1,71 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// bindingProfile returns a profile.d script that, unless $guard is set, finds the first binding at launch of a type
+// that has an entry and runs body with $SECRET set to the directory that contains the binding's entries.  Both
+// Kubernetes Service Binding bindings, whose type is in a type file beside the entries, and CNB bindings, whose type is
+// in metadata/kind and entries in secret, are found.  Bindings are read at launch so that secrets are not written to
+// the image.
+func bindingProfile(guard string, kind string, entry string, body ...string) string {
+	var b []string
+	for _, s := range body {
+		for _, l := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+			if l == "" {
+				b = append(b, "")
+			} else {
+				b = append(b, "      "+l)
+			}
+		}
+	}
+
+	return fmt.Sprintf(`BINDINGS="${SERVICE_BINDING_ROOT:-${CNB_BINDINGS:-}}"
+
+if [ -z "${%s:-}" ] && [ -n "${BINDINGS}" ]; then
+  for BINDING in "${BINDINGS}"/*; do
+    if [ -f "${BINDING}/metadata/kind" ]; then
+      TYPE=$(cat "${BINDING}/metadata/kind")
+      SECRET="${BINDING}/secret"
+    else
+      TYPE=$(cat "${BINDING}/type" 2>/dev/null)
+      SECRET="${BINDING}"
+    fi
+
+    if [ "${TYPE}" = "%s" ] && [ -f "${SECRET}/%s" ]; then
+%s
+
+      break
+    fi
+  done
+fi
+`, guard, kind, entry, strings.Join(b, "\n"))
+}
+
+// exportBindingEntry returns a script fragment, for the body of a bindingProfile, that exports an entry of the binding
+// as a variable unless the variable is set or the binding does not have the entry.
+func exportBindingEntry(variable string, entry string) string {
+	return fmt.Sprintf(`if [ -z "${%[1]s:-}" ] && [ -f "${SECRET}/%[2]s" ]; then
+  %[1]s=$(cat "${SECRET}/%[2]s")
+  export %[1]s
+fi
+`, variable, entry)
+}
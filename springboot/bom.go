@@ -0,0 +1,110 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// BOMFile is the name of the file containing the JAR dependencies of an application.
+	BOMFile = "dependencies.json"
+
+	// BOMSchemaVersion is the version of the schema of the BOMFile.
+	BOMSchemaVersion = "1"
+
+	// PlanDependencyLimit is the environment variable that contains the maximum number of dependencies included in
+	// plan metadata.  Above it, plan metadata refers to the BOMFile instead.
+	PlanDependencyLimit = "BP_SPRING_BOOT_PLAN_DEPENDENCY_LIMIT"
+
+	// DefaultPlanDependencyLimit is the default maximum number of dependencies included in plan metadata.
+	DefaultPlanDependencyLimit = 1000
+)
+
+// BOM represents the JAR dependencies of an application in a machine-readable form.
+type BOM struct {
+	// SchemaVersion is the version of the schema of the BOM.
+	SchemaVersion string `json:"schema-version" toml:"schema-version"`
+
+	// Dependencies are the JAR dependencies of the application.
+	Dependencies JARDependencies `json:"dependencies" toml:"dependencies"`
+}
+
+func (b BOM) Identity() (string, string) {
+	return "Dependencies BOM", fmt.Sprintf("(%d dependencies)", len(b.Dependencies))
+}
+
+// Contribute writes the BOM to a layer marked build and launch and exposes its location as $SPRING_BOOT_DEPENDENCIES.
+func (b BOM) Contribute(layer layers.Layer) error {
+	if err := Migrate(layer, b.SchemaVersion); err != nil {
+		return err
+	}
+
+	return layer.Contribute(b, func(layer layers.Layer) error {
+		j, err := json.MarshalIndent(b, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		f := filepath.Join(layer.Root, BOMFile)
+		if err := helper.WriteFile(f, 0644, "%s", j); err != nil {
+			return err
+		}
+
+		if err := layer.OverrideSharedEnv("SPRING_BOOT_DEPENDENCIES", f); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Build, layers.Launch)
+}
+
+// PlanMetadata adds the dependencies to plan metadata, unless there are more than PlanDependencyLimit of them, in which
+// case the path of the BOMFile in layer and the number of dependencies are added instead.
+func (b BOM) PlanMetadata(metadata buildpackplan.Metadata, layer layers.Layer) error {
+	limit, err := config.LookupInt(PlanDependencyLimit, DefaultPlanDependencyLimit)
+	if err != nil {
+		return err
+	}
+
+	if len(b.Dependencies) <= limit {
+		metadata["dependencies"] = b.Dependencies
+		return nil
+	}
+
+	layer.Logger.Body("%d dependencies exceed the plan limit of %d, referring to %s", len(b.Dependencies), limit, BOMFile)
+	metadata["dependencies-file"] = filepath.Join(layer.Root, BOMFile)
+	metadata["dependencies-count"] = len(b.Dependencies)
+	return nil
+}
+
+// NewBOM creates a new BOM instance.
+func NewBOM(dependencies JARDependencies) BOM {
+	if dependencies == nil {
+		dependencies = JARDependencies{}
+	}
+
+	return BOM{BOMSchemaVersion, dependencies}
+}
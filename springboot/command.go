@@ -0,0 +1,87 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+const (
+	// CommandTemplate is the environment variable that configures a template for the launch command.  The template is
+	// a Go text/template with the placeholders {{.StartClass}}, {{.ClassPath}}, {{.Args}}, and {{.ProgramArgs}}.
+	CommandTemplate = "BP_SPRING_BOOT_COMMAND_TEMPLATE"
+
+	// ProgramArgs is the environment variable that, at launch, configures arguments (e.g.
+	// --spring.config.import=configtree:/bindings/) passed to the application after the Start-Class.
+	ProgramArgs = "BPL_SPRING_BOOT_ARGS"
+)
+
+// Command is the data made available to a launch command template.
+type Command struct {
+	// Args are the extra arguments to the JVM.  Expanded from $JAVA_OPTS at launch.
+	Args string
+
+	// ClassPath is the class path of the application.  Expanded from $CLASSPATH at launch.
+	ClassPath string
+
+	// ProgramArgs are the arguments to the application.  Expanded from $BPL_SPRING_BOOT_ARGS at launch.
+	ProgramArgs string
+
+	// StartClass is the Start-Class of the application.
+	StartClass string
+}
+
+// NewCommand returns the launch command for a Spring Boot application.  The command is evaluated by the shell at
+// launch, so that $JAVA_OPTS and $BPL_SPRING_BOOT_ARGS are split on whitespace and are omitted when empty.  If
+// $BP_SPRING_BOOT_COMMAND_TEMPLATE is set, the rendered template is returned instead.
+func NewCommand(metadata Metadata) (string, error) {
+	return newCommand(metadata, "$CLASSPATH")
+}
+
+func newCommand(metadata Metadata, classPath string) (string, error) {
+	c := Command{
+		Args:        "$JAVA_OPTS",
+		ClassPath:   classPath,
+		ProgramArgs: "$" + ProgramArgs,
+		StartClass:  metadata.StartClass,
+	}
+
+	s, ok := config.Lookup(CommandTemplate)
+	if !ok {
+		return fmt.Sprintf("java -cp %s %s %s %s", c.ClassPath, c.Args, c.StartClass, c.ProgramArgs), nil
+	}
+
+	t, err := template.New(CommandTemplate).Option("missingkey=error").Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s %s: %w", CommandTemplate, s, err)
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, c); err != nil {
+		return "", fmt.Errorf("invalid %s %s: %w", CommandTemplate, s, err)
+	}
+
+	if strings.TrimSpace(b.String()) == "" {
+		return "", fmt.Errorf("invalid %s %s: renders an empty command", CommandTemplate, s)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestCommand(t *testing.T) {
+	spec.Run(t, "Command", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		md := springboot.Metadata{StartClass: "test-start-class"}
+
+		// launch evaluates command with the shell, as the launcher does for a process that is not direct, and returns
+		// the arguments that java receives.
+		launch := func(command string, env ...string) []string {
+			bin := test.ScratchDir(t, "bin")
+			test.WriteFileWithPerm(t, filepath.Join(bin, "java"), 0755, "#!/bin/sh\nfor a in \"$@\"; do printf '%%s\\n' \"$a\"; done\n")
+
+			c := exec.Command("sh", "-c", command)
+			c.Env = append([]string{"PATH=" + bin + string(filepath.ListSeparator) + os.Getenv("PATH"), "CLASSPATH=test-class-path"}, env...)
+			out, err := c.Output()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			return strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+		}
+
+		it("returns java command evaluated by the shell by default", func() {
+			command, err := springboot.NewCommand(md)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(command).To(gomega.Equal("java -cp $CLASSPATH $JAVA_OPTS test-start-class $BPL_SPRING_BOOT_ARGS"))
+		})
+
+		it("passes each $JAVA_OPTS flag to java as a separate argument", func() {
+			command, err := springboot.NewCommand(md)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(launch(command, "JAVA_OPTS=-Dtest-1=a -Dtest-2=b")).To(gomega.Equal([]string{
+				"-cp", "test-class-path", "-Dtest-1=a", "-Dtest-2=b", "test-start-class",
+			}))
+		})
+
+		it("passes no argument for empty $JAVA_OPTS", func() {
+			command, err := springboot.NewCommand(md)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(launch(command, "JAVA_OPTS=")).To(gomega.Equal([]string{"-cp", "test-class-path", "test-start-class"}))
+		})
+
+		it("passes each $BPL_SPRING_BOOT_ARGS argument to the application", func() {
+			command, err := springboot.NewCommand(md)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(launch(command, "BPL_SPRING_BOOT_ARGS=--test-1=a --test-2=b")).To(gomega.Equal([]string{
+				"-cp", "test-class-path", "test-start-class", "--test-1=a", "--test-2=b",
+			}))
+		})
+
+		it("passes no argument to the application for unset $BPL_SPRING_BOOT_ARGS", func() {
+			command, err := springboot.NewCommand(md)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(launch(command)).To(gomega.Equal([]string{"-cp", "test-class-path", "test-start-class"}))
+		})
+
+		it("renders template", func() {
+			defer test.ReplaceEnv(t, springboot.CommandTemplate,
+				"test-wrapper java -Djava.security.manager -cp {{.ClassPath}} {{.Args}} {{.StartClass}}")()
+
+			command, err := springboot.NewCommand(md)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(command).To(gomega.Equal("test-wrapper java -Djava.security.manager -cp $CLASSPATH $JAVA_OPTS test-start-class"))
+		})
+
+		it("renders program arguments", func() {
+			defer test.ReplaceEnv(t, springboot.CommandTemplate, "java -cp {{.ClassPath}} {{.StartClass}} {{.ProgramArgs}}")()
+
+			command, err := springboot.NewCommand(md)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(command).To(gomega.Equal("java -cp $CLASSPATH test-start-class $BPL_SPRING_BOOT_ARGS"))
+		})
+
+		it("returns error for invalid template", func() {
+			defer test.ReplaceEnv(t, springboot.CommandTemplate, "java {{.StartClass")()
+
+			_, err := springboot.NewCommand(md)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid BP_SPRING_BOOT_COMMAND_TEMPLATE")))
+		})
+
+		it("returns error for unknown placeholder", func() {
+			defer test.ReplaceEnv(t, springboot.CommandTemplate, "java {{.Unknown}}")()
+
+			_, err := springboot.NewCommand(md)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("returns error for empty command", func() {
+			defer test.ReplaceEnv(t, springboot.CommandTemplate, " ")()
+
+			_, err := springboot.NewCommand(md)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// ConfigDirectory is the directory, relative to the workspace, that is added to the Spring Boot configuration
+	// locations when no binding provides one.
+	ConfigDirectory = "config"
+
+	// ConfigService is the filter used to find a binding that provides the directory external configuration is
+	// mounted at.  The binding must have a location credential.
+	ConfigService = "spring-boot-config"
+
+	// ApplicationProperties is the environment variable that contains properties, in application.properties format,
+	// written to the config location layer so that platforms can inject defaults into every image.
+	ApplicationProperties = "BP_SPRING_APPLICATION_PROPERTIES"
+
+	// ApplicationPropertiesService is the filter used to find a binding that provides properties written to the config
+	// location layer.  The binding must have an application.properties credential.
+	ApplicationPropertiesService = "spring-application-properties"
+)
+
+// ConfigLocation adds an external configuration directory, such as a mounted ConfigMap or Secret, to the locations
+// that Spring Boot reads configuration from.
+type ConfigLocation struct {
+	// Location is the value of $SPRING_CONFIG_ADDITIONAL_LOCATION.
+	Location string `toml:"location"`
+
+	// Properties are injected properties, in application.properties format, written to the layer.
+	Properties string `toml:"properties"`
+}
+
+func (c ConfigLocation) Identity() (string, string) {
+	return "Config Location", c.Location
+}
+
+// Contribute writes a default launch environment variable to a layer marked launch.  A value configured on the running
+// image takes precedence.  Injected properties are written to the layer, whose location precedes Location so that
+// external configuration overrides them.
+func (c ConfigLocation) Contribute(layer layers.Layer) error {
+	return layer.Contribute(c, func(layer layers.Layer) error {
+		l := c.Location
+
+		if c.Properties != "" {
+			if err := helper.WriteFile(filepath.Join(layer.Root, "application.properties"), 0644, "%s", c.Properties); err != nil {
+				return err
+			}
+
+			l = fmt.Sprintf("file:%s/,%s", layer.Root, l)
+		}
+
+		if err := layer.DefaultLaunchEnv("SPRING_CONFIG_ADDITIONAL_LOCATION", l); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewConfigLocation creates a new ConfigLocation instance.  The directory is the location credential of a
+// "spring-boot-config" binding if one exists, otherwise the config directory of the workspace.  Spring Boot 2.4 and
+// later fail to start when a location does not exist, so the location is marked optional for those versions.
+// Properties are those of a "spring-application-properties" binding followed, so that they override them, by those
+// of $BP_SPRING_APPLICATION_PROPERTIES.
+func NewConfigLocation(build build.Build, metadata Metadata) ConfigLocation {
+	d := filepath.Join(build.Application.Root, ConfigDirectory)
+	if c, ok := build.Services.FindServiceCredentials(ConfigService, "location"); ok {
+		d = fmt.Sprintf("%s", c["location"])
+	}
+
+	if !strings.HasSuffix(d, "/") {
+		d += "/"
+	}
+
+	l := fmt.Sprintf("file:%s", d)
+	if metadata.versionMatches(">=2.4") {
+		l = fmt.Sprintf("optional:%s", l)
+	}
+
+	var p []string
+	if c, ok := build.Services.FindServiceCredentials(ApplicationPropertiesService, "application.properties"); ok {
+		p = append(p, fmt.Sprintf("%s", c["application.properties"]))
+	}
+	if v, ok := config.Lookup(ApplicationProperties); ok && v != "" {
+		p = append(p, v)
+	}
+
+	properties := ""
+	for _, v := range p {
+		properties += strings.TrimSuffix(v, "\n") + "\n"
+	}
+
+	return ConfigLocation{l, properties}
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestConfigLocation(t *testing.T) {
+	spec.Run(t, "ConfigLocation", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("uses config directory in workspace", func() {
+			c := springboot.NewConfigLocation(f.Build, springboot.Metadata{Version: "2.3.0.RELEASE"})
+			g.Expect(c.Location).To(gomega.Equal("file:" + filepath.Join(f.Build.Application.Root, "config") + "/"))
+		})
+
+		it("marks location optional for Spring Boot 2.4 and later", func() {
+			c := springboot.NewConfigLocation(f.Build, springboot.Metadata{Version: "2.4.0"})
+			g.Expect(c.Location).To(gomega.Equal("optional:file:" + filepath.Join(f.Build.Application.Root, "config") + "/"))
+		})
+
+		it("uses binding location", func() {
+			f.AddService("spring-boot-config", map[string]interface{}{"location": "/test-config"})
+
+			c := springboot.NewConfigLocation(f.Build, springboot.Metadata{Version: "2.3.0.RELEASE"})
+			g.Expect(c.Location).To(gomega.Equal("file:/test-config/"))
+		})
+
+		it("contributes default launch environment", func() {
+			c := springboot.NewConfigLocation(f.Build, springboot.Metadata{Version: "2.4.0"})
+
+			layer := f.Build.Layers.Layer("config-location")
+			g.Expect(c.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(layer).To(test.HaveDefaultLaunchEnvironment("SPRING_CONFIG_ADDITIONAL_LOCATION", c.Location))
+		})
+
+		it("contributes injected properties", func() {
+			defer test.ReplaceEnv(t, springboot.ApplicationProperties, "logging.pattern.console=%m%n\nmanagement.metrics.export.prometheus.enabled=true")()
+			f.AddService("spring-application-properties", map[string]interface{}{
+				"application.properties": "logging.level.root=WARN\n",
+			})
+
+			c := springboot.NewConfigLocation(f.Build, springboot.Metadata{Version: "2.4.0"})
+			g.Expect(c.Properties).To(gomega.Equal("logging.level.root=WARN\nlogging.pattern.console=%m%n\nmanagement.metrics.export.prometheus.enabled=true\n"))
+
+			layer := f.Build.Layers.Layer("config-location")
+			g.Expect(c.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(filepath.Join(layer.Root, "application.properties")).To(test.HaveContent(c.Properties))
+			g.Expect(layer).To(test.HaveDefaultLaunchEnvironment("SPRING_CONFIG_ADDITIONAL_LOCATION",
+				"file:%s/,%s", layer.Root, c.Location))
+		})
+	}, spec.Report(report.Terminal{}))
+}
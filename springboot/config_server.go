@@ -0,0 +1,70 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+// ConfigServerBindingType is the type of the binding that provides the uri, and optional username and password, of a
+// Spring Cloud Config Server.
+const ConfigServerBindingType = "config-server"
+
+// ConfigServer imports configuration from the Spring Cloud Config Server of a binding at launch.
+type ConfigServer struct {
+	// Type is the type of the binding.
+	Type string `toml:"type"`
+}
+
+func (c ConfigServer) Identity() (string, string) {
+	return "Config Server", c.Type
+}
+
+// Contribute contributes a profile.d script to a layer marked launch that, unless $SPRING_CONFIG_IMPORT is set, sets
+// it to configserver:<uri> for the first binding of Type and exports its username and password as
+// $SPRING_CLOUD_CONFIG_USERNAME and $SPRING_CLOUD_CONFIG_PASSWORD, unless they are set.  Bindings are read at launch so
+// that credentials are not written to the image.
+func (c ConfigServer) Contribute(layer layers.Layer) error {
+	return layer.Contribute(c, func(layer layers.Layer) error {
+		if err := layer.WriteProfile("config-server", "%s", bindingProfile("SPRING_CONFIG_IMPORT", c.Type, "uri",
+			`SPRING_CONFIG_IMPORT="configserver:$(cat "${SECRET}/uri")"
+export SPRING_CONFIG_IMPORT
+`,
+			exportBindingEntry("SPRING_CLOUD_CONFIG_USERNAME", "username"),
+			exportBindingEntry("SPRING_CLOUD_CONFIG_PASSWORD", "password"))); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewConfigServer creates a new ConfigServer instance.  OK is true if the application is Spring Boot 2.4 or later,
+// which supports spring.config.import, and spring-cloud-config-client, which resolves configserver: imports, is a
+// dependency.
+func NewConfigServer(metadata Metadata) (ConfigServer, bool) {
+	if !metadata.versionMatches(">=2.4") {
+		return ConfigServer{}, false
+	}
+
+	if _, ok := FindJARDependency(metadata.ClassPath, "spring-cloud-config-client"); !ok {
+		return ConfigServer{}, false
+	}
+
+	return ConfigServer{Type: ConfigServerBindingType}, true
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestConfigServer(t *testing.T) {
+	spec.Run(t, "Config Server", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		when("NewConfigServer", func() {
+
+			classPath := []string{"/test-lib/spring-cloud-config-client-3.0.0.jar"}
+
+			it("returns false before Spring Boot 2.4", func() {
+				_, ok := springboot.NewConfigServer(springboot.Metadata{Version: "2.3.5.RELEASE", ClassPath: classPath})
+				g.Expect(ok).To(gomega.BeFalse())
+			})
+
+			it("returns false without spring-cloud-config-client", func() {
+				_, ok := springboot.NewConfigServer(springboot.Metadata{Version: "2.4.0"})
+				g.Expect(ok).To(gomega.BeFalse())
+			})
+
+			it("returns true for Spring Boot 2.4 and later with spring-cloud-config-client", func() {
+				c, ok := springboot.NewConfigServer(springboot.Metadata{Version: "2.4.0", ClassPath: classPath})
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(c.Type).To(gomega.Equal(springboot.ConfigServerBindingType))
+			})
+		})
+
+		when("Contribute", func() {
+
+			var script string
+
+			environment := func(env ...string) string {
+				c := exec.Command("sh", "-c", `. "$0" && printf '%s|%s|%s' "${SPRING_CONFIG_IMPORT:-}" "${SPRING_CLOUD_CONFIG_USERNAME:-}" "${SPRING_CLOUD_CONFIG_PASSWORD:-}"`, script)
+				c.Env = env
+				b, err := c.Output()
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				return string(b)
+			}
+
+			it.Before(func() {
+				f := test.NewBuildFactory(t)
+
+				layer := f.Build.Layers.Layer("config-server")
+				g.Expect(springboot.ConfigServer{Type: springboot.ConfigServerBindingType}.Contribute(layer)).To(gomega.Succeed())
+				g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+
+				script = filepath.Join(layer.Root, "profile.d", "config-server")
+			})
+
+			it("exports import and credentials from kubernetes binding", func() {
+				root := test.ScratchDir(t, "bindings")
+				test.WriteFile(t, filepath.Join(root, "other", "type"), "other")
+				test.WriteFile(t, filepath.Join(root, "other", "uri"), "https://other")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "type"), "config-server")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "uri"), "https://config.example.com")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "username"), "test-username")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "password"), "test-password")
+
+				g.Expect(environment("SERVICE_BINDING_ROOT=" + root)).
+					To(gomega.Equal("configserver:https://config.example.com|test-username|test-password"))
+			})
+
+			it("exports import from CNB binding without credentials", func() {
+				root := test.ScratchDir(t, "bindings")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "metadata", "kind"), "config-server")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "secret", "uri"), "https://config.example.com")
+
+				g.Expect(environment("CNB_BINDINGS=" + root)).To(gomega.Equal("configserver:https://config.example.com||"))
+			})
+
+			it("does not replace configured credentials", func() {
+				root := test.ScratchDir(t, "bindings")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "type"), "config-server")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "uri"), "https://config.example.com")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "username"), "test-username")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "password"), "test-password")
+
+				g.Expect(environment("SERVICE_BINDING_ROOT="+root, "SPRING_CLOUD_CONFIG_PASSWORD=configured")).
+					To(gomega.Equal("configserver:https://config.example.com|test-username|configured"))
+			})
+
+			it("does not replace configured import", func() {
+				root := test.ScratchDir(t, "bindings")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "type"), "config-server")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "uri"), "https://config.example.com")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "username"), "test-username")
+
+				g.Expect(environment("SERVICE_BINDING_ROOT="+root, "SPRING_CONFIG_IMPORT=optional:configserver:")).
+					To(gomega.Equal("optional:configserver:||"))
+			})
+
+			it("does not export without bindings", func() {
+				g.Expect(environment()).To(gomega.Equal("||"))
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
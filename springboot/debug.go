@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+// DebugVersion is the version of the profile.d scripts contributed by Debug.
+const DebugVersion = "1"
+
+// Debug contributes profile.d scripts that enable remote debugging ($BPL_DEBUG_ENABLED) and JMX ($BPL_JMX_ENABLED) at
+// launch.
+type Debug struct {
+	// Version is the version of the profile.d scripts.
+	Version string `toml:"version"`
+}
+
+func (d Debug) Identity() (string, string) {
+	return "Debug and JMX", d.Version
+}
+
+// Contribute writes the profile.d scripts to a layer marked launch.
+func (d Debug) Contribute(layer layers.Layer) error {
+	return layer.Contribute(d, func(layer layers.Layer) error {
+		if err := layer.WriteProfile("debug", `if [ "${BPL_DEBUG_ENABLED:-false}" = "true" ]; then
+  DEBUG_PORT=${BPL_DEBUG_PORT:-8000}
+  DEBUG_ADDRESS="*:${DEBUG_PORT}"
+
+  if [ -f "${JAVA_HOME}/release" ] && grep -q 'JAVA_VERSION="1.8' "${JAVA_HOME}/release"; then
+    DEBUG_ADDRESS="${DEBUG_PORT}"
+  fi
+
+  DEBUG_SUSPEND=n
+  if [ "${BPL_DEBUG_SUSPEND:-false}" = "true" ]; then
+    DEBUG_SUSPEND=y
+  fi
+
+  printf "Debugging enabled on port %%s\n" "${DEBUG_PORT}"
+  export JAVA_OPTS="${JAVA_OPTS} -agentlib:jdwp=transport=dt_socket,server=y,address=${DEBUG_ADDRESS},suspend=${DEBUG_SUSPEND}"
+fi
+`); err != nil {
+			return err
+		}
+
+		if err := layer.WriteProfile("jmx", `if [ "${BPL_JMX_ENABLED:-false}" = "true" ]; then
+  JMX_PORT=${BPL_JMX_PORT:-5000}
+
+  printf "JMX enabled on port %%s\n" "${JMX_PORT}"
+  export JAVA_OPTS="${JAVA_OPTS} -Djava.rmi.server.hostname=127.0.0.1 -Dcom.sun.management.jmxremote.authenticate=false -Dcom.sun.management.jmxremote.ssl=false -Dcom.sun.management.jmxremote.port=${JMX_PORT} -Dcom.sun.management.jmxremote.rmi.port=${JMX_PORT}"
+fi
+`); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewDebug creates a new Debug instance.
+func NewDebug() Debug {
+	return Debug{DebugVersion}
+}
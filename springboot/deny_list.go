@@ -0,0 +1,139 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+const (
+	// DenyListEntries is the environment variable that contains denied dependencies, separated by commas.
+	DenyListEntries = "BP_SPRING_BOOT_DENY_LIST"
+
+	// DenyListFile is the environment variable that contains the path to a file of denied dependencies, one per line.
+	DenyListFile = "BP_SPRING_BOOT_DENY_LIST_FILE"
+)
+
+var numeric = regexp.MustCompile(`^[\d]+(\.[\d]+)*`)
+
+// DenyList is a collection of dependencies that are not allowed in an application.  Each entry is of the form
+// <name>[:<version-constraint>], for example log4j-core:<2.17.
+type DenyList []DenyListEntry
+
+// DenyListEntry is a single entry in a DenyList.
+type DenyListEntry struct {
+	// Name is the name of the denied dependency.
+	Name string
+
+	// Constraint is the version constraint of the denied dependency.  A nil constraint denies all versions.
+	Constraint *semver.Constraints
+
+	raw string
+}
+
+// Check returns an error listing every dependency that matches an entry in the deny list.
+func (d DenyList) Check(dependencies JARDependencies) error {
+	var m []string
+
+	for _, dep := range dependencies {
+		for _, e := range d {
+			if e.Matches(dep) {
+				m = append(m, fmt.Sprintf("%s %s (sha256 %s) matches %s", dep.Name, dep.Version, dep.SHA256, e.raw))
+			}
+		}
+	}
+
+	if len(m) > 0 {
+		return fmt.Errorf("%d denied dependencies found:\n%s", len(m), strings.Join(m, "\n"))
+	}
+
+	return nil
+}
+
+// Matches returns true if a dependency matches this entry.  Versions that cannot be interpreted numerically only match
+// entries without a constraint.
+func (e DenyListEntry) Matches(dependency JARDependency) bool {
+	if e.Name != dependency.Name {
+		return false
+	}
+
+	if e.Constraint == nil {
+		return true
+	}
+
+	n := numeric.FindString(dependency.Version)
+	if n == "" {
+		return false
+	}
+
+	v, err := semver.NewVersion(n)
+	if err != nil {
+		return false
+	}
+
+	return e.Constraint.Check(v)
+}
+
+// NewDenyList creates a new DenyList from the BP_SPRING_BOOT_DENY_LIST and BP_SPRING_BOOT_DENY_LIST_FILE environment
+// variables.
+func NewDenyList() (DenyList, error) {
+	var raw []string
+
+	if s, ok := config.Lookup(DenyListEntries); ok {
+		raw = append(raw, strings.Split(s, ",")...)
+	}
+
+	if f, ok := config.Lookup(DenyListFile); ok {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", DenyListFile, err)
+		}
+
+		raw = append(raw, strings.Split(string(b), "\n")...)
+	}
+
+	var d DenyList
+	for _, r := range raw {
+		r = strings.TrimSpace(r)
+		if r == "" || strings.HasPrefix(r, "#") {
+			continue
+		}
+
+		e := DenyListEntry{raw: r}
+
+		if i := strings.Index(r, ":"); i < 0 {
+			e.Name = r
+		} else {
+			c, err := semver.NewConstraint(r[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid deny list entry %s: %w", r, err)
+			}
+
+			e.Name, e.Constraint = r[:i], c
+		}
+
+		d = append(d, e)
+	}
+
+	return d, nil
+}
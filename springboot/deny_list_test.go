@@ -0,0 +1,89 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestDenyList(t *testing.T) {
+	spec.Run(t, "DenyList", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		dependencies := springboot.JARDependencies{
+			{Name: "log4j-core", Version: "2.14.1", SHA256: "test-sha256-1"},
+			{Name: "spring-core", Version: "5.2.4.RELEASE", SHA256: "test-sha256-2"},
+		}
+
+		it("is empty when not configured", func() {
+			d, err := springboot.NewDenyList()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(d).To(gomega.BeEmpty())
+			g.Expect(d.Check(dependencies)).To(gomega.Succeed())
+		})
+
+		it("denies all versions of name-only entries", func() {
+			defer test.ReplaceEnv(t, springboot.DenyListEntries, "test-name, spring-core")()
+
+			d, err := springboot.NewDenyList()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(d.Check(dependencies)).To(gomega.MatchError(gomega.ContainSubstring("spring-core 5.2.4.RELEASE")))
+		})
+
+		it("denies versions matching constraint", func() {
+			defer test.ReplaceEnv(t, springboot.DenyListEntries, "log4j-core:<2.17")()
+
+			d, err := springboot.NewDenyList()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(d.Check(dependencies)).To(gomega.MatchError(gomega.ContainSubstring("log4j-core 2.14.1")))
+		})
+
+		it("allows versions not matching constraint", func() {
+			defer test.ReplaceEnv(t, springboot.DenyListEntries, "log4j-core:<2.14,spring-core:<5.2")()
+
+			d, err := springboot.NewDenyList()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(d.Check(dependencies)).To(gomega.Succeed())
+		})
+
+		it("reads deny list file", func() {
+			f := filepath.Join(test.ScratchDir(t, "deny-list"), "deny-list")
+			test.WriteFile(t, f, "# comment\n\nlog4j-core:<2.17\n")
+			defer test.ReplaceEnv(t, springboot.DenyListFile, f)()
+
+			d, err := springboot.NewDenyList()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(d).To(gomega.HaveLen(1))
+			g.Expect(d.Check(dependencies)).NotTo(gomega.Succeed())
+		})
+
+		it("returns error for invalid constraint", func() {
+			defer test.ReplaceEnv(t, springboot.DenyListEntries, "log4j-core:<>x")()
+
+			_, err := springboot.NewDenyList()
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// Dev is the environment variable that enables Spring Boot DevTools restarts in the built image.
+	Dev = "BP_SPRING_BOOT_DEV"
+
+	// DevToolsLayer is the name of the layer that contains the TriggerFile.
+	DevToolsLayer = "devtools"
+
+	// TriggerFile is the name of the file that triggers a DevTools restart when it is modified.
+	TriggerFile = ".reloadtrigger"
+)
+
+// DevTools configures Spring Boot DevTools to restart the application when TriggerFile, written to the DevToolsLayer,
+// is modified.  The layer is on the class path, as DevTools only watches class path directories for the trigger file,
+// and is separate from Spring-Boot-Classes, so that the trigger file is not hidden when a volume of classes is
+// mounted over them.  Tools such as Tilt and Skaffold sync changed classes into the running container and then touch
+// the trigger file, exposed as $SPRING_BOOT_TRIGGER_FILE.
+type DevTools struct {
+	// TriggerFile is the name of the file that triggers a restart.
+	TriggerFile string `toml:"trigger-file"`
+}
+
+func (d DevTools) Identity() (string, string) {
+	return "Spring Boot DevTools", ""
+}
+
+// Contribute writes the trigger file and configures the restart in a layer marked launch.
+func (d DevTools) Contribute(layer layers.Layer) error {
+	return layer.Contribute(d, func(layer layers.Layer) error {
+		f := filepath.Join(layer.Root, d.TriggerFile)
+		if err := helper.WriteFile(f, 0644, ""); err != nil {
+			return err
+		}
+
+		if err := layer.OverrideLaunchEnv("SPRING_BOOT_TRIGGER_FILE", f); err != nil {
+			return err
+		}
+
+		if err := layer.AppendLaunchEnv("JAVA_OPTS", " -Dspring.devtools.restart.enabled=true -Dspring.devtools.restart.trigger-file=%s",
+			d.TriggerFile); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewDevTools creates a new DevTools instance.  OK is true if $BP_SPRING_BOOT_DEV is true.
+func NewDevTools() (DevTools, bool, error) {
+	e, err := devEnabled()
+	if err != nil {
+		return DevTools{}, false, err
+	}
+
+	return DevTools{TriggerFile}, e, nil
+}
+
+func devEnabled() (bool, error) {
+	return config.LookupBool(Dev, false)
+}
+
+// keepDevTools returns exclusions without the globs that exclude the spring-boot-devtools JARs of a class path, so that
+// DevTools stays in the application and on the class path.
+func keepDevTools(root string, classPath []string, exclusions Exclusions, logger logger.Logger) Exclusions {
+	for _, p := range classPath {
+		if m := pattern.FindStringSubmatch(p); m == nil || m[1] != "spring-boot-devtools" {
+			continue
+		}
+
+		r, err := filepath.Rel(root, p)
+		if err != nil || strings.HasPrefix(r, "..") || !exclusions.Excluded(r) {
+			continue
+		}
+
+		logger.BodyWarning("Not excluding %s, as %s is true", r, Dev)
+		exclusions = exclusions.Without(r)
+	}
+
+	return exclusions
+}
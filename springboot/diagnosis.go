@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"path/filepath"
+
+	"github.com/buildpacks/libbuildpack/v2/application"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+)
+
+// Reason describes why an application is not a runnable Spring Boot application.
+type Reason string
+
+const (
+	// NoManifest indicates that the application has no META-INF/MANIFEST.MF.
+	NoManifest Reason = "no META-INF/MANIFEST.MF"
+
+	// NoStartClass indicates that the manifest has no Start-Class and none could be discovered.
+	NoStartClass Reason = "no Start-Class in META-INF/MANIFEST.MF"
+
+	// NoVersion indicates that the manifest has no Spring-Boot-Version.
+	NoVersion Reason = "no Spring-Boot-Version in META-INF/MANIFEST.MF"
+
+	// NotExploded indicates that the application contains a JAR or WAR that has not been exploded.
+	NotExploded Reason = "application contains an archive that has not been exploded; set $BP_SPRING_BOOT_BUILT_ARTIFACT"
+)
+
+// Diagnose returns the reason that an application is not a runnable Spring Boot application.  OK is false if the
+// application is a runnable Spring Boot application.
+func Diagnose(application application.Application, logger logger.Logger) (Reason, bool, error) {
+	if ok, err := helper.FileExists(filepath.Join(application.Root, "META-INF", "MANIFEST.MF")); err != nil {
+		return "", false, err
+	} else if !ok {
+		if _, ok, err := NewDistribution(application.Root); err != nil {
+			return "", false, err
+		} else if ok {
+			return "", false, nil
+		}
+
+		a, err := filepath.Glob(filepath.Join(application.Root, "*.[jw]ar"))
+		if err != nil {
+			return "", false, err
+		}
+
+		if len(a) > 0 {
+			return NotExploded, true, nil
+		}
+
+		return NoManifest, true, nil
+	}
+
+	md, ok, err := NewMetadata(application, logger)
+	if err != nil {
+		return "", false, err
+	} else if !ok {
+		return NoVersion, true, nil
+	}
+
+	if md.StartClass == "" {
+		return NoStartClass, true, nil
+	}
+
+	return "", false, nil
+}
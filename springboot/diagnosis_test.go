@@ -0,0 +1,97 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestDiagnosis(t *testing.T) {
+	spec.Run(t, "Diagnosis", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.DetectFactory
+
+		it.Before(func() {
+			f = test.NewDetectFactory(t)
+		})
+
+		it("diagnoses missing manifest", func() {
+			r, ok, err := springboot.Diagnose(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(r).To(gomega.Equal(springboot.NoManifest))
+		})
+
+		it("diagnoses archive that has not been exploded", func() {
+			test.TouchFile(t, f.Detect.Application.Root, "test.jar")
+
+			r, ok, err := springboot.Diagnose(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(r).To(gomega.Equal(springboot.NotExploded))
+		})
+
+		it("returns false for distribution", func() {
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "bin", "test"), `CLASSPATH=$APP_HOME/lib/test-boot.jar
+exec java -classpath "$CLASSPATH" org.springframework.boot.loader.JarLauncher "$@"
+`)
+			test.TouchFile(t, f.Detect.Application.Root, "lib", "test-boot.jar")
+
+			_, ok, err := springboot.Diagnose(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("diagnoses missing Spring-Boot-Version", func() {
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"), "Main-Class: test-main-class")
+
+			r, ok, err := springboot.Diagnose(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(r).To(gomega.Equal(springboot.NoVersion))
+		})
+
+		it("diagnoses missing Start-Class", func() {
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"), "Spring-Boot-Version: test-version")
+
+			r, ok, err := springboot.Diagnose(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(r).To(gomega.Equal(springboot.NoStartClass))
+		})
+
+		it("returns false for Spring Boot application", func() {
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			_, ok, err := springboot.Diagnose(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+	}, spec.Report(report.Terminal{}))
+}
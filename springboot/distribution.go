@@ -0,0 +1,204 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+var (
+	distributionClassPath = regexp.MustCompile(`(?m)^CLASSPATH=(.+)$`)
+	distributionMainClass = regexp.MustCompile(`-classpath\s+\S*CLASSPATH\S*\s+(?:\\\s+)?([A-Za-z_$][\w$]*(?:\.[A-Za-z_$][\w$]*)+)`)
+	springBootJAR         = regexp.MustCompile(`^spring-boot-([\d]+\.[\d]+[\w.-]*)\.jar$`)
+)
+
+// Distribution represents an application packaged as a distribution by the Gradle application plugin, with start
+// scripts in bin/ and JARs in lib/.
+type Distribution struct {
+	// ClassPath is the class path of the start script, in order.
+	ClassPath []string
+
+	// MainClass is the main class of the start script.
+	MainClass string
+
+	// Script is the start script.
+	Script string
+
+	// Version is the Spring Boot version of the distribution, unless its class path is a single executable JAR.
+	Version string
+}
+
+// Executable returns true if the distribution's class path is a single Spring Boot executable JAR (e.g. one produced
+// by bootStartScripts) launched by the Spring Boot loader.
+func (d Distribution) Executable() bool {
+	return len(d.ClassPath) == 1 && strings.HasPrefix(d.MainClass, loaderPackage)
+}
+
+// Contribute lays the distribution out as an exploded Spring Boot application in layer.  An executable JAR is
+// exploded.  Otherwise, the JARs of the class path are copied to lib/, ordered by a classpath.idx as in the start
+// script, and a manifest is written with the Spring-Boot-Version and a Start-Class of the main class.
+func (d Distribution) Contribute(layer layers.Layer) error {
+	h := make(map[string]string, len(d.ClassPath))
+	for _, j := range d.ClassPath {
+		s, err := hash(j)
+		if err != nil {
+			return err
+		}
+		h[filepath.Base(j)] = s
+	}
+
+	return layer.Contribute(distributionMetadata{d.Script, d.MainClass, h}, func(layer layers.Layer) error {
+		if err := os.RemoveAll(layer.Root); err != nil {
+			return err
+		}
+
+		if d.Executable() {
+			layer.Logger.Body("Expanding %s to %s", filepath.Base(d.ClassPath[0]), layer.Root)
+			if err := extractArchive(d.ClassPath[0], layer.Root); err != nil {
+				return err
+			}
+
+			return reproducible.Normalize(layer.Root)
+		}
+
+		layer.Logger.Body("Copying %d JARs to %s", len(d.ClassPath), layer.Root)
+
+		var index []string
+		for _, j := range d.ClassPath {
+			n := filepath.Base(j)
+			if err := helper.CopyFile(j, filepath.Join(layer.Root, "lib", n)); err != nil {
+				return err
+			}
+			index = append(index, fmt.Sprintf("- \"lib/%s\"", n))
+		}
+
+		if err := helper.WriteFile(filepath.Join(layer.Root, "classpath.idx"), 0644, "%s\n",
+			strings.Join(index, "\n")); err != nil {
+			return err
+		}
+
+		if err := helper.WriteFile(filepath.Join(layer.Root, "META-INF", "MANIFEST.MF"), 0644, strings.Join([]string{
+			"Manifest-Version: 1.0",
+			"Spring-Boot-Version: %s",
+			"Start-Class: %s",
+			"Spring-Boot-Lib: lib/",
+			"Spring-Boot-Classpath-Index: classpath.idx",
+			"",
+		}, "\n"), d.Version, d.MainClass); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Build, layers.Cache, layers.Launch)
+}
+
+type distributionMetadata struct {
+	Script    string            `toml:"script"`
+	MainClass string            `toml:"main-class"`
+	JARs      map[string]string `toml:"jars"`
+}
+
+func (d distributionMetadata) Identity() (string, string) {
+	return "Distribution", filepath.Base(d.Script)
+}
+
+// NewDistribution creates a new Distribution instance from the start scripts in bin/ of root.  OK is true if a start
+// script has a class path in lib/ and a main class, and its class path contains a Spring Boot executable JAR or
+// Spring Boot itself.
+func NewDistribution(root string) (Distribution, bool, error) {
+	if ok, err := helper.FileExists(filepath.Join(root, "META-INF", "MANIFEST.MF")); err != nil {
+		return Distribution{}, false, err
+	} else if ok {
+		return Distribution{}, false, nil
+	}
+
+	s, err := filepath.Glob(filepath.Join(root, "bin", "*"))
+	if err != nil {
+		return Distribution{}, false, err
+	}
+	sort.Strings(s)
+
+	for _, f := range s {
+		if filepath.Ext(f) == ".bat" {
+			continue
+		}
+
+		if i, err := os.Stat(f); err != nil {
+			return Distribution{}, false, err
+		} else if i.IsDir() {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return Distribution{}, false, err
+		}
+
+		d, ok, err := parseStartScript(root, f, string(b))
+		if err != nil {
+			return Distribution{}, false, err
+		} else if ok {
+			return d, true, nil
+		}
+	}
+
+	return Distribution{}, false, nil
+}
+
+func parseStartScript(root string, script string, content string) (Distribution, bool, error) {
+	c := distributionClassPath.FindStringSubmatch(content)
+	m := distributionMainClass.FindStringSubmatch(content)
+	if c == nil || m == nil {
+		return Distribution{}, false, nil
+	}
+
+	d := Distribution{MainClass: m[1], Script: script}
+
+	for _, e := range strings.Split(strings.Trim(strings.TrimSpace(c[1]), `"`), ":") {
+		if !strings.HasPrefix(e, "$APP_HOME/") {
+			return Distribution{}, false, nil
+		}
+
+		p := filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(e, "$APP_HOME/")))
+		if ok, err := helper.FileExists(p); err != nil {
+			return Distribution{}, false, err
+		} else if !ok {
+			return Distribution{}, false, fmt.Errorf("start script %s references %s, which does not exist", script, e)
+		}
+		d.ClassPath = append(d.ClassPath, p)
+
+		if v := springBootJAR.FindStringSubmatch(filepath.Base(p)); v != nil && d.Version == "" {
+			d.Version = v[1]
+		}
+	}
+
+	if !d.Executable() && d.Version == "" {
+		return Distribution{}, false, nil
+	}
+
+	return d, true, nil
+}
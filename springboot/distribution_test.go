@@ -0,0 +1,159 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestDistribution(t *testing.T) {
+	spec.Run(t, "Distribution", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("returns false without start scripts", func() {
+			_, ok, err := springboot.NewDistribution(f.Build.Application.Root)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("returns false with a manifest", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"), "Spring-Boot-Version: test-version")
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "bin", "test"), `CLASSPATH=$APP_HOME/lib/test.jar
+exec java -classpath "$CLASSPATH" org.springframework.boot.loader.JarLauncher "$@"
+`)
+			test.TouchFile(t, f.Build.Application.Root, "lib", "test.jar")
+
+			_, ok, err := springboot.NewDistribution(f.Build.Application.Root)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("returns false without Spring Boot", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "bin", "test"), `CLASSPATH=$APP_HOME/lib/test.jar
+exec java -classpath "$CLASSPATH" test.Main "$@"
+`)
+			test.TouchFile(t, f.Build.Application.Root, "lib", "test.jar")
+
+			_, ok, err := springboot.NewDistribution(f.Build.Application.Root)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("parses Gradle 6 start script", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "bin", "test.bat"), "%s", `set CLASSPATH=%APP_HOME%\lib\test.jar`)
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "bin", "test"), `APP_HOME="`+"`pwd -P`"+`"
+CLASSPATH=$APP_HOME/lib/test-1.0.0.jar:$APP_HOME/lib/spring-boot-2.7.0.jar:$APP_HOME/lib/spring-boot-autoconfigure-2.7.0.jar
+
+eval set -- $DEFAULT_JVM_OPTS $JAVA_OPTS $TEST_OPTS -classpath "\"$CLASSPATH\"" test.Application "$APP_ARGS"
+`)
+			test.TouchFile(t, f.Build.Application.Root, "lib", "test-1.0.0.jar")
+			test.TouchFile(t, f.Build.Application.Root, "lib", "spring-boot-2.7.0.jar")
+			test.TouchFile(t, f.Build.Application.Root, "lib", "spring-boot-autoconfigure-2.7.0.jar")
+
+			d, ok, err := springboot.NewDistribution(f.Build.Application.Root)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(d).To(gomega.Equal(springboot.Distribution{
+				ClassPath: []string{
+					filepath.Join(f.Build.Application.Root, "lib", "test-1.0.0.jar"),
+					filepath.Join(f.Build.Application.Root, "lib", "spring-boot-2.7.0.jar"),
+					filepath.Join(f.Build.Application.Root, "lib", "spring-boot-autoconfigure-2.7.0.jar"),
+				},
+				MainClass: "test.Application",
+				Script:    filepath.Join(f.Build.Application.Root, "bin", "test"),
+				Version:   "2.7.0",
+			}))
+			g.Expect(d.Executable()).To(gomega.BeFalse())
+		})
+
+		it("parses Gradle 7 boot start script", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "bin", "test"), `CLASSPATH=$APP_HOME/lib/test-boot.jar
+
+set -- \
+        "-Dorg.gradle.appname=$APP_BASE_NAME" \
+        -classpath "$CLASSPATH" \
+        org.springframework.boot.loader.JarLauncher \
+        "$@"
+`)
+			test.TouchFile(t, f.Build.Application.Root, "lib", "test-boot.jar")
+
+			d, ok, err := springboot.NewDistribution(f.Build.Application.Root)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(d.MainClass).To(gomega.Equal("org.springframework.boot.loader.JarLauncher"))
+			g.Expect(d.Executable()).To(gomega.BeTrue())
+		})
+
+		it("returns error if class path entry does not exist", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "bin", "test"), `CLASSPATH=$APP_HOME/lib/test.jar
+exec java -classpath "$CLASSPATH" test.Main "$@"
+`)
+
+			_, _, err := springboot.NewDistribution(f.Build.Application.Root)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("references $APP_HOME/lib/test.jar, which does not exist")))
+		})
+
+		it("lays out distribution as exploded application", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "bin", "test"), `CLASSPATH=$APP_HOME/lib/test-1.0.0.jar:$APP_HOME/lib/spring-boot-2.7.0.jar
+exec java -classpath "$CLASSPATH" test.Application "$@"
+`)
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "lib", "test-1.0.0.jar"), "test-1")
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "lib", "spring-boot-2.7.0.jar"), "test-2")
+
+			d, ok, err := springboot.NewDistribution(f.Build.Application.Root)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+
+			layer := f.Build.Layers.Layer("application")
+			g.Expect(d.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(true, true, true))
+			g.Expect(filepath.Join(layer.Root, "lib", "test-1.0.0.jar")).To(test.HaveContent("test-1"))
+			g.Expect(filepath.Join(layer.Root, "lib", "spring-boot-2.7.0.jar")).To(test.HaveContent("test-2"))
+			g.Expect(filepath.Join(layer.Root, "classpath.idx")).To(test.HaveContent(`- "lib/test-1.0.0.jar"
+- "lib/spring-boot-2.7.0.jar"
+`))
+
+			a := f.Build.Application
+			a.Root = layer.Root
+			md, ok, err := springboot.NewMetadata(a, f.Build.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(md.Version).To(gomega.Equal("2.7.0"))
+			g.Expect(md.StartClass).To(gomega.Equal("test.Application"))
+			g.Expect(md.ClassPath).To(gomega.Equal([]string{
+				layer.Root,
+				filepath.Join(layer.Root, "lib", "test-1.0.0.jar"),
+				filepath.Join(layer.Root, "lib", "spring-boot-2.7.0.jar"),
+			}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,131 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"archive/zip"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+const (
+	// DuplicateClassesEnabled is the environment variable that enables detection of classes that appear in multiple
+	// JARs.
+	DuplicateClassesEnabled = "BP_SPRING_BOOT_DUPLICATE_CLASSES"
+
+	// DuplicateClassesLimit is the maximum number of conflicting JAR groups reported.
+	DuplicateClassesLimit = 10
+)
+
+// DuplicateClasses is a collection of conflicts, each a group of JARs that contain the same classes, ordered by the
+// number of classes in conflict.
+type DuplicateClasses []DuplicateClassConflict
+
+// DuplicateClassConflict is a group of JARs that each contain the same classes.
+type DuplicateClassConflict struct {
+	// Classes are the fully-qualified names of the classes that appear in every JAR.
+	Classes []string
+
+	// JARs are the names of the JARs that contain the classes.
+	JARs []string
+}
+
+// String returns a one line description of the conflict.
+func (d DuplicateClassConflict) String() string {
+	return fmt.Sprintf("%d classes duplicated in %s (e.g. %s)", len(d.Classes), strings.Join(d.JARs, ", "), d.Classes[0])
+}
+
+// FindDuplicateClasses returns classes that appear in more than one of a collection of JARs.  module-info and
+// multi-release versions of classes are ignored.
+func FindDuplicateClasses(jars []string) (DuplicateClasses, error) {
+	owners := make(map[string][]string)
+
+	for _, j := range jars {
+		if filepath.Ext(j) != ".jar" {
+			continue
+		}
+
+		classes, err := classes(j)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read %s: %w", j, err)
+		}
+
+		for _, c := range classes {
+			owners[c] = append(owners[c], filepath.Base(j))
+		}
+	}
+
+	groups := make(map[string]*DuplicateClassConflict)
+	for c, o := range owners {
+		if len(o) < 2 {
+			continue
+		}
+
+		sort.Strings(o)
+		k := strings.Join(o, "\x00")
+
+		g, ok := groups[k]
+		if !ok {
+			g = &DuplicateClassConflict{JARs: o}
+			groups[k] = g
+		}
+		g.Classes = append(g.Classes, c)
+	}
+
+	var d DuplicateClasses
+	for _, g := range groups {
+		sort.Strings(g.Classes)
+		d = append(d, *g)
+	}
+
+	sort.Slice(d, func(i, j int) bool {
+		if len(d[i].Classes) != len(d[j].Classes) {
+			return len(d[i].Classes) > len(d[j].Classes)
+		}
+		return strings.Join(d[i].JARs, ",") < strings.Join(d[j].JARs, ",")
+	})
+
+	return d, nil
+}
+
+func classes(jar string) ([]string, error) {
+	z, err := zip.OpenReader(jar)
+	if err != nil {
+		return nil, err
+	}
+	defer z.Close()
+
+	var c []string
+	for _, f := range z.File {
+		if !strings.HasSuffix(f.Name, ".class") || strings.HasPrefix(f.Name, "META-INF/") ||
+			strings.HasSuffix(f.Name, "module-info.class") {
+			continue
+		}
+
+		c = append(c, strings.ReplaceAll(strings.TrimSuffix(f.Name, ".class"), "/", "."))
+	}
+
+	return c, nil
+}
+
+func duplicateClassesEnabled() (bool, error) {
+	return config.LookupBool(DuplicateClassesEnabled, false)
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestDuplicateClasses(t *testing.T) {
+	spec.Run(t, "DuplicateClasses", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var root string
+
+		jar := func(name string, entries ...string) string {
+			t.Helper()
+
+			p := filepath.Join(root, name)
+			f, err := os.Create(p)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			defer f.Close()
+
+			z := zip.NewWriter(f)
+			for _, e := range entries {
+				_, err := z.Create(e)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			}
+			g.Expect(z.Close()).To(gomega.Succeed())
+
+			return p
+		}
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "duplicate-classes")
+		})
+
+		it("returns no conflicts for distinct classes", func() {
+			g.Expect(springboot.FindDuplicateClasses([]string{
+				filepath.Join(root, "test-classes"),
+				jar("test-1.jar", "a/A.class", "module-info.class"),
+				jar("test-2.jar", "b/B.class", "module-info.class", "META-INF/versions/9/a/A.class"),
+			})).To(gomega.BeEmpty())
+		})
+
+		it("groups and orders conflicts", func() {
+			d, err := springboot.FindDuplicateClasses([]string{
+				jar("test-1.jar", "a/A.class", "a/B.class", "c/C.class"),
+				jar("test-2.jar", "a/A.class", "a/B.class"),
+				jar("test-3.jar", "c/C.class"),
+			})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(d).To(gomega.Equal(springboot.DuplicateClasses{
+				{Classes: []string{"a.A", "a.B"}, JARs: []string{"test-1.jar", "test-2.jar"}},
+				{Classes: []string{"c.C"}, JARs: []string{"test-1.jar", "test-3.jar"}},
+			}))
+			g.Expect(d[0].String()).To(gomega.Equal("2 classes duplicated in test-1.jar, test-2.jar (e.g. a.A)"))
+		})
+
+		it("returns error for invalid JAR", func() {
+			test.WriteFile(t, filepath.Join(root, "test.jar"), "test")
+
+			_, err := springboot.FindDuplicateClasses([]string{filepath.Join(root, "test.jar")})
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"path/filepath"
+)
+
+const (
+	// DefaultPort is the port an embedded server listens on when server.port is not configured.
+	DefaultPort = "8080"
+
+	// ServerLabel is the label that identifies the embedded server of an application.
+	ServerLabel = "org.springframework.boot.server"
+
+	// ServerVersionLabel is the label that contains the version of the embedded server of an application.
+	ServerVersionLabel = "org.springframework.boot.server.version"
+)
+
+// EmbeddedServer describes the embedded web server of a Spring Boot application.
+type EmbeddedServer struct {
+	// Name is the name of the server: tomcat, jetty, undertow, or netty.
+	Name string `mapstructure:"name"`
+
+	// Port is the server.port configured in application.properties, or DefaultPort.
+	Port string `mapstructure:"port"`
+
+	// Version is the version of the server.
+	Version string `mapstructure:"version"`
+}
+
+type server struct {
+	name       string
+	dependency string
+}
+
+var (
+	servletServers = []server{
+		{"tomcat", "tomcat-embed-core"},
+		{"jetty", "jetty-server"},
+		{"undertow", "undertow-core"},
+	}
+
+	netty = server{"netty", "reactor-netty"}
+)
+
+// NewEmbeddedServer creates a new EmbeddedServer instance.  When more than one server is present, the server that
+// Spring Boot auto-configures is chosen: Netty for reactive applications, and then Tomcat, Jetty, and Undertow.  OK is
+// false if no embedded server is present.
+func NewEmbeddedServer(root string, metadata Metadata) (EmbeddedServer, bool, error) {
+	candidates := servletServers
+	if w, ok := NewWebApplicationType(metadata); ok && w.Type == Reactive {
+		candidates = append([]server{netty}, servletServers...)
+	}
+
+	for _, c := range candidates {
+		v, ok := FindJARDependency(metadata.ClassPath, c.dependency)
+		if !ok {
+			continue
+		}
+
+		p, ok, err := readProperty(filepath.Join(root, metadata.Classes, "application.properties"), "server.port")
+		if err != nil {
+			return EmbeddedServer{}, false, err
+		} else if !ok {
+			p = DefaultPort
+		}
+
+		return EmbeddedServer{Name: c.name, Port: p, Version: v}, true, nil
+	}
+
+	return EmbeddedServer{}, false, nil
+}
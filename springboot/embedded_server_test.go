@@ -0,0 +1,79 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestEmbeddedServer(t *testing.T) {
+	spec.Run(t, "EmbeddedServer", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var root string
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "embedded-server")
+		})
+
+		it("returns false without an embedded server", func() {
+			_, ok, err := springboot.NewEmbeddedServer(root, springboot.Metadata{})
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("detects server and version with default port", func() {
+			e, ok, err := springboot.NewEmbeddedServer(root, springboot.Metadata{
+				ClassPath: []string{"/test-lib/tomcat-embed-core-9.0.31.jar"},
+			})
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(e).To(gomega.Equal(springboot.EmbeddedServer{Name: "tomcat", Port: springboot.DefaultPort, Version: "9.0.31"}))
+		})
+
+		it("prefers netty for reactive applications", func() {
+			e, ok, err := springboot.NewEmbeddedServer(root, springboot.Metadata{ClassPath: []string{
+				"/test-lib/reactor-netty-0.9.5.RELEASE.jar",
+				"/test-lib/spring-webflux-5.2.4.RELEASE.jar",
+				"/test-lib/undertow-core-2.0.29.Final.jar",
+			}})
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(e.Name).To(gomega.Equal("netty"))
+			g.Expect(e.Version).To(gomega.Equal("0.9.5.RELEASE"))
+		})
+
+		it("reads server.port from application.properties", func() {
+			test.WriteFile(t, filepath.Join(root, "test-classes", "application.properties"), "server.port=9090\n")
+
+			e, _, err := springboot.NewEmbeddedServer(root, springboot.Metadata{
+				Classes:   "test-classes",
+				ClassPath: []string{"/test-lib/jetty-server-9.4.27.v20200227.jar"},
+			})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(e).To(gomega.Equal(springboot.EmbeddedServer{Name: "jetty", Port: "9090", Version: "9.4.27.v20200227"}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// EncryptionConfigServer indicates that encrypted properties are decrypted by a Spring Cloud Config Server.
+	EncryptionConfigServer = "config-server"
+
+	// EncryptionJasypt indicates that encrypted properties are decrypted by jasypt-spring-boot.
+	EncryptionJasypt = "jasypt"
+
+	// EncryptionLabel is the label that contains the mechanisms that decrypt encrypted properties of an
+	// application.
+	EncryptionLabel = "org.springframework.boot.encryption"
+
+	// JasyptBindingType is the type of the binding whose password entry is exposed as $JASYPT_ENCRYPTOR_PASSWORD at
+	// launch.
+	JasyptBindingType = "jasypt"
+)
+
+// Encryption represents the mechanisms that decrypt encrypted properties of an application, so that platforms know to
+// provision passwords or Config Server bindings.
+type Encryption struct {
+	// Mechanisms are the mechanisms, ordered by name.
+	Mechanisms []string `toml:"mechanisms"`
+}
+
+func (e Encryption) Identity() (string, string) {
+	return "Property Encryption", strings.Join(e.Mechanisms, ", ")
+}
+
+// Contribute contributes a profile.d script to a layer marked launch that, for jasypt, exports the password entry of
+// a jasypt binding as $JASYPT_ENCRYPTOR_PASSWORD at launch, unless it is already set.  The password is read at launch
+// so that it is not written to the image.
+func (e Encryption) Contribute(layer layers.Layer) error {
+	return layer.Contribute(e, func(layer layers.Layer) error {
+		if err := layer.WriteProfile("jasypt", "%s", bindingProfile("JASYPT_ENCRYPTOR_PASSWORD", JasyptBindingType,
+			"password", exportBindingEntry("JASYPT_ENCRYPTOR_PASSWORD", "password"))); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// Jasypt returns true if jasypt-spring-boot decrypts properties.
+func (e Encryption) Jasypt() bool {
+	for _, m := range e.Mechanisms {
+		if m == EncryptionJasypt {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewEncryption creates a new Encryption instance.  OK is true if jasypt-spring-boot or spring-cloud-config-client is
+// a dependency.
+func NewEncryption(metadata Metadata) (Encryption, bool) {
+	var e Encryption
+
+	if _, ok := FindJARDependency(metadata.ClassPath, "spring-cloud-config-client"); ok {
+		e.Mechanisms = append(e.Mechanisms, EncryptionConfigServer)
+	}
+
+	if _, ok := FindJARDependency(metadata.ClassPath, "jasypt-spring-boot"); ok {
+		e.Mechanisms = append(e.Mechanisms, EncryptionJasypt)
+	}
+
+	return e, len(e.Mechanisms) > 0
+}
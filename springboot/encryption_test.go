@@ -0,0 +1,118 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestEncryption(t *testing.T) {
+	spec.Run(t, "Encryption", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		when("NewEncryption", func() {
+
+			it("returns false without jasypt-spring-boot or spring-cloud-config-client", func() {
+				_, ok := springboot.NewEncryption(springboot.Metadata{ClassPath: []string{"/test-lib/spring-core-5.2.4.RELEASE.jar"}})
+				g.Expect(ok).To(gomega.BeFalse())
+			})
+
+			it("returns mechanisms", func() {
+				e, ok := springboot.NewEncryption(springboot.Metadata{ClassPath: []string{
+					"/test-lib/jasypt-spring-boot-3.0.3.jar",
+					"/test-lib/spring-cloud-config-client-2.2.2.RELEASE.jar",
+				}})
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(e.Mechanisms).To(gomega.Equal([]string{springboot.EncryptionConfigServer, springboot.EncryptionJasypt}))
+				g.Expect(e.Jasypt()).To(gomega.BeTrue())
+			})
+
+			it("does not return jasypt for config-client only", func() {
+				e, ok := springboot.NewEncryption(springboot.Metadata{ClassPath: []string{
+					"/test-lib/spring-cloud-config-client-2.2.2.RELEASE.jar",
+				}})
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(e.Jasypt()).To(gomega.BeFalse())
+			})
+		})
+
+		when("Contribute", func() {
+
+			var (
+				f      *test.BuildFactory
+				script string
+			)
+
+			password := func(env ...string) string {
+				c := exec.Command("sh", "-c", `. "$0" && printf '%s' "${JASYPT_ENCRYPTOR_PASSWORD:-}"`, script)
+				c.Env = env
+				b, err := c.Output()
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				return string(b)
+			}
+
+			it.Before(func() {
+				f = test.NewBuildFactory(t)
+
+				l := f.Build.Layers.Layer("encryption")
+				g.Expect(springboot.Encryption{Mechanisms: []string{springboot.EncryptionJasypt}}.Contribute(l)).To(gomega.Succeed())
+				g.Expect(l).To(test.HaveLayerMetadata(false, false, true))
+
+				script = filepath.Join(l.Root, "profile.d", "jasypt")
+			})
+
+			it("exports password from kubernetes binding", func() {
+				root := test.ScratchDir(t, "bindings")
+				test.WriteFile(t, filepath.Join(root, "other", "type"), "other")
+				test.WriteFile(t, filepath.Join(root, "other", "password"), "other-password")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "type"), "jasypt")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "password"), "test-password")
+
+				g.Expect(password("SERVICE_BINDING_ROOT=" + root)).To(gomega.Equal("test-password"))
+			})
+
+			it("exports password from CNB binding", func() {
+				root := test.ScratchDir(t, "bindings")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "metadata", "kind"), "jasypt")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "secret", "password"), "test-password")
+
+				g.Expect(password("CNB_BINDINGS=" + root)).To(gomega.Equal("test-password"))
+			})
+
+			it("does not replace configured password", func() {
+				root := test.ScratchDir(t, "bindings")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "type"), "jasypt")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "password"), "test-password")
+
+				g.Expect(password("SERVICE_BINDING_ROOT="+root, "JASYPT_ENCRYPTOR_PASSWORD=configured")).To(gomega.Equal("configured"))
+			})
+
+			it("does not export password without bindings", func() {
+				g.Expect(password()).To(gomega.BeEmpty())
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+// EurekaBindingType is the type of the binding that provides the uri, and optional client-id, client-secret, and
+// access-token-uri, of a Eureka service registry.
+const EurekaBindingType = "eureka"
+
+// Eureka registers the application with the Eureka service registry of a binding at launch, as spring-cloud-bindings
+// does for applications that do not depend on it.
+type Eureka struct {
+	// Type is the type of the binding.
+	Type string `toml:"type"`
+}
+
+func (e Eureka) Identity() (string, string) {
+	return "Eureka", e.Type
+}
+
+// Contribute contributes a profile.d script to a layer marked launch that, unless
+// $EUREKA_CLIENT_SERVICEURL_DEFAULTZONE is set, sets it to <uri>/eureka/ for the first binding of Type, defaults
+// $EUREKA_CLIENT_REGION to default, and exports its client-id, client-secret, and access-token-uri as
+// $EUREKA_CLIENT_OAUTH2_CLIENTID, $EUREKA_CLIENT_OAUTH2_CLIENTSECRET, and $EUREKA_CLIENT_OAUTH2_ACCESSTOKENURI, unless
+// they are set.
+func (e Eureka) Contribute(layer layers.Layer) error {
+	return layer.Contribute(e, func(layer layers.Layer) error {
+		if err := layer.WriteProfile("eureka", "%s", bindingProfile("EUREKA_CLIENT_SERVICEURL_DEFAULTZONE", e.Type, "uri",
+			`EUREKA_CLIENT_SERVICEURL_DEFAULTZONE="$(cat "${SECRET}/uri")/eureka/"
+export EUREKA_CLIENT_SERVICEURL_DEFAULTZONE
+export EUREKA_CLIENT_REGION="${EUREKA_CLIENT_REGION:-default}"
+`,
+			exportBindingEntry("EUREKA_CLIENT_OAUTH2_CLIENTID", "client-id"),
+			exportBindingEntry("EUREKA_CLIENT_OAUTH2_CLIENTSECRET", "client-secret"),
+			exportBindingEntry("EUREKA_CLIENT_OAUTH2_ACCESSTOKENURI", "access-token-uri"))); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewEureka creates a new Eureka instance.  OK is true if spring-cloud-netflix-eureka-client is a dependency and
+// spring-cloud-bindings, which registers with Eureka itself, is not.
+func NewEureka(metadata Metadata) (Eureka, bool) {
+	if _, ok := FindJARDependency(metadata.ClassPath, "spring-cloud-netflix-eureka-client"); !ok {
+		return Eureka{}, false
+	}
+
+	if _, ok := FindJARDependency(metadata.ClassPath, "spring-cloud-bindings"); ok {
+		return Eureka{}, false
+	}
+
+	return Eureka{Type: EurekaBindingType}, true
+}
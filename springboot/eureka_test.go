@@ -0,0 +1,113 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestEureka(t *testing.T) {
+	spec.Run(t, "Eureka", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		when("NewEureka", func() {
+
+			it("returns false without spring-cloud-netflix-eureka-client", func() {
+				_, ok := springboot.NewEureka(springboot.Metadata{ClassPath: []string{"/test-lib/spring-core-5.2.4.RELEASE.jar"}})
+				g.Expect(ok).To(gomega.BeFalse())
+			})
+
+			it("returns false with spring-cloud-bindings", func() {
+				_, ok := springboot.NewEureka(springboot.Metadata{ClassPath: []string{
+					"/test-lib/spring-cloud-bindings-1.6.0.jar",
+					"/test-lib/spring-cloud-netflix-eureka-client-2.2.5.RELEASE.jar",
+				}})
+				g.Expect(ok).To(gomega.BeFalse())
+			})
+
+			it("returns true with spring-cloud-netflix-eureka-client", func() {
+				e, ok := springboot.NewEureka(springboot.Metadata{ClassPath: []string{
+					"/test-lib/spring-cloud-netflix-eureka-client-2.2.5.RELEASE.jar",
+				}})
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(e.Type).To(gomega.Equal(springboot.EurekaBindingType))
+			})
+		})
+
+		when("Contribute", func() {
+
+			var script string
+
+			environment := func(env ...string) string {
+				c := exec.Command("sh", "-c", `. "$0" && printf '%s|%s|%s|%s|%s' "${EUREKA_CLIENT_SERVICEURL_DEFAULTZONE:-}" "${EUREKA_CLIENT_REGION:-}" "${EUREKA_CLIENT_OAUTH2_CLIENTID:-}" "${EUREKA_CLIENT_OAUTH2_CLIENTSECRET:-}" "${EUREKA_CLIENT_OAUTH2_ACCESSTOKENURI:-}"`, script)
+				c.Env = env
+				b, err := c.Output()
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				return string(b)
+			}
+
+			it.Before(func() {
+				f := test.NewBuildFactory(t)
+
+				layer := f.Build.Layers.Layer("eureka")
+				g.Expect(springboot.Eureka{Type: springboot.EurekaBindingType}.Contribute(layer)).To(gomega.Succeed())
+				g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+
+				script = filepath.Join(layer.Root, "profile.d", "eureka")
+			})
+
+			it("exports service url and credentials from binding", func() {
+				root := test.ScratchDir(t, "bindings")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "type"), "eureka")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "uri"), "https://eureka.example.com")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "client-id"), "test-client-id")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "client-secret"), "test-client-secret")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "access-token-uri"), "https://uaa.example.com/oauth/token")
+
+				g.Expect(environment("SERVICE_BINDING_ROOT=" + root)).To(gomega.Equal(
+					"https://eureka.example.com/eureka/|default|test-client-id|test-client-secret|https://uaa.example.com/oauth/token"))
+			})
+
+			it("does not replace configured region", func() {
+				root := test.ScratchDir(t, "bindings")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "metadata", "kind"), "eureka")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "secret", "uri"), "https://eureka.example.com")
+
+				g.Expect(environment("CNB_BINDINGS="+root, "EUREKA_CLIENT_REGION=test-region")).
+					To(gomega.Equal("https://eureka.example.com/eureka/|test-region|||"))
+			})
+
+			it("does not replace configured service url", func() {
+				root := test.ScratchDir(t, "bindings")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "type"), "eureka")
+				test.WriteFile(t, filepath.Join(root, "test-binding", "uri"), "https://eureka.example.com")
+
+				g.Expect(environment("SERVICE_BINDING_ROOT="+root, "EUREKA_CLIENT_SERVICEURL_DEFAULTZONE=https://configured/eureka/")).
+					To(gomega.Equal("https://configured/eureka/||||"))
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+const (
+	// ExcludePatterns is the environment variable that contains globs, separated by commas, of paths removed from the
+	// application and excluded from slices and the class path.
+	ExcludePatterns = "BP_SPRING_BOOT_EXCLUDE_PATTERNS"
+
+	// IgnoreFile is the name of the file, in the root of the workspace, containing globs of paths removed from the
+	// application and excluded from slices and the class path, one per line.
+	IgnoreFile = ".cnbignore"
+)
+
+// Exclusions are globs, relative to the application root, of paths removed from the application and excluded from
+// slices and the class path.  A glob that matches a directory excludes everything in it.
+type Exclusions []string
+
+// Excluded returns true if a path, relative to the application root, or any of its parent directories matches a glob.
+func (e Exclusions) Excluded(path string) bool {
+	for p := filepath.Clean(path); p != "." && p != string(filepath.Separator); p = filepath.Dir(p) {
+		for _, g := range e {
+			if ok, _ := filepath.Match(g, p); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Without returns the globs that exclude neither a path, relative to the application root, nor any of its parent
+// directories.
+func (e Exclusions) Without(path string) Exclusions {
+	var w Exclusions
+	for _, g := range e {
+		if !(Exclusions{g}).Excluded(path) {
+			w = append(w, g)
+		}
+	}
+
+	return w
+}
+
+// ClassPath returns the entries of a class path that are not excluded.  Entries outside of root are never excluded.
+func (e Exclusions) ClassPath(root string, classPath []string) []string {
+	if len(e) == 0 {
+		return classPath
+	}
+
+	var c []string
+	for _, p := range classPath {
+		if r, err := filepath.Rel(root, p); err == nil && !strings.HasPrefix(r, "..") && e.Excluded(r) {
+			continue
+		}
+		c = append(c, p)
+	}
+
+	return c
+}
+
+// Remove removes the paths below root that are excluded, so that they are not in the image.
+func (e Exclusions) Remove(root string) error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		r, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if r == "." || !e.Excluded(r) {
+			return nil
+		}
+
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// NewExclusions creates a new Exclusions instance from the BP_SPRING_BOOT_EXCLUDE_PATTERNS environment variable and
+// the IgnoreFile in the workspace.
+func NewExclusions(workspace string) (Exclusions, error) {
+	var raw []string
+
+	if s, ok := config.Lookup(ExcludePatterns); ok {
+		raw = append(raw, strings.Split(s, ",")...)
+	}
+
+	f := filepath.Join(workspace, IgnoreFile)
+	if exists, err := helper.FileExists(f); err != nil {
+		return nil, err
+	} else if exists {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		raw = append(raw, strings.Split(string(b), "\n")...)
+	}
+
+	var e Exclusions
+	for _, r := range raw {
+		r = strings.TrimSpace(r)
+		if r == "" || strings.HasPrefix(r, "#") {
+			continue
+		}
+
+		r = strings.TrimSuffix(strings.TrimPrefix(r, "/"), "/")
+		if _, err := filepath.Match(r, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %s: %w", r, err)
+		}
+
+		e = append(e, r)
+	}
+
+	return e, nil
+}
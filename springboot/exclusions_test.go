@@ -0,0 +1,98 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestExclusions(t *testing.T) {
+	spec.Run(t, "Exclusions", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var root string
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "exclusions")
+		})
+
+		it("returns no exclusions by default", func() {
+			g.Expect(springboot.NewExclusions(root)).To(gomega.BeEmpty())
+		})
+
+		it("reads environment variable and ignore file", func() {
+			defer test.ReplaceEnv(t, springboot.ExcludePatterns, "test-1, /test-2/")()
+			test.WriteFile(t, filepath.Join(root, springboot.IgnoreFile), "# comment\n\ntest-3\n*.tmp\n")
+
+			g.Expect(springboot.NewExclusions(root)).To(gomega.Equal(springboot.Exclusions{"test-1", "test-2", "test-3", "*.tmp"}))
+		})
+
+		it("returns error for invalid pattern", func() {
+			defer test.ReplaceEnv(t, springboot.ExcludePatterns, "[")()
+
+			_, err := springboot.NewExclusions(root)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("excludes matching paths and their contents", func() {
+			e := springboot.Exclusions{"test-dir", "test-lib/*-SNAPSHOT.jar"}
+
+			g.Expect(e.Excluded("test-dir")).To(gomega.BeTrue())
+			g.Expect(e.Excluded("test-dir/test-file")).To(gomega.BeTrue())
+			g.Expect(e.Excluded("test-lib/test-1.2.3-SNAPSHOT.jar")).To(gomega.BeTrue())
+			g.Expect(e.Excluded("test-lib/test-1.2.3.jar")).To(gomega.BeFalse())
+			g.Expect(e.Excluded("other/test-dir")).To(gomega.BeFalse())
+		})
+
+		it("returns globs that do not exclude a path", func() {
+			e := springboot.Exclusions{"test-dir", "test-lib/*.jar", "*.tmp"}
+
+			g.Expect(e.Without("test-lib/test-1.2.3.jar")).To(gomega.Equal(springboot.Exclusions{"test-dir", "*.tmp"}))
+			g.Expect(e.Without("test-dir/test-file")).To(gomega.Equal(springboot.Exclusions{"test-lib/*.jar", "*.tmp"}))
+		})
+
+		it("removes excluded paths", func() {
+			test.TouchFile(t, root, "test-dir", "test-file")
+			test.TouchFile(t, root, "test-lib", "test-1.2.3-SNAPSHOT.jar")
+			test.TouchFile(t, root, "test-lib", "test-1.2.3.jar")
+
+			g.Expect(springboot.Exclusions{"test-dir", "test-lib/*-SNAPSHOT.jar"}.Remove(root)).To(gomega.Succeed())
+
+			g.Expect(filepath.Join(root, "test-dir")).NotTo(gomega.BeAnExistingFile())
+			g.Expect(filepath.Join(root, "test-lib", "test-1.2.3-SNAPSHOT.jar")).NotTo(gomega.BeAnExistingFile())
+			g.Expect(filepath.Join(root, "test-lib", "test-1.2.3.jar")).To(gomega.BeAnExistingFile())
+		})
+
+		it("filters class path", func() {
+			e := springboot.Exclusions{"test-lib/excluded.jar"}
+
+			g.Expect(e.ClassPath(root, []string{
+				filepath.Join(root, "test-classes"),
+				filepath.Join(root, "test-lib", "excluded.jar"),
+				"/other/test-lib/excluded.jar",
+			})).To(gomega.Equal([]string{filepath.Join(root, "test-classes"), "/other/test-lib/excluded.jar"}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
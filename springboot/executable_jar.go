@@ -0,0 +1,137 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+)
+
+// maxLaunchScript is the largest launch script, prepended to a fully executable JAR, that is searched for the start of
+// the archive.
+const maxLaunchScript = 1024 * 1024
+
+var localFileHeader = []byte("PK\x03\x04")
+
+// launchScriptLength returns the length of the launch script (e.g. the one embedded by Spring Boot's executable
+// repackaging) that precedes the archive in a fully executable JAR.  OK is false if the file does not start with a
+// script.
+func launchScriptLength(file string) (int64, bool, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, false, err
+	}
+	defer f.Close()
+
+	b, err := ioutil.ReadAll(io.LimitReader(f, maxLaunchScript))
+	if err != nil {
+		return 0, false, err
+	}
+
+	if !bytes.HasPrefix(b, []byte("#!")) {
+		return 0, false, nil
+	}
+
+	i := bytes.Index(b, localFileHeader)
+	if i < 0 {
+		return 0, false, nil
+	}
+
+	return int64(i), true, nil
+}
+
+type archive struct {
+	*zip.Reader
+	io.Closer
+}
+
+// openArchive opens a JAR, including a fully executable JAR whose entry offsets are relative to the end of its launch
+// script rather than to the start of the file.
+func openArchive(file string) (archive, error) {
+	z, err := zip.OpenReader(file)
+	if err == nil {
+		return archive{&z.Reader, z}, nil
+	} else if err != zip.ErrFormat {
+		return archive{}, err
+	}
+
+	n, ok, lErr := launchScriptLength(file)
+	if lErr != nil {
+		return archive{}, lErr
+	} else if !ok {
+		return archive{}, err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return archive{}, err
+	}
+
+	i, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return archive{}, err
+	}
+
+	r, err := zip.NewReader(io.NewSectionReader(f, n, i.Size()-n), i.Size()-n)
+	if err != nil {
+		_ = f.Close()
+		return archive{}, err
+	}
+
+	return archive{r, f}, nil
+}
+
+// extractArchive extracts a JAR, ignoring the launch script of a fully executable JAR, to destination.
+func extractArchive(source string, destination string) error {
+	a, err := openArchive(source)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	for _, f := range a.File {
+		target := filepath.Join(destination, filepath.FromSlash(path.Clean("/"+f.Name)))
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+
+		err = helper.WriteFileFromReader(target, f.Mode(), r)
+		_ = r.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+// FingerprintDatabase is the environment variable that contains the path to a JSON file of class fingerprints, used to
+// identify libraries shaded into JARs that cannot be identified by their name.
+const FingerprintDatabase = "BP_SPRING_BOOT_FINGERPRINT_DATABASE"
+
+// Fingerprint identifies the library that contains a class file with a given SHA256.
+type Fingerprint struct {
+	// Name is the name of the library.
+	Name string `json:"name"`
+
+	// Version is the version of the library.
+	Version string `json:"version"`
+
+	// SHA256 is the SHA256 of a class file in the library.
+	SHA256 string `json:"sha256"`
+}
+
+// Fingerprints maps the SHA256 of class files to the library that contains them.
+type Fingerprints map[string]Fingerprint
+
+// Identify returns the libraries whose classes are in a JAR, each with NestedIn set to the name of the JAR.  Files that
+// are not JARs contain no libraries.
+func (f Fingerprints) Identify(path string) (JARDependencies, error) {
+	z, err := zip.OpenReader(path)
+	if err == zip.ErrFormat {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer z.Close()
+
+	found := make(map[JARDependency]bool)
+	for _, e := range z.File {
+		if filepath.Ext(e.Name) != ".class" {
+			continue
+		}
+
+		h, err := hashEntry(e)
+		if err != nil {
+			return nil, err
+		}
+
+		if p, ok := f[h]; ok {
+			found[JARDependency{Name: p.Name, Version: p.Version, NestedIn: filepath.Base(path)}] = true
+		}
+	}
+
+	var d JARDependencies
+	for k := range found {
+		d = append(d, k)
+	}
+	sort.Sort(d)
+
+	return d, nil
+}
+
+// NewFingerprints creates a new Fingerprints instance from the file named by BP_SPRING_BOOT_FINGERPRINT_DATABASE, a
+// JSON array of Fingerprint objects.  OK is false if BP_SPRING_BOOT_FINGERPRINT_DATABASE is not set.
+func NewFingerprints() (Fingerprints, bool, error) {
+	p, ok := config.Lookup(FingerprintDatabase)
+	if !ok {
+		return nil, false, nil
+	}
+
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to read %s: %w", FingerprintDatabase, err)
+	}
+
+	var raw []Fingerprint
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, false, fmt.Errorf("unable to decode %s %s: %w", FingerprintDatabase, p, err)
+	}
+
+	f := make(Fingerprints, len(raw))
+	for _, r := range raw {
+		f[r.SHA256] = r
+	}
+
+	return f, true, nil
+}
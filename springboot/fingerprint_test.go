@@ -0,0 +1,74 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestFingerprint(t *testing.T) {
+	spec.Run(t, "Fingerprint", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var root string
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "fingerprint")
+		})
+
+		it("returns false without database", func() {
+			_, ok, err := springboot.NewFingerprints()
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns error for invalid database", func() {
+			p := filepath.Join(root, "fingerprints.json")
+			test.WriteFile(t, p, "{")
+			defer test.ReplaceEnv(t, springboot.FingerprintDatabase, p)()
+
+			_, _, err := springboot.NewFingerprints()
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("identifies shaded libraries", func() {
+			p := filepath.Join(root, "fingerprints.json")
+			test.WriteFile(t, p, `[
+  {"name": "test-library-1", "version": "1.0.0", "sha256": "ed1e1dcf971990c1b89676ae785436106f7548b1ae41d174ca9d3bfb9661a477"},
+  {"name": "test-library-1", "version": "1.0.0", "sha256": "e063cdf36f817a24e97839b0799c023644dd1c31c668bda6481869027035a655"},
+  {"name": "test-library-2", "version": "2.0.0", "sha256": "test-sha256"}
+]`)
+			defer test.ReplaceEnv(t, springboot.FingerprintDatabase, p)()
+
+			f, ok, err := springboot.NewFingerprints()
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(f.Identify(filepath.Join("testdata", "test-shaded.jar"))).To(gomega.Equal(springboot.JARDependencies{
+				{Name: "test-library-1", Version: "1.0.0", NestedIn: "test-shaded.jar"},
+			}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// GracefulShutdownEnabled is the environment variable that disables graceful shutdown configuration when set to
+	// false.
+	GracefulShutdownEnabled = "BP_SPRING_BOOT_GRACEFUL_SHUTDOWN"
+
+	// GracefulShutdownTimeout is the default time allowed for each shutdown phase.  It is shorter than the default
+	// Kubernetes termination grace period of 30 seconds so that shutdown completes before SIGKILL.
+	GracefulShutdownTimeout = "20s"
+)
+
+// GracefulShutdown configures Spring Boot 2.3 and later to complete in-flight requests when it receives SIGTERM.
+type GracefulShutdown struct {
+	// Timeout is the time allowed for each shutdown phase.
+	Timeout string `toml:"timeout"`
+}
+
+func (g GracefulShutdown) Identity() (string, string) {
+	return "Graceful Shutdown", g.Timeout
+}
+
+// Contribute writes default launch environment variables to a layer marked launch.  Values configured on the running
+// image take precedence.
+func (g GracefulShutdown) Contribute(layer layers.Layer) error {
+	return layer.Contribute(g, func(layer layers.Layer) error {
+		if err := layer.DefaultLaunchEnv("SERVER_SHUTDOWN", "graceful"); err != nil {
+			return err
+		}
+
+		if err := layer.DefaultLaunchEnv("SPRING_LIFECYCLE_TIMEOUTPERSHUTDOWNPHASE", g.Timeout); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewGracefulShutdown creates a new GracefulShutdown instance.  OK is true if the application is Spring Boot 2.3 or
+// later and $BP_SPRING_BOOT_GRACEFUL_SHUTDOWN is not false.
+func NewGracefulShutdown(metadata Metadata) (GracefulShutdown, bool, error) {
+	if e, err := config.LookupBool(GracefulShutdownEnabled, true); err != nil {
+		return GracefulShutdown{}, false, err
+	} else if !e {
+		return GracefulShutdown{}, false, nil
+	}
+
+	if !metadata.versionMatches(">=2.3") {
+		return GracefulShutdown{}, false, nil
+	}
+
+	return GracefulShutdown{GracefulShutdownTimeout}, true, nil
+}
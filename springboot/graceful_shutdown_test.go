@@ -0,0 +1,81 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	spec.Run(t, "GracefulShutdown", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		it("returns false for Spring Boot before 2.3", func() {
+			_, ok, err := springboot.NewGracefulShutdown(springboot.Metadata{Version: "2.2.5.RELEASE"})
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns false for non-numeric versions", func() {
+			_, ok, err := springboot.NewGracefulShutdown(springboot.Metadata{Version: "test-version"})
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns true for Spring Boot 2.3 and later", func() {
+			_, ok, err := springboot.NewGracefulShutdown(springboot.Metadata{Version: "2.3.0.RELEASE"})
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns false when disabled", func() {
+			defer test.ReplaceEnv(t, springboot.GracefulShutdownEnabled, "false")()
+
+			_, ok, err := springboot.NewGracefulShutdown(springboot.Metadata{Version: "2.3.0.RELEASE"})
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns error for invalid value", func() {
+			defer test.ReplaceEnv(t, springboot.GracefulShutdownEnabled, "test-value")()
+
+			_, _, err := springboot.NewGracefulShutdown(springboot.Metadata{Version: "2.3.0.RELEASE"})
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("contributes default launch environment", func() {
+			f := test.NewBuildFactory(t)
+
+			s, _, err := springboot.NewGracefulShutdown(springboot.Metadata{Version: "2.3.0.RELEASE"})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			layer := f.Build.Layers.Layer("graceful-shutdown")
+			g.Expect(s.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(layer).To(test.HaveDefaultLaunchEnvironment("SERVER_SHUTDOWN", "graceful"))
+			g.Expect(layer).To(test.HaveDefaultLaunchEnvironment("SPRING_LIFECYCLE_TIMEOUTPERSHUTDOWNPHASE", springboot.GracefulShutdownTimeout))
+		})
+	}, spec.Report(report.Terminal{}))
+}
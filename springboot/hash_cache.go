@@ -0,0 +1,187 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+)
+
+// HashCacheFile is the name of the file, in a layer marked cache, that records the SHA256 of files hashed during the
+// previous build.
+const HashCacheFile = "hashes.json"
+
+// HashCacheSample is the number of bytes, at each end of a file, whose SHA256 identifies the file in the HashCache.
+const HashCacheSample = 64 * 1024
+
+type hashCacheEntry struct {
+	Sample string `json:"sample"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// HashCache caches the SHA256 of files, keyed by path, across builds.  A file whose size and first and last
+// HashCacheSample bytes are unchanged since the previous build is not hashed again.  Modification times are not used,
+// as platforms such as pack normalize them.  A change confined to the middle of a file that preserves its size is not
+// detected, but a change to any entry of a JAR is, as the central directory at its end records the CRC-32 of every
+// entry.  Files no larger than two samples are always hashed and never cached.  A nil HashCache hashes every file.
+type HashCache struct {
+	// Hits is the number of files whose SHA256 was reused.
+	Hits int
+
+	// Misses is the number of files that were hashed.
+	Misses int
+
+	current  map[string]hashCacheEntry
+	layer    layers.Layer
+	mutex    sync.Mutex
+	previous map[string]hashCacheEntry
+}
+
+// Hash returns the SHA256 of a file.
+func (h *HashCache) Hash(path string) (string, error) {
+	if h == nil {
+		return hash(path)
+	}
+
+	i, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	if i.Size() <= 2*HashCacheSample {
+		h.mutex.Lock()
+		h.Misses++
+		h.mutex.Unlock()
+		return hash(path)
+	}
+
+	p, err := sample(path)
+	if err != nil {
+		return "", err
+	}
+
+	h.mutex.Lock()
+	e, ok := h.previous[path]
+	h.mutex.Unlock()
+
+	if ok && e.Size == i.Size() && e.Sample == p {
+		h.mutex.Lock()
+		h.current[path] = e
+		h.Hits++
+		h.mutex.Unlock()
+		return e.SHA256, nil
+	}
+
+	s, err := hash(path)
+	if err != nil {
+		return "", err
+	}
+
+	h.mutex.Lock()
+	h.current[path] = hashCacheEntry{Sample: p, SHA256: s, Size: i.Size()}
+	h.Misses++
+	h.mutex.Unlock()
+
+	return s, nil
+}
+
+// Write persists the SHA256 of the files hashed during this build, replacing those of the previous build.
+func (h *HashCache) Write() error {
+	if h == nil {
+		return nil
+	}
+
+	h.mutex.Lock()
+	b, err := json.Marshal(h.current)
+	n := len(h.current)
+	h.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := helper.WriteFile(filepath.Join(h.layer.Root, HashCacheFile), 0644, "%s", b); err != nil {
+		return err
+	}
+
+	h.layer.Touch()
+	return h.layer.WriteMetadata(struct {
+		Files int `toml:"files"`
+	}{n}, layers.Cache)
+}
+
+// NewHashCache creates a new HashCache instance, reading the SHA256 of files hashed during the previous build from
+// layer.
+func NewHashCache(layer layers.Layer) (*HashCache, error) {
+	h := &HashCache{
+		current:  make(map[string]hashCacheEntry),
+		layer:    layer,
+		previous: make(map[string]hashCacheEntry),
+	}
+
+	f := filepath.Join(layer.Root, HashCacheFile)
+	if exists, err := helper.FileExists(f); err != nil {
+		return nil, err
+	} else if !exists {
+		return h, nil
+	}
+
+	b, err := ioutil.ReadFile(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &h.previous); err != nil {
+		layer.Logger.Debug("Ignoring invalid %s: %s", f, err)
+		h.previous = make(map[string]hashCacheEntry)
+	}
+
+	return h, nil
+}
+
+// sample returns the SHA256 of the first and last HashCacheSample bytes of a file.
+func sample(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	s := sha256.New()
+	if _, err := io.CopyN(s, f, HashCacheSample); err != nil {
+		return "", err
+	}
+
+	if _, err := f.Seek(-HashCacheSample, io.SeekEnd); err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(s, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(s.Sum(nil)), nil
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestHashCache(t *testing.T) {
+	spec.Run(t, "HashCache", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			content string
+			f       *test.BuildFactory
+			file    string
+			layer   layers.Layer
+		)
+
+		digest := func(s string) string {
+			d := sha256.Sum256([]byte(s))
+			return hex.EncodeToString(d[:])
+		}
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+			layer = f.Build.Layers.Layer("hashes")
+
+			file = filepath.Join(f.Build.Application.Root, "test-file")
+			content = strings.Repeat("a", springboot.HashCacheSample) + "test-1" + strings.Repeat("b", springboot.HashCacheSample)
+			test.WriteFile(t, file, "%s", content)
+		})
+
+		cache := func(value string) {
+			s := digest(content[:springboot.HashCacheSample] + content[len(content)-springboot.HashCacheSample:])
+			test.WriteFile(t, filepath.Join(layer.Root, springboot.HashCacheFile),
+				`{"%s": {"sample": "%s", "sha256": "%s", "size": %d}}`, file, s, value, len(content))
+		}
+
+		it("hashes files without a cache", func() {
+			var h *springboot.HashCache
+			g.Expect(h.Hash(file)).To(gomega.Equal(digest(content)))
+			g.Expect(h.Write()).To(gomega.Succeed())
+		})
+
+		it("hashes and persists files", func() {
+			h, err := springboot.NewHashCache(layer)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(h.Hash(file)).To(gomega.Equal(digest(content)))
+			g.Expect(h.Misses).To(gomega.Equal(1))
+			g.Expect(h.Write()).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(false, true, false))
+
+			h, err = springboot.NewHashCache(layer)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(h.Hash(file)).To(gomega.Equal(digest(content)))
+			g.Expect(h.Hits).To(gomega.Equal(1))
+		})
+
+		it("reuses hashes of unchanged files", func() {
+			cache("test-sha256")
+
+			h, err := springboot.NewHashCache(layer)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(h.Hash(file)).To(gomega.Equal("test-sha256"))
+			g.Expect(h.Hits).To(gomega.Equal(1))
+		})
+
+		it("reuses hashes of files with changed modification times", func() {
+			cache("test-sha256")
+			g.Expect(os.Chtimes(file, reproducible.DefaultTime, reproducible.DefaultTime)).To(gomega.Succeed())
+
+			h, err := springboot.NewHashCache(layer)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(h.Hash(file)).To(gomega.Equal("test-sha256"))
+		})
+
+		it("hashes changed files", func() {
+			cache("test-sha256")
+			content = content[:len(content)-1] + "c"
+			test.WriteFile(t, file, "%s", content)
+
+			h, err := springboot.NewHashCache(layer)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(h.Hash(file)).To(gomega.Equal(digest(content)))
+			g.Expect(h.Misses).To(gomega.Equal(1))
+		})
+
+		it("hashes small files without caching them", func() {
+			test.WriteFile(t, file, "test-1")
+
+			h, err := springboot.NewHashCache(layer)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(h.Hash(file)).To(gomega.Equal(digest("test-1")))
+			g.Expect(h.Write()).To(gomega.Succeed())
+			g.Expect(filepath.Join(layer.Root, springboot.HashCacheFile)).To(test.HaveContent("{}"))
+		})
+
+		it("ignores invalid cache", func() {
+			test.WriteFile(t, filepath.Join(layer.Root, springboot.HashCacheFile), "invalid")
+
+			h, err := springboot.NewHashCache(layer)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(h.Hash(file)).To(gomega.Equal(digest(content)))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/spring-boot-cnb/health"
+)
+
+// DefaultHealthBasePath is the base path of actuator endpoints when management.endpoints.web.base-path is not
+// configured.
+const DefaultHealthBasePath = "/actuator"
+
+// NewHealthEndpoint creates a new health.Endpoint from the management configuration in application.properties.  OK is
+// false if spring-boot-actuator or an embedded server is not present, or if management over HTTP is disabled.
+func NewHealthEndpoint(root string, metadata Metadata) (health.Endpoint, bool, error) {
+	if _, ok := FindJARDependency(metadata.ClassPath, "spring-boot-actuator"); !ok {
+		return health.Endpoint{}, false, nil
+	}
+
+	s, ok, err := NewEmbeddedServer(root, metadata)
+	if err != nil || !ok {
+		return health.Endpoint{}, false, err
+	}
+
+	file := filepath.Join(root, metadata.Classes, "application.properties")
+	property := func(key string, def string) (string, error) {
+		v, ok, err := readProperty(file, key)
+		if err != nil || !ok {
+			return def, err
+		}
+		return v, nil
+	}
+
+	port, err := property("management.server.port", "")
+	if err != nil {
+		return health.Endpoint{}, false, err
+	} else if port == "-1" {
+		return health.Endpoint{}, false, nil
+	}
+
+	// Actuator endpoints are served beneath the servlet context path unless they have a port of their own
+	prefix, ssl := "", "server.ssl"
+	if port == "" {
+		port = s.Port
+		if prefix, err = property("server.servlet.context-path", ""); err != nil {
+			return health.Endpoint{}, false, err
+		}
+	} else {
+		ssl = "management.server.ssl"
+	}
+
+	base, err := property("management.endpoints.web.base-path", DefaultHealthBasePath)
+	if err != nil {
+		return health.Endpoint{}, false, err
+	}
+
+	mapping, err := property("management.endpoints.web.path-mapping.health", "health")
+	if err != nil {
+		return health.Endpoint{}, false, err
+	}
+
+	// SSL is enabled by configuring a key store or bundle, unless it is explicitly disabled
+	scheme := "http"
+	if e, err := property(ssl+".enabled", "true"); err != nil {
+		return health.Endpoint{}, false, err
+	} else if e == "true" {
+		for _, k := range []string{".key-store", ".bundle"} {
+			if v, err := property(ssl+k, ""); err != nil {
+				return health.Endpoint{}, false, err
+			} else if v != "" {
+				scheme = "https"
+			}
+		}
+	}
+
+	return health.Endpoint{Path: healthPath(prefix, base, mapping), Port: port, Scheme: scheme}, true, nil
+}
+
+func healthPath(segments ...string) string {
+	var s []string
+	for _, v := range segments {
+		if v = strings.Trim(v, "/"); v != "" {
+			s = append(s, v)
+		}
+	}
+
+	return "/" + strings.Join(s, "/")
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/health"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestHealthEndpoint(t *testing.T) {
+	spec.Run(t, "HealthEndpoint", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			metadata springboot.Metadata
+			root     string
+		)
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "health")
+			metadata = springboot.Metadata{
+				Classes: "test-classes",
+				ClassPath: []string{
+					"/test-lib/spring-boot-actuator-2.3.0.RELEASE.jar",
+					"/test-lib/tomcat-embed-core-9.0.31.jar",
+				},
+			}
+		})
+
+		it("returns false without spring-boot-actuator", func() {
+			_, ok, err := springboot.NewHealthEndpoint(root, springboot.Metadata{
+				ClassPath: []string{"/test-lib/tomcat-embed-core-9.0.31.jar"},
+			})
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns false without an embedded server", func() {
+			_, ok, err := springboot.NewHealthEndpoint(root, springboot.Metadata{
+				ClassPath: []string{"/test-lib/spring-boot-actuator-2.3.0.RELEASE.jar"},
+			})
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns default endpoint", func() {
+			e, ok, err := springboot.NewHealthEndpoint(root, metadata)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(e).To(gomega.Equal(health.Endpoint{Path: "/actuator/health", Port: "8080", Scheme: "http"}))
+		})
+
+		it("uses server configuration", func() {
+			test.WriteFile(t, filepath.Join(root, "test-classes", "application.properties"), `server.port=9090
+server.servlet.context-path=/test-context/
+server.ssl.key-store=classpath:test.p12
+management.endpoints.web.base-path=/manage
+management.endpoints.web.path-mapping.health=healthz
+`)
+
+			e, ok, err := springboot.NewHealthEndpoint(root, metadata)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(e).To(gomega.Equal(health.Endpoint{Path: "/test-context/manage/healthz", Port: "9090", Scheme: "https"}))
+		})
+
+		it("uses management server configuration", func() {
+			test.WriteFile(t, filepath.Join(root, "test-classes", "application.properties"), `server.port=9090
+server.servlet.context-path=/test-context
+server.ssl.key-store=classpath:test.p12
+management.server.port=9091
+`)
+
+			e, ok, err := springboot.NewHealthEndpoint(root, metadata)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(e).To(gomega.Equal(health.Endpoint{Path: "/actuator/health", Port: "9091", Scheme: "http"}))
+		})
+
+		it("returns false when management over HTTP is disabled", func() {
+			test.WriteFile(t, filepath.Join(root, "test-classes", "application.properties"), "management.server.port=-1\n")
+
+			_, ok, err := springboot.NewHealthEndpoint(root, metadata)
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("ignores disabled SSL", func() {
+			test.WriteFile(t, filepath.Join(root, "test-classes", "application.properties"), `server.ssl.enabled=false
+server.ssl.key-store=classpath:test.p12
+`)
+
+			e, _, err := springboot.NewHealthEndpoint(root, metadata)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(e.Scheme).To(gomega.Equal("http"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -16,14 +16,42 @@
 
 package springboot
 
+// JARDependencies are JAR dependencies, ordered by name, version, SHA256, and the JAR they are nested in.
 type JARDependencies []JARDependency
 
+// Dedup returns the dependencies, which must be sorted, without repeated dependencies with the same name, version, and
+// SHA256 (e.g. the same JAR in more than one directory) that are nested in the same JAR.
+func (d JARDependencies) Dedup() JARDependencies {
+	var r JARDependencies
+
+	for i, dep := range d {
+		if i > 0 && dep == d[i-1] {
+			continue
+		}
+		r = append(r, dep)
+	}
+
+	return r
+}
+
 func (d JARDependencies) Len() int {
 	return len(d)
 }
 
 func (d JARDependencies) Less(i, j int) bool {
-	return d[i].Name < d[j].Name
+	if d[i].Name != d[j].Name {
+		return d[i].Name < d[j].Name
+	}
+
+	if d[i].Version != d[j].Version {
+		return d[i].Version < d[j].Version
+	}
+
+	if d[i].SHA256 != d[j].SHA256 {
+		return d[i].SHA256 < d[j].SHA256
+	}
+
+	return d[i].NestedIn < d[j].NestedIn
 }
 
 func (d JARDependencies) Swap(i, j int) {
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestJARDependencies(t *testing.T) {
+	spec.Run(t, "JARDependencies", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		it("orders by name, version, and sha256", func() {
+			d := springboot.JARDependencies{
+				{Name: "test-2", Version: "1.0.0", SHA256: "test-sha256-1"},
+				{Name: "test-1", Version: "2.0.0", SHA256: "test-sha256-1"},
+				{Name: "test-1", Version: "1.0.0", SHA256: "test-sha256-2"},
+				{Name: "test-1", Version: "1.0.0", SHA256: "test-sha256-1"},
+			}
+
+			sort.Sort(d)
+
+			g.Expect(d).To(gomega.Equal(springboot.JARDependencies{
+				{Name: "test-1", Version: "1.0.0", SHA256: "test-sha256-1"},
+				{Name: "test-1", Version: "1.0.0", SHA256: "test-sha256-2"},
+				{Name: "test-1", Version: "2.0.0", SHA256: "test-sha256-1"},
+				{Name: "test-2", Version: "1.0.0", SHA256: "test-sha256-1"},
+			}))
+		})
+
+		it("removes duplicates", func() {
+			d := springboot.JARDependencies{
+				{Name: "test-1", Version: "1.0.0", SHA256: "test-sha256-1"},
+				{Name: "test-1", Version: "1.0.0", SHA256: "test-sha256-1"},
+				{Name: "test-1", Version: "1.0.0", SHA256: "test-sha256-2"},
+				{Name: "test-2", Version: "1.0.0", SHA256: "test-sha256-1"},
+			}
+
+			g.Expect(d.Dedup()).To(gomega.Equal(springboot.JARDependencies{
+				{Name: "test-1", Version: "1.0.0", SHA256: "test-sha256-1"},
+				{Name: "test-1", Version: "1.0.0", SHA256: "test-sha256-2"},
+				{Name: "test-2", Version: "1.0.0", SHA256: "test-sha256-1"},
+			}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -17,11 +17,13 @@
 package springboot
 
 import (
+	"archive/zip"
 	"crypto/sha256"
 	"encoding/hex"
 	"io"
 	"os"
 	"regexp"
+	"strings"
 
 	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
 )
@@ -30,20 +32,30 @@ var pattern = regexp.MustCompile(".+/(.*)-([\\d].*)\\.jar")
 
 // JARDependency represents a JAR dependency within an application
 type JARDependency struct {
-	Name    string `toml:"name"`
-	Version string `toml:"version"`
-	SHA256  string `toml:"sha256"`
+	Name    string `json:"name" toml:"name"`
+	Version string `json:"version" toml:"version"`
+	SHA256  string `json:"sha256" toml:"sha256"`
+
+	// Group is the Maven group id of the dependency, if the JAR contains Maven metadata.
+	Group string `json:"group,omitempty" toml:"group,omitempty"`
+
+	// NestedIn is the name of the JAR that the dependency is nested in, if any.
+	NestedIn string `json:"nested-in,omitempty" toml:"nested-in,omitempty"`
 }
 
 // NewJARDependency creates a new instance of JAR dependency, returning true if it matches the standard Maven naming
 // scheme.
 func NewJARDependency(path string, logger logger.Logger) (JARDependency, bool, error) {
+	return newJARDependency(path, nil)
+}
+
+func newJARDependency(path string, hashes *HashCache) (JARDependency, bool, error) {
 	m := pattern.FindStringSubmatch(path)
 	if m == nil {
 		return JARDependency{}, false, nil
 	}
 
-	h, err := hash(path)
+	h, err := hashes.Hash(path)
 	if err != nil {
 		return JARDependency{}, false, err
 	}
@@ -52,9 +64,42 @@ func NewJARDependency(path string, logger logger.Logger) (JARDependency, bool, e
 		Name:    m[1],
 		Version: m[2],
 		SHA256:  h,
+		Group:   group(path, m[1]),
 	}, true, nil
 }
 
+// group returns the Maven group id recorded for an artifact in a JAR's META-INF/maven directory.  Files that are not
+// JARs or contain no such entry have no group.
+func group(file string, artifact string) string {
+	z, err := zip.OpenReader(file)
+	if err != nil {
+		return ""
+	}
+	defer z.Close()
+
+	for _, f := range z.File {
+		// META-INF/maven/<group>/<artifact>/pom.properties
+		if p := strings.Split(f.Name, "/"); len(p) == 5 && p[0] == "META-INF" && p[1] == "maven" &&
+			p[3] == artifact && p[4] == "pom.properties" {
+			return p[2]
+		}
+	}
+
+	return ""
+}
+
+// FindJARDependency returns the version of a JAR dependency with a given name from a collection of paths, returning
+// true if it was found.
+func FindJARDependency(paths []string, name string) (string, bool) {
+	for _, p := range paths {
+		if m := pattern.FindStringSubmatch(p); m != nil && m[1] == name {
+			return m[2], true
+		}
+	}
+
+	return "", false
+}
+
 func hash(file string) (string, error) {
 	s := sha256.New()
 
@@ -0,0 +1,299 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+const (
+	// JDKModulesCacheFile is the name of the file, in a layer marked cache, containing the JDK modules referenced by
+	// each JAR, keyed by SHA256.
+	JDKModulesCacheFile = "jdk-modules.json"
+
+	// JDKModulesEnabled is the environment variable that, when true, records the JDK modules required by the
+	// application as plan metadata.
+	JDKModulesEnabled = "BP_SPRING_BOOT_JDK_MODULES"
+)
+
+// jdkModules are the JDK modules that export packages, keyed by the package prefixes they export.  A package belongs
+// to the module of its longest matching prefix.  Prefixes mapped to "" were removed from the JDK and are provided by
+// libraries.
+var jdkModules = map[string]string{
+	"com.sun.jdi":                  "jdk.jdi",
+	"com.sun.management":           "jdk.management",
+	"com.sun.net.httpserver":       "jdk.httpserver",
+	"com.sun.nio.file":             "jdk.unsupported",
+	"com.sun.nio.sctp":             "jdk.sctp",
+	"com.sun.tools.attach":         "jdk.attach",
+	"java":                         "java.base",
+	"java.applet":                  "java.desktop",
+	"java.awt":                     "java.desktop",
+	"java.awt.datatransfer":        "java.datatransfer",
+	"java.beans":                   "java.desktop",
+	"java.lang.instrument":         "java.instrument",
+	"java.lang.management":         "java.management",
+	"java.net.http":                "java.net.http",
+	"java.rmi":                     "java.rmi",
+	"java.sql":                     "java.sql",
+	"java.util.logging":            "java.logging",
+	"java.util.prefs":              "java.prefs",
+	"javax.accessibility":          "java.desktop",
+	"javax.annotation.processing":  "java.compiler",
+	"javax.crypto":                 "java.base",
+	"javax.imageio":                "java.desktop",
+	"javax.lang.model":             "java.compiler",
+	"javax.management":             "java.management",
+	"javax.management.remote.rmi":  "java.management.rmi",
+	"javax.naming":                 "java.naming",
+	"javax.net":                    "java.base",
+	"javax.print":                  "java.desktop",
+	"javax.rmi.ssl":                "java.rmi",
+	"javax.script":                 "java.scripting",
+	"javax.security.auth":          "java.base",
+	"javax.security.auth.kerberos": "java.security.jgss",
+	"javax.security.cert":          "java.base",
+	"javax.security.sasl":          "java.security.sasl",
+	"javax.smartcardio":            "java.smartcardio",
+	"javax.sound":                  "java.desktop",
+	"javax.sql":                    "java.sql",
+	"javax.sql.rowset":             "java.sql.rowset",
+	"javax.swing":                  "java.desktop",
+	"javax.tools":                  "java.compiler",
+	"javax.transaction.xa":         "java.transaction.xa",
+	"javax.xml":                    "java.xml",
+	"javax.xml.bind":               "",
+	"javax.xml.crypto":             "java.xml.crypto",
+	"javax.xml.soap":               "",
+	"javax.xml.ws":                 "",
+	"jdk.jfr":                      "jdk.jfr",
+	"jdk.net":                      "jdk.net",
+	"org.ietf.jgss":                "java.security.jgss",
+	"org.w3c.dom":                  "java.xml",
+	"org.xml.sax":                  "java.xml",
+	"sun.misc":                     "jdk.unsupported",
+	"sun.reflect":                  "jdk.unsupported",
+}
+
+// jdkModule returns the JDK module that exports the package of a class, in internal form (e.g. java/sql/Connection),
+// returning true if one does.
+func jdkModule(class string) (string, bool) {
+	i := strings.LastIndex(class, "/")
+	if i < 0 {
+		return "", false
+	}
+
+	for p := strings.ReplaceAll(class[:i], "/", "."); ; {
+		if m, ok := jdkModules[p]; ok {
+			return m, m != ""
+		}
+
+		i := strings.LastIndex(p, ".")
+		if i < 0 {
+			return "", false
+		}
+		p = p[:i]
+	}
+}
+
+// AnalyzeJDKModules returns the JDK modules, ordered by name, that export packages referenced by the class files on the
+// class path, as jdeps does.  java.base is always required.  The modules referenced by each JAR are cached in layer,
+// keyed by SHA256, so that unchanged JARs are not analyzed again in later builds.
+func AnalyzeJDKModules(classPath []string, layer layers.Layer) ([]string, error) {
+	f := filepath.Join(layer.Root, JDKModulesCacheFile)
+
+	previous := make(map[string][]string)
+	if exists, err := helper.FileExists(f); err != nil {
+		return nil, err
+	} else if exists {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(b, &previous); err != nil {
+			layer.Logger.Debug("Ignoring invalid %s: %s", f, err)
+		}
+	}
+
+	m := map[string]bool{"java.base": true}
+	cache := make(map[string][]string)
+
+	for _, e := range classPath {
+		i, err := os.Stat(e)
+		if err != nil {
+			continue
+		}
+
+		if i.IsDir() {
+			if err := filepath.Walk(e, func(path string, info os.FileInfo, err error) error {
+				if err != nil || info.IsDir() || filepath.Ext(path) != ".class" {
+					return err
+				}
+
+				f, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				return addJDKModules(m, f, path)
+			}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if filepath.Ext(e) != ".jar" {
+			continue
+		}
+
+		h, err := hash(e)
+		if err != nil {
+			return nil, err
+		}
+
+		j, ok := previous[h]
+		if !ok {
+			if j, err = jarJDKModules(e); err != nil {
+				return nil, err
+			}
+		}
+		cache[h] = j
+
+		for _, n := range j {
+			m[n] = true
+		}
+	}
+
+	b, err := json.Marshal(cache)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := helper.WriteFile(f, 0644, "%s", b); err != nil {
+		return nil, err
+	}
+
+	layer.Touch()
+	if err := layer.WriteMetadata(struct {
+		JARs int `toml:"jars"`
+	}{len(cache)}, layers.Cache); err != nil {
+		return nil, err
+	}
+
+	var modules []string
+	for k := range m {
+		modules = append(modules, k)
+	}
+	sort.Strings(modules)
+
+	return modules, nil
+}
+
+func addJDKModules(modules map[string]bool, r io.Reader, name string) error {
+	c, err := readClassReferences(r)
+	if err != nil {
+		return fmt.Errorf("unable to read class file %s: %w", name, err)
+	}
+
+	for _, n := range c {
+		if m, ok := jdkModule(n); ok {
+			modules[m] = true
+		}
+	}
+
+	return nil
+}
+
+func jarJDKModules(jar string) ([]string, error) {
+	z, err := zip.OpenReader(jar)
+	if err != nil {
+		return []string{}, nil
+	}
+	defer z.Close()
+
+	m := make(map[string]bool)
+	for _, f := range z.File {
+		if filepath.Ext(f.Name) != ".class" {
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		err = addJDKModules(m, r, fmt.Sprintf("%s in %s", f.Name, jar))
+		_ = r.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	modules := []string{}
+	for k := range m {
+		modules = append(modules, k)
+	}
+	sort.Strings(modules)
+
+	return modules, nil
+}
+
+func jdkModulesEnabled() (bool, error) {
+	return config.LookupBool(JDKModulesEnabled, false)
+}
+
+// readClassReferences returns the names, in internal form, of the classes referenced by the constant pool of a class
+// file.  Array classes are reported as their element class.
+func readClassReferences(r io.Reader) ([]string, error) {
+	c := classReader{r: bufio.NewReader(r)}
+
+	if c.u4() != classMagic {
+		return nil, c.invalid("not a class file")
+	}
+	c.skip(4) // minor and major version
+
+	utf8, classes := c.constantPool()
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	var n []string
+	for _, i := range classes {
+		s := strings.TrimLeft(utf8[i], "[")
+		if strings.HasPrefix(s, "L") && strings.HasSuffix(s, ";") {
+			s = s[1 : len(s)-1]
+		}
+		n = append(n, s)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestJDKModules(t *testing.T) {
+	spec.Run(t, "JDKModules", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			classes = filepath.Join("testdata", "runtime_hints")
+			f       *test.BuildFactory
+			jar     string
+			layer   layers.Layer
+		)
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+			layer = f.Build.Layers.Layer("jdk-modules")
+
+			b, err := ioutil.ReadFile(filepath.Join(classes, "test", "Database.class"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			jar = filepath.Join(test.ScratchDir(t, "jdk-modules"), "test-1.2.3.jar")
+			w, err := os.Create(jar)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			z := zip.NewWriter(w)
+			e, err := z.Create("test/Database.class")
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			_, err = e.Write(b)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(z.Close()).To(gomega.Succeed())
+			g.Expect(w.Close()).To(gomega.Succeed())
+		})
+
+		it("requires java.base", func() {
+			g.Expect(springboot.AnalyzeJDKModules(nil, layer)).To(gomega.Equal([]string{"java.base"}))
+		})
+
+		it("analyzes class files", func() {
+			g.Expect(springboot.AnalyzeJDKModules([]string{classes}, layer)).
+				To(gomega.Equal([]string{"java.base", "java.logging", "java.sql"}))
+		})
+
+		it("analyzes JARs and caches them by SHA256", func() {
+			g.Expect(springboot.AnalyzeJDKModules([]string{jar}, layer)).
+				To(gomega.Equal([]string{"java.base", "java.logging", "java.sql"}))
+
+			g.Expect(layer).To(test.HaveLayerMetadata(false, true, false))
+			b, err := ioutil.ReadFile(filepath.Join(layer.Root, springboot.JDKModulesCacheFile))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(b).To(gomega.MatchJSON(fmt.Sprintf(`{"%s": ["java.base", "java.logging", "java.sql"]}`, digest(jar))))
+		})
+
+		it("uses cached JARs", func() {
+			test.WriteFile(t, filepath.Join(layer.Root, springboot.JDKModulesCacheFile),
+				fmt.Sprintf(`{"%s": ["java.naming"]}`, digest(jar)))
+
+			g.Expect(springboot.AnalyzeJDKModules([]string{jar}, layer)).
+				To(gomega.Equal([]string{"java.base", "java.naming"}))
+		})
+
+		it("ignores invalid cache", func() {
+			test.WriteFile(t, filepath.Join(layer.Root, springboot.JDKModulesCacheFile), "invalid")
+
+			g.Expect(springboot.AnalyzeJDKModules([]string{jar}, layer)).
+				To(gomega.Equal([]string{"java.base", "java.logging", "java.sql"}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
+
+func digest(file string) string {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		panic(err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
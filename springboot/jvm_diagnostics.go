@@ -0,0 +1,76 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// HeapDumpPath is the environment variable that, at launch, enables heap dumps on OutOfMemoryError and configures
+	// the directory, typically a mounted volume, that they are written to.
+	HeapDumpPath = "BPL_HEAP_DUMP_PATH"
+
+	// JFREnabled is the environment variable that, at launch, enables continuous Java Flight Recorder recording when
+	// set to true.
+	JFREnabled = "BPL_JFR_ENABLED"
+
+	// JVMDiagnosticsVersion is the version of the profile.d scripts contributed by JVMDiagnostics.
+	JVMDiagnosticsVersion = "1"
+)
+
+// JVMDiagnostics contributes profile.d scripts that enable heap dumps on OutOfMemoryError ($BPL_HEAP_DUMP_PATH) and
+// continuous Java Flight Recorder recording ($BPL_JFR_ENABLED) at launch.
+type JVMDiagnostics struct {
+	// Version is the version of the profile.d scripts.
+	Version string `toml:"version"`
+}
+
+func (j JVMDiagnostics) Identity() (string, string) {
+	return "Heap Dump and JFR", j.Version
+}
+
+// Contribute writes the profile.d scripts to a layer marked launch.
+func (j JVMDiagnostics) Contribute(layer layers.Layer) error {
+	return layer.Contribute(j, func(layer layers.Layer) error {
+		if err := layer.WriteProfile("heap-dump", `if [ -n "${%[1]s:-}" ]; then
+  printf "Heap dumps on OutOfMemoryError written to %%s\n" "${%[1]s}"
+  export JAVA_OPTS="${JAVA_OPTS} -XX:+HeapDumpOnOutOfMemoryError -XX:HeapDumpPath=${%[1]s}"
+fi
+`, HeapDumpPath); err != nil {
+			return err
+		}
+
+		if err := layer.WriteProfile("jfr", `if [ "${%s:-false}" = "true" ]; then
+  JFR_FILE="${%s:-/tmp}/recording.jfr"
+
+  printf "Java Flight Recorder recording written to %%s on exit\n" "${JFR_FILE}"
+  export JAVA_OPTS="${JAVA_OPTS} -XX:StartFlightRecording=disk=true,dumponexit=true,maxage=1h,filename=${JFR_FILE}"
+fi
+`, JFREnabled, HeapDumpPath); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewJVMDiagnostics creates a new JVMDiagnostics instance.
+func NewJVMDiagnostics() JVMDiagnostics {
+	return JVMDiagnostics{JVMDiagnosticsVersion}
+}
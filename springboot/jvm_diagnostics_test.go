@@ -0,0 +1,85 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestJVMDiagnostics(t *testing.T) {
+	spec.Run(t, "JVMDiagnostics", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		javaOpts := func(profile string, env ...string) string {
+			layer := f.Build.Layers.Layer("jvm-diagnostics")
+			c := exec.Command("sh", "-c", `. "$0" > /dev/null && printf "%s" "${JAVA_OPTS}"`,
+				filepath.Join(layer.Root, "profile.d", profile))
+			c.Env = append([]string{"JAVA_OPTS=-Dtest"}, env...)
+
+			b, err := c.Output()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			return string(b)
+		}
+
+		it("contributes profile.d scripts", func() {
+			g.Expect(springboot.NewJVMDiagnostics().Contribute(f.Build.Layers.Layer("jvm-diagnostics"))).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("jvm-diagnostics")
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(filepath.Join(layer.Root, "profile.d", "heap-dump")).To(gomega.BeARegularFile())
+			g.Expect(filepath.Join(layer.Root, "profile.d", "jfr")).To(gomega.BeARegularFile())
+		})
+
+		it("does not change $JAVA_OPTS by default", func() {
+			g.Expect(springboot.NewJVMDiagnostics().Contribute(f.Build.Layers.Layer("jvm-diagnostics"))).To(gomega.Succeed())
+
+			g.Expect(javaOpts("heap-dump")).To(gomega.Equal("-Dtest"))
+			g.Expect(javaOpts("jfr")).To(gomega.Equal("-Dtest"))
+		})
+
+		it("enables heap dumps", func() {
+			g.Expect(springboot.NewJVMDiagnostics().Contribute(f.Build.Layers.Layer("jvm-diagnostics"))).To(gomega.Succeed())
+
+			g.Expect(javaOpts("heap-dump", "BPL_HEAP_DUMP_PATH=/dumps")).
+				To(gomega.Equal("-Dtest -XX:+HeapDumpOnOutOfMemoryError -XX:HeapDumpPath=/dumps"))
+		})
+
+		it("enables JFR", func() {
+			g.Expect(springboot.NewJVMDiagnostics().Contribute(f.Build.Layers.Layer("jvm-diagnostics"))).To(gomega.Succeed())
+
+			g.Expect(javaOpts("jfr", "BPL_JFR_ENABLED=true")).
+				To(gomega.Equal("-Dtest -XX:StartFlightRecording=disk=true,dumponexit=true,maxage=1h,filename=/tmp/recording.jfr"))
+			g.Expect(javaOpts("jfr", "BPL_JFR_ENABLED=true", "BPL_HEAP_DUMP_PATH=/dumps")).
+				To(gomega.Equal("-Dtest -XX:StartFlightRecording=disk=true,dumponexit=true,maxage=1h,filename=/dumps/recording.jfr"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"os"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+)
+
+// LayerSchemaVersion is the version of the semantics (e.g. class path ordering and slicing) of the metadata persisted
+// for reuse of the Spring Boot layer.  It must be incremented whenever those semantics change, so that layers
+// contributed by an earlier version of the buildpack are contributed again rather than reused.
+const LayerSchemaVersion = "2"
+
+// LayerMetadata is the metadata persisted for reuse of the Spring Boot layer.
+type LayerMetadata struct {
+	Metadata
+
+	// SchemaVersion is the LayerSchemaVersion that the layer was contributed with.
+	SchemaVersion string `toml:"schema-version"`
+}
+
+// NewLayerMetadata creates a new LayerMetadata instance for the current LayerSchemaVersion.
+func NewLayerMetadata(metadata Metadata) LayerMetadata {
+	return LayerMetadata{metadata, LayerSchemaVersion}
+}
+
+// Migrate invalidates a layer whose metadata was persisted with a schema-version other than version, including layers
+// persisted before their metadata had a schema-version, by removing its contents and metadata.
+func Migrate(layer layers.Layer, version string) error {
+	if exists, err := helper.FileExists(layer.Metadata); err != nil || !exists {
+		return err
+	}
+
+	var m struct {
+		SchemaVersion string `toml:"schema-version"`
+	}
+	if err := layer.ReadMetadata(&m); err == nil && m.SchemaVersion == version {
+		return nil
+	}
+
+	layer.Logger.Body("Invalidating layer contributed with schema version %q, expected %q", m.SchemaVersion, version)
+
+	if err := os.RemoveAll(layer.Root); err != nil {
+		return err
+	}
+
+	return os.RemoveAll(layer.Metadata)
+}
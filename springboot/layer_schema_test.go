@@ -0,0 +1,90 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestLayerSchema(t *testing.T) {
+	spec.Run(t, "Layer Schema", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			f     *test.BuildFactory
+			layer layers.Layer
+		)
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+			layer = f.Build.Layers.Layer("test-layer")
+		})
+
+		it("round-trips layer metadata", func() {
+			m := springboot.NewLayerMetadata(springboot.Metadata{
+				ClassPath: []string{"test-class-path"},
+				Version:   "2.2.5.RELEASE",
+			})
+			g.Expect(layer.WriteMetadata(m)).To(gomega.Succeed())
+
+			g.Expect(layer.MetadataMatches(m)).To(gomega.BeTrue())
+		})
+
+		it("does nothing without metadata", func() {
+			g.Expect(springboot.Migrate(layer, springboot.LayerSchemaVersion)).To(gomega.Succeed())
+		})
+
+		it("keeps layer with current schema version", func() {
+			g.Expect(layer.WriteMetadata(springboot.NewLayerMetadata(springboot.Metadata{}))).To(gomega.Succeed())
+			test.TouchFile(t, layer.Root, "test-file")
+
+			g.Expect(springboot.Migrate(layer, springboot.LayerSchemaVersion)).To(gomega.Succeed())
+
+			g.Expect(layer.Metadata).To(gomega.BeARegularFile())
+			g.Expect(filepath.Join(layer.Root, "test-file")).To(gomega.BeARegularFile())
+		})
+
+		it("invalidates layer with other schema version", func() {
+			g.Expect(layer.WriteMetadata(springboot.LayerMetadata{SchemaVersion: "0"})).To(gomega.Succeed())
+			test.TouchFile(t, layer.Root, "test-file")
+
+			g.Expect(springboot.Migrate(layer, springboot.LayerSchemaVersion)).To(gomega.Succeed())
+
+			g.Expect(layer.Metadata).NotTo(gomega.BeAnExistingFile())
+			g.Expect(layer.Root).NotTo(gomega.BeADirectory())
+		})
+
+		it("invalidates layer without schema version", func() {
+			g.Expect(layer.WriteMetadata(springboot.Metadata{Version: "2.2.5.RELEASE"})).To(gomega.Succeed())
+			test.TouchFile(t, layer.Root, "test-file")
+
+			g.Expect(springboot.Migrate(layer, springboot.LayerSchemaVersion)).To(gomega.Succeed())
+
+			g.Expect(layer.Metadata).NotTo(gomega.BeAnExistingFile())
+			g.Expect(layer.Root).NotTo(gomega.BeADirectory())
+		})
+	}, spec.Report(report.Terminal{}))
+}
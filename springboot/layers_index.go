@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LayersIndex is the parsed contents of a Spring Boot layers.idx file.  Each layer lists the files and directories,
+// relative to the application root, that it contains.
+type LayersIndex []IndexedLayer
+
+// IndexedLayer is a single layer of a LayersIndex.
+type IndexedLayer struct {
+	// Name is the name of the layer.
+	Name string
+
+	// Paths are the files, and directories ending in "/", that the layer contains.
+	Paths []string
+}
+
+// Layer returns the name of the first layer that contains a path, returning false if no layer contains it.
+func (l LayersIndex) Layer(path string) (string, bool) {
+	for _, i := range l {
+		for _, p := range i.Paths {
+			if path == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+				return i.Name, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// NewLayersIndex parses a layers.idx file, in which each layer is a line of the form `- "<name>":` followed by lines
+// of the form `  - "<path>"`.
+func NewLayersIndex(path string) (LayersIndex, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var l LayersIndex
+
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			continue
+		case strings.HasPrefix(line, "- ") && strings.HasSuffix(line, ":"):
+			l = append(l, IndexedLayer{Name: unquote(strings.TrimSuffix(strings.TrimPrefix(line, "- "), ":"))})
+		case strings.HasPrefix(line, "  - ") && len(l) > 0:
+			l[len(l)-1].Paths = append(l[len(l)-1].Paths, unquote(strings.TrimPrefix(line, "  - ")))
+		default:
+			return nil, fmt.Errorf("invalid layers index %s line %d: %s", path, n, line)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func unquote(s string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), `"`), `"`)
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestLayersIndex(t *testing.T) {
+	spec.Run(t, "LayersIndex", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var path string
+
+		it.Before(func() {
+			path = filepath.Join(test.ScratchDir(t, "layers-index"), "layers.idx")
+		})
+
+		it("parses layers", func() {
+			test.WriteFile(t, path, `- "dependencies":
+  - "BOOT-INF/lib/"
+- "spring-boot-loader":
+  - "org/"
+- "snapshot-dependencies":
+- "application":
+  - "BOOT-INF/classes/"
+  - "BOOT-INF/layers.idx"
+`)
+
+			g.Expect(springboot.NewLayersIndex(path)).To(gomega.Equal(springboot.LayersIndex{
+				{Name: "dependencies", Paths: []string{"BOOT-INF/lib/"}},
+				{Name: "spring-boot-loader", Paths: []string{"org/"}},
+				{Name: "snapshot-dependencies"},
+				{Name: "application", Paths: []string{"BOOT-INF/classes/", "BOOT-INF/layers.idx"}},
+			}))
+		})
+
+		it("returns error for invalid index", func() {
+			test.WriteFile(t, path, "test-line")
+
+			_, err := springboot.NewLayersIndex(path)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("line 1: test-line")))
+		})
+
+		it("returns first layer containing path", func() {
+			l := springboot.LayersIndex{
+				{Name: "test-1", Paths: []string{"BOOT-INF/lib/test.jar"}},
+				{Name: "test-2", Paths: []string{"BOOT-INF/lib/"}},
+			}
+
+			n, ok := l.Layer("BOOT-INF/lib/test.jar")
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(n).To(gomega.Equal("test-1"))
+
+			n, ok = l.Layer("BOOT-INF/lib/other.jar")
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(n).To(gomega.Equal("test-2"))
+
+			_, ok = l.Layer("BOOT-INF/lib")
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+	}, spec.Report(report.Terminal{}))
+}
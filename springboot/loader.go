@@ -0,0 +1,88 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/buildpacks/libbuildpack/v2/application"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+)
+
+const loaderPackage = "org.springframework.boot.loader."
+
+// Loader describes the Spring Boot loader of an application and any inconsistencies in its packaging.
+type Loader struct {
+	// Diagnostics are descriptions of packaging inconsistencies.
+	Diagnostics []string `mapstructure:"diagnostics" toml:"diagnostics"`
+
+	// LibVersion is the version of the spring-boot JAR in Spring-Boot-Lib.
+	LibVersion string `mapstructure:"lib-version" toml:"lib-version"`
+
+	// MainClass is the Main-Class of the application.
+	MainClass string `mapstructure:"main-class" toml:"main-class"`
+
+	// Present indicates whether the Main-Class is present in the application.
+	Present bool `mapstructure:"present" toml:"present"`
+}
+
+// NewLoader creates a new Loader instance, diagnosing inconsistencies between the manifest and the contents of the
+// application.
+func NewLoader(application application.Application, metadata Metadata, logger logger.Logger) (Loader, error) {
+	m, err := NewManifest(application, logger)
+	if err != nil {
+		return Loader{}, err
+	}
+
+	l := Loader{Diagnostics: []string{}, MainClass: m.GetString("Main-Class", "")}
+
+	if l.MainClass != "" {
+		c := filepath.Join(application.Root, strings.ReplaceAll(l.MainClass, ".", string(filepath.Separator))+".class")
+		if l.Present, err = helper.FileExists(c); err != nil {
+			return Loader{}, err
+		}
+
+		if !l.Present && strings.HasPrefix(l.MainClass, loaderPackage) {
+			l.Diagnostics = append(l.Diagnostics, fmt.Sprintf(
+				"Main-Class %s does not exist; the application may have been repackaged by a plugin other than Spring Boot's",
+				l.MainClass))
+		}
+	}
+
+	for _, d := range [][]string{{"Spring-Boot-Classes", metadata.Classes}, {"Spring-Boot-Lib", metadata.Lib}} {
+		if d[1] == "" {
+			continue
+		}
+
+		if ok, err := helper.FileExists(filepath.Join(application.Root, d[1])); err != nil {
+			return Loader{}, err
+		} else if !ok {
+			l.Diagnostics = append(l.Diagnostics, fmt.Sprintf("%s %s does not exist", d[0], d[1]))
+		}
+	}
+
+	l.LibVersion, _ = FindJARDependency(metadata.ClassPath, "spring-boot")
+	if l.LibVersion != "" && l.LibVersion != metadata.Version {
+		l.Diagnostics = append(l.Diagnostics, fmt.Sprintf(
+			"Spring-Boot-Version %s does not match spring-boot %s in Spring-Boot-Lib", metadata.Version, l.LibVersion))
+	}
+
+	return l, nil
+}
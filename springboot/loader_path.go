@@ -0,0 +1,174 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/manifest"
+)
+
+// LoaderProperties is the file, in the application root or Spring-Boot-Classes, that configures the
+// PropertiesLauncher.
+const LoaderProperties = "loader.properties"
+
+// isPropertiesLauncher returns true if a Main-Class is the Spring Boot PropertiesLauncher.
+func isPropertiesLauncher(mainClass string) bool {
+	return strings.HasPrefix(mainClass, loaderPackage) && strings.HasSuffix(mainClass, ".PropertiesLauncher")
+}
+
+// loaderPath returns the loader.path entries of a PropertiesLauncher application.  As with the PropertiesLauncher,
+// loader.properties in the application root is preferred to loader.properties in Spring-Boot-Classes, which is
+// preferred to the Loader-Path manifest header.  Entries that contain placeholders or refer to nested archives cannot
+// be resolved at build time and are skipped.
+func loaderPath(root string, classes string, m manifest.Manifest, logger logger.Logger) ([]string, error) {
+	s, ok := "", false
+
+	for _, f := range []string{filepath.Join(root, LoaderProperties), filepath.Join(root, classes, LoaderProperties)} {
+		var err error
+		if s, ok, err = readProperty(f, "loader.path"); err != nil {
+			return nil, err
+		} else if ok {
+			break
+		}
+	}
+
+	if !ok {
+		s = m.GetString("Loader-Path", "")
+	}
+
+	var p []string
+	for _, e := range strings.Split(s, ",") {
+		e = strings.TrimPrefix(strings.TrimSpace(e), "file:")
+		if e == "" {
+			continue
+		}
+
+		if strings.Contains(e, "${") || strings.Contains(e, "!") {
+			logger.BodyWarning("Unable to resolve loader.path entry %s at build time", e)
+			continue
+		}
+
+		p = append(p, filepath.Clean(e))
+	}
+
+	return p, nil
+}
+
+// readProperty returns the value of a key in a properties file.
+func readProperty(file string, key string) (string, bool, error) {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		l := strings.TrimSpace(s.Text())
+		if l == "" || strings.HasPrefix(l, "#") || strings.HasPrefix(l, "!") {
+			continue
+		}
+
+		i := strings.IndexAny(l, "=:")
+		if i < 0 || strings.TrimSpace(l[:i]) != key {
+			continue
+		}
+
+		return strings.TrimSpace(l[i+1:]), true, nil
+	}
+
+	return "", false, s.Err()
+}
+
+// loaderClassPath returns the class path contributed by the loader.path entries and the JARs that are not part of it.
+// Directories contribute themselves, for resources, followed by the JARs they contain.
+func (m Metadata) loaderClassPath(root string, jars []string) ([]string, []string, error) {
+	var cp []string
+	remaining := append([]string{}, jars...)
+
+	take := func(match func(string) bool) {
+		var r []string
+		for _, j := range remaining {
+			if match(j) {
+				cp = append(cp, j)
+			} else {
+				r = append(r, j)
+			}
+		}
+		remaining = r
+	}
+
+	for _, e := range m.LoaderPath {
+		if !filepath.IsAbs(e) {
+			e = filepath.Join(root, e)
+		}
+
+		if filepath.Ext(e) == ".jar" {
+			n := len(cp)
+			take(func(j string) bool { return j == e })
+			if len(cp) == n {
+				cp = append(cp, e)
+			}
+			continue
+		}
+
+		cp = append(cp, e)
+
+		if strings.HasPrefix(e, root+string(filepath.Separator)) {
+			take(func(j string) bool { return strings.HasPrefix(j, e+string(filepath.Separator)) })
+			continue
+		}
+
+		if ok, err := helper.FileExists(e); err != nil {
+			return nil, nil, err
+		} else if !ok {
+			continue
+		}
+
+		j, err := helper.FindFiles(e, regexp.MustCompile(".*\\.jar$"))
+		if err != nil {
+			return nil, nil, err
+		}
+		cp = append(cp, j...)
+	}
+
+	return cp, remaining, nil
+}
+
+// inLoaderPath returns true if a path, relative to the application root, is in a loader.path entry.
+func (m Metadata) inLoaderPath(path string) bool {
+	for _, e := range m.LoaderPath {
+		if filepath.IsAbs(e) {
+			continue
+		}
+
+		if path == e || strings.HasPrefix(path, e+"/") {
+			return true
+		}
+	}
+
+	return false
+}
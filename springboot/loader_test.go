@@ -0,0 +1,90 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestLoader(t *testing.T) {
+	spec.Run(t, "Loader", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.DetectFactory
+
+		it.Before(func() {
+			f = test.NewDetectFactory(t)
+		})
+
+		metadata := func() springboot.Metadata {
+			md, ok, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			return md
+		}
+
+		it("has no diagnostics for consistent application", func() {
+			test.TouchFile(t, f.Detect.Application.Root, "org", "springframework", "boot", "loader", "JarLauncher.class")
+			test.TouchFile(t, f.Detect.Application.Root, "test-classes", "Test.class")
+			test.TouchFile(t, f.Detect.Application.Root, "test-lib", "spring-boot-2.2.5.RELEASE.jar")
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Main-Class: org.springframework.boot.loader.JarLauncher
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: 2.2.5.RELEASE`)
+
+			l, err := springboot.NewLoader(f.Detect.Application, metadata(), f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(l).To(gomega.Equal(springboot.Loader{
+				Diagnostics: []string{},
+				LibVersion:  "2.2.5.RELEASE",
+				MainClass:   "org.springframework.boot.loader.JarLauncher",
+				Present:     true,
+			}))
+		})
+
+		it("diagnoses missing loader and version mismatch", func() {
+			test.TouchFile(t, f.Detect.Application.Root, "test-classes", "Test.class")
+			test.TouchFile(t, f.Detect.Application.Root, "test-lib", "spring-boot-2.1.0.RELEASE.jar")
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Main-Class: org.springframework.boot.loader.JarLauncher
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: 2.2.5.RELEASE`)
+
+			l, err := springboot.NewLoader(f.Detect.Application, metadata(), f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(l.Present).To(gomega.BeFalse())
+			g.Expect(l.Diagnostics).To(gomega.ConsistOf(
+				"Main-Class org.springframework.boot.loader.JarLauncher does not exist; the application may have been repackaged by a plugin other than Spring Boot's",
+				"Spring-Boot-Version 2.2.5.RELEASE does not match spring-boot 2.1.0.RELEASE in Spring-Boot-Lib",
+			))
+		})
+	}, spec.Report(report.Terminal{}))
+}
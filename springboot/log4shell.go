@@ -0,0 +1,68 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"github.com/Masterminds/semver"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+// log4Shell matches the log4j-core versions vulnerable to JNDI lookups in log messages (CVE-2021-44228 and
+// CVE-2021-45046).
+var log4Shell = DenyListEntry{Name: "log4j-core", Constraint: mustConstraint("<2.16"), raw: "log4j-core:<2.16"}
+
+// Log4ShellMitigation disables message lookups in log4j-core versions vulnerable to Log4Shell.  It is defense in depth
+// while the dependency is upgraded and has no effect before log4j-core 2.10.
+type Log4ShellMitigation struct {
+	// Version is the version of the vulnerable log4j-core.
+	Version string `toml:"version"`
+}
+
+func (l Log4ShellMitigation) Identity() (string, string) {
+	return "Log4Shell Mitigation", l.Version
+}
+
+// Contribute appends -Dlog4j2.formatMsgNoLookups=true to $JAVA_OPTS in a layer marked launch.
+func (l Log4ShellMitigation) Contribute(layer layers.Layer) error {
+	return layer.Contribute(l, func(layer layers.Layer) error {
+		if err := layer.AppendLaunchEnv("JAVA_OPTS", " -Dlog4j2.formatMsgNoLookups=true"); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewLog4ShellMitigation creates a new Log4ShellMitigation instance.  OK is true if the class path contains log4j-core
+// earlier than 2.16.
+func NewLog4ShellMitigation(metadata Metadata) (Log4ShellMitigation, bool) {
+	v, ok := FindJARDependency(metadata.ClassPath, log4Shell.Name)
+	if !ok || !log4Shell.Matches(JARDependency{Name: log4Shell.Name, Version: v}) {
+		return Log4ShellMitigation{}, false
+	}
+
+	return Log4ShellMitigation{v}, true
+}
+
+func mustConstraint(c string) *semver.Constraints {
+	s, err := semver.NewConstraint(c)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestLog4ShellMitigation(t *testing.T) {
+	spec.Run(t, "Log4ShellMitigation", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		it("returns false without log4j-core", func() {
+			_, ok := springboot.NewLog4ShellMitigation(springboot.Metadata{
+				ClassPath: []string{"/test-lib/log4j-api-2.14.1.jar"},
+			})
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("returns false for patched log4j-core", func() {
+			_, ok := springboot.NewLog4ShellMitigation(springboot.Metadata{
+				ClassPath: []string{"/test-lib/log4j-core-2.16.0.jar"},
+			})
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("contributes mitigation for vulnerable log4j-core", func() {
+			f := test.NewBuildFactory(t)
+
+			m, ok := springboot.NewLog4ShellMitigation(springboot.Metadata{
+				ClassPath: []string{"/test-lib/log4j-core-2.14.1.jar"},
+			})
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(m.Version).To(gomega.Equal("2.14.1"))
+
+			layer := f.Build.Layers.Layer("log4shell-mitigation")
+			g.Expect(m.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(layer).To(test.HaveAppendLaunchEnvironment("JAVA_OPTS", " -Dlog4j2.formatMsgNoLookups=true"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// LoggingConfigLayer is the name of the layer containing logging configuration from a binding.
+	LoggingConfigLayer = "logging-config"
+
+	// LoggingConfigService is the filter used to find a binding that provides logging configuration.  The binding must
+	// have a logback.xml or log4j2.xml credential.
+	LoggingConfigService = "logging-config"
+)
+
+var loggingConfigFiles = []string{"log4j2.xml", "logback.xml"}
+
+// LoggingConfig is logging configuration, such as structured JSON logging, that is placed on the class path ahead of
+// the application so that it takes precedence over configuration packaged in the application.
+type LoggingConfig struct {
+	// Files are the contents of the configuration files, keyed by file name.
+	Files map[string]string `toml:"files"`
+}
+
+func (l LoggingConfig) Identity() (string, string) {
+	return "Logging Configuration", fmt.Sprintf("(%d files)", len(l.Files))
+}
+
+// Contribute writes the configuration files to a layer marked launch.
+func (l LoggingConfig) Contribute(layer layers.Layer) error {
+	return layer.Contribute(l, func(layer layers.Layer) error {
+		for n, c := range l.Files {
+			if err := helper.WriteFile(filepath.Join(layer.Root, n), 0644, "%s", c); err != nil {
+				return err
+			}
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewLoggingConfig creates a new LoggingConfig instance from a "logging-config" binding.  OK is false if no binding
+// with a logback.xml or log4j2.xml credential exists.
+func NewLoggingConfig(build build.Build) (LoggingConfig, bool) {
+	c, ok := build.Services.FindServiceCredentials(LoggingConfigService)
+	if !ok {
+		return LoggingConfig{}, false
+	}
+
+	l := LoggingConfig{Files: make(map[string]string)}
+	for _, n := range loggingConfigFiles {
+		if v, ok := c[n]; ok {
+			l.Files[n] = fmt.Sprintf("%s", v)
+		}
+	}
+
+	return l, len(l.Files) > 0
+}
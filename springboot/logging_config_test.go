@@ -0,0 +1,72 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestLoggingConfig(t *testing.T) {
+	spec.Run(t, "LoggingConfig", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("returns false without binding", func() {
+			_, ok := springboot.NewLoggingConfig(f.Build)
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("returns false without configuration files", func() {
+			f.AddService("logging-config", map[string]interface{}{"test-key": "test-value"})
+
+			_, ok := springboot.NewLoggingConfig(f.Build)
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("contributes configuration files", func() {
+			f.AddService("logging-config", map[string]interface{}{
+				"logback.xml": "<configuration/>",
+				"log4j2.xml":  "<Configuration/>",
+				"test-key":    "test-value",
+			})
+
+			l, ok := springboot.NewLoggingConfig(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+
+			layer := f.Build.Layers.Layer(springboot.LoggingConfigLayer)
+			g.Expect(l.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(filepath.Join(layer.Root, "logback.xml")).To(test.HaveContent("<configuration/>"))
+			g.Expect(filepath.Join(layer.Root, "log4j2.xml")).To(test.HaveContent("<Configuration/>"))
+			g.Expect(filepath.Join(layer.Root, "test-key")).NotTo(gomega.BeAnExistingFile())
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/buildpacks/libbuildpack/v2/application"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/manifest"
+	"github.com/magiconair/properties"
+)
+
+// ManifestLimit is the maximum size, in bytes, of a META-INF/MANIFEST.MF that is read.
+const ManifestLimit = 1024 * 1024
+
+// NewManifest reads the main section of the META-INF/MANIFEST.MF of an application, streaming it rather than reading
+// it whole, and returns an error if it is larger than ManifestLimit.  Per-entry sections, which follow the first blank
+// line, are ignored.  An application without a manifest has an empty one.
+func NewManifest(application application.Application, logger logger.Logger) (manifest.Manifest, error) {
+	f := filepath.Join(application.Root, "META-INF", "MANIFEST.MF")
+
+	in, err := os.Open(f)
+	if os.IsNotExist(err) {
+		return manifest.Manifest{Properties: properties.NewProperties()}, nil
+	} else if err != nil {
+		return manifest.Manifest{}, err
+	}
+	defer in.Close()
+
+	p, err := parseManifest(io.LimitReader(in, ManifestLimit+1))
+	if err != nil {
+		return manifest.Manifest{}, fmt.Errorf("unable to read %s: %w", f, err)
+	}
+
+	m := manifest.Manifest{Properties: p}
+	logger.Debug("Manifest: %s", m)
+	return m, nil
+}
+
+// parseManifest parses the main section of a manifest as java.util.jar.Manifest does.  Lines end with CR LF, LF, or CR
+// and lines that begin with a space continue the value of the previous header, no matter where the writer wrapped them
+// (e.g. at 72 bytes, in the middle of a multi-byte UTF-8 character).  Values are decoded as UTF-8, with invalid
+// sequences replaced, and a leading byte order mark is ignored.
+func parseManifest(in io.Reader) (*properties.Properties, error) {
+	p := properties.NewProperties()
+	p.DisableExpansion = true
+
+	var (
+		key   string
+		value []byte
+		n     int
+	)
+
+	set := func() error {
+		if key == "" {
+			return nil
+		}
+
+		_, _, err := p.Set(key, strings.TrimSpace(strings.ToValidUTF8(string(value), "\uFFFD")))
+		key, value = "", nil
+		return err
+	}
+
+	r := bufio.NewReader(in)
+	for number := 1; ; number++ {
+		line, c, err := readManifestLine(r)
+		n += c
+		if n > ManifestLimit {
+			return nil, fmt.Errorf("manifest is larger than %d bytes", ManifestLimit)
+		}
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		if number == 1 {
+			line = bytes.TrimPrefix(line, []byte("\uFEFF"))
+		}
+
+		switch {
+		case len(line) > 0 && line[0] == ' ':
+			if key == "" {
+				return nil, fmt.Errorf("continuation line %d does not follow a header", number)
+			}
+			value = append(value, line[1:]...)
+		case len(line) == 0:
+			if p.Len() > 0 || key != "" {
+				return p, set()
+			}
+		default:
+			if err := set(); err != nil {
+				return nil, err
+			}
+
+			i := bytes.IndexByte(line, ':')
+			if i < 0 {
+				return nil, fmt.Errorf("invalid header on line %d: %q", number, line)
+			}
+			key, value = strings.TrimSpace(string(line[:i])), append([]byte(nil), line[i+1:]...)
+		}
+
+		if err == io.EOF {
+			return p, set()
+		}
+	}
+}
+
+// readManifestLine reads a line, without its terminator, and the number of bytes consumed.  Lines end with CR LF, LF,
+// or CR.  io.EOF is returned with the last line.
+func readManifestLine(r *bufio.Reader) ([]byte, int, error) {
+	var (
+		line []byte
+		n    int
+	)
+
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return line, n, err
+		}
+		n++
+
+		switch b {
+		case '\n':
+			return line, n, nil
+		case '\r':
+			if next, err := r.Peek(1); err == nil && next[0] == '\n' {
+				_, _ = r.ReadByte()
+				n++
+			}
+			return line, n, nil
+		default:
+			line = append(line, b)
+		}
+	}
+}
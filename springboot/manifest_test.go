@@ -0,0 +1,132 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestManifest(t *testing.T) {
+	spec.Run(t, "Manifest", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			f    *test.DetectFactory
+			path string
+		)
+
+		it.Before(func() {
+			f = test.NewDetectFactory(t)
+			path = filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF")
+		})
+
+		it("returns empty manifest without META-INF/MANIFEST.MF", func() {
+			m, err := springboot.NewManifest(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(m.Len()).To(gomega.Equal(0))
+		})
+
+		it("reads main section", func() {
+			test.WriteFile(t, path, "\r\nManifest-Version: 1.0\r\nStart-Class: test.\r\n Start\r\nLoader-Path: ${test}\r\n\r\nName: test/\r\nStart-Class: other\r\n")
+
+			m, err := springboot.NewManifest(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(m.Map()).To(gomega.Equal(map[string]string{
+				"Manifest-Version": "1.0",
+				"Start-Class":      "test.Start",
+				"Loader-Path":      "${test}",
+			}))
+		})
+
+		it("reads manifest without trailing newline", func() {
+			test.WriteFile(t, path, "Spring-Boot-Version: 2.2.5.RELEASE")
+
+			m, err := springboot.NewManifest(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(m.GetString("Spring-Boot-Version", "")).To(gomega.Equal("2.2.5.RELEASE"))
+		})
+
+		it("reads long values wrapped at 72 bytes", func() {
+			c := "com.example." + strings.Repeat("verylongpackagename.", 8) + "Application"
+			v := "Start-Class: " + c
+
+			var m strings.Builder
+			m.WriteString(v[:70] + "\r\n")
+			for v = v[70:]; len(v) > 69; v = v[69:] {
+				m.WriteString(" " + v[:69] + "\r\n")
+			}
+			m.WriteString(" " + v + "\r\n")
+			test.WriteFile(t, path, "%s", m.String())
+
+			md, err := springboot.NewManifest(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(md.GetString("Start-Class", "")).To(gomega.Equal(c))
+		})
+
+		it("reads UTF-8 values split across lines", func() {
+			test.WriteFile(t, path, "\xef\xbb\xbfImplementation-Title: Gr\xc3\n \xbc\xc3\x9fe\nBuilt-By: \xff\n")
+
+			m, err := springboot.NewManifest(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(m.Map()).To(gomega.Equal(map[string]string{
+				"Implementation-Title": "Grüße",
+				"Built-By":             "\uFFFD",
+			}))
+		})
+
+		it("reads lines ending with CR", func() {
+			test.WriteFile(t, path, "Manifest-Version: 1.0\rStart-Class: test.\r Start\r\rName: test/\r")
+
+			m, err := springboot.NewManifest(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(m.Map()).To(gomega.Equal(map[string]string{
+				"Manifest-Version": "1.0",
+				"Start-Class":      "test.Start",
+			}))
+		})
+
+		it("returns error for header without colon", func() {
+			test.WriteFile(t, path, "Manifest-Version: 1.0\nStart-Class\n")
+
+			_, err := springboot.NewManifest(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring(`invalid header on line 2: "Start-Class"`)))
+		})
+
+		it("returns error for continuation line without header", func() {
+			test.WriteFile(t, path, " test.Start\n")
+
+			_, err := springboot.NewManifest(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("continuation line 1 does not follow a header")))
+		})
+
+		it("returns error for manifest larger than limit", func() {
+			test.WriteFile(t, path, "Test-Key: %s\n", strings.Repeat("x", springboot.ManifestLimit))
+
+			_, err := springboot.NewManifest(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("manifest is larger than 1048576 bytes")))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -17,26 +17,85 @@
 package springboot
 
 import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 
+	"github.com/Masterminds/semver"
 	"github.com/buildpacks/libbuildpack/v2/application"
 	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
 	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
 	"github.com/cloudfoundry/libcfbuildpack/v2/manifest"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
 )
 
+// LibProvided is the environment variable that includes the JARs in the provided lib directory in the class path when
+// set to true.
+const LibProvided = "BP_SPRING_BOOT_LIB_PROVIDED"
+
+const (
+	// ApplicationSlice is the slice containing Spring-Boot-Classes.
+	ApplicationSlice = "application"
+
+	// ClassesSlice is the slice containing Spring-Boot-Classes when $BP_SPRING_BOOT_DEV is true.
+	ClassesSlice = "classes"
+
+	// DependencySlice is the slice containing release JARs in Spring-Boot-Lib.
+	DependencySlice = "dependencies"
+
+	// LaunchSlice is the slice containing the Spring Boot loader and other files outside of Spring-Boot-Classes,
+	// Spring-Boot-Lib, and META-INF.
+	LaunchSlice = "launch"
+
+	// ProvidedSlice is the slice containing JARs in the provided lib directory that accompanies Spring-Boot-Lib.
+	ProvidedSlice = "provided-dependencies"
+
+	// RemainderSlice is the slice containing all files not in another slice.
+	RemainderSlice = "remainder"
+
+	// SnapshotSlice is the slice containing SNAPSHOT JARs in Spring-Boot-Lib.
+	SnapshotSlice = "snapshot-dependencies"
+)
+
+// ManifestHeaders are the manifest headers included in plan metadata and, prefixed with ManifestLabelPrefix and
+// lower-cased, labels.
+var ManifestHeaders = []string{"Build-Jdk", "Implementation-Title", "Implementation-Version"}
+
+// ManifestLabelPrefix is the prefix of labels that contain manifest headers.
+const ManifestLabelPrefix = "org.springframework.boot.manifest."
+
 // Metadata describes the application's metadata.
 type Metadata struct {
 	// Classes indicates the Spring-Boot-Classes of a Spring Boot application.
 	Classes string `mapstructure:"classes" properties:"Spring-Boot-Classes,default=" toml:"classes"`
 
+	// IndexedLibs are the directories, other than Spring-Boot-Lib and Spring-Boot-Classes, that contain JARs listed by
+	// the Spring-Boot-Classpath-Index or Spring-Boot-Layers-Index of a Spring Boot application with a custom layout.
+	IndexedLibs []string `mapstructure:"-" properties:"-" toml:"indexed-libs"`
+
+	// ClassPathIndex indicates the Spring-Boot-Classpath-Index of a Spring Boot application.
+	ClassPathIndex string `mapstructure:"classpath-index" properties:"Spring-Boot-Classpath-Index,default=" toml:"classpath-index"`
+
 	// Classpath is the classpath of a Spring Boot application.
 	ClassPath []string `mapstructure:"classpath" properties:",default=" toml:"classpath"`
 
+	// LayersIndex indicates the Spring-Boot-Layers-Index of a Spring Boot application.
+	LayersIndex string `mapstructure:"layers-index" properties:"Spring-Boot-Layers-Index,default=" toml:"layers-index"`
+
 	// Lib indicates the Spring-Boot-Lib of a Spring Boot application.
 	Lib string `mapstructure:"lib" properties:"Spring-Boot-Lib,default=" toml:"lib"`
 
+	// LoaderPath is the loader.path of a Spring Boot application launched by the PropertiesLauncher.
+	LoaderPath []string `mapstructure:"loader-path" properties:"-" toml:"loader-path"`
+
+	// Manifest is the full contents of the META-INF/MANIFEST.MF of a Spring Boot application.
+	Manifest map[string]string `mapstructure:"-" properties:"-" toml:"manifest"`
+
 	// StartClass indicates the Start-Class of a Spring Boot application.
 	StartClass string `mapstructure:"start-class" properties:"Start-Class,default=" toml:"start-class"`
 
@@ -48,29 +107,355 @@ func (m Metadata) Identity() (string, string) {
 	return "Spring Boot", m.Version
 }
 
+// SelectedHeaders returns the ManifestHeaders that are present in the manifest.
+func (m Metadata) SelectedHeaders() map[string]string {
+	h := make(map[string]string)
+
+	for _, k := range ManifestHeaders {
+		if v, ok := m.Manifest[k]; ok && v != "" {
+			h[k] = v
+		}
+	}
+
+	return h
+}
+
+// Libs returns the lib directories of the application: Spring-Boot-Lib followed by the IndexedLibs.
+func (m Metadata) Libs() []string {
+	var l []string
+
+	if m.Lib != "" {
+		l = append(l, m.Lib)
+	}
+
+	return append(l, m.IndexedLibs...)
+}
+
+// ProvidedLibs returns the provided lib directory that accompanies Spring-Boot-Lib (e.g. BOOT-INF/lib-provided for
+// BOOT-INF/lib).  It contains dependencies that are provided by a servlet container and are not required at runtime.
+func (m Metadata) ProvidedLibs() []string {
+	if m.Lib == "" {
+		return nil
+	}
+
+	return []string{strings.TrimSuffix(filepath.Clean(m.Lib), string(filepath.Separator)) + "-provided"}
+}
+
+// Slice returns the name of the slice that a path, relative to the application root, is contributed to.  Paths in a
+// loader.path entry are sliced as if they were in Spring-Boot-Lib.
+func (m Metadata) Slice(path string) string {
+	lib := m.inLib(path) || m.inLoaderPath(path)
+
+	switch {
+	case strings.HasPrefix(path, m.Classes):
+		return ApplicationSlice
+	case m.inProvidedLib(path) && filepath.Ext(path) == ".jar":
+		return ProvidedSlice
+	case lib && filepath.Ext(path) == ".jar" && !strings.Contains(path, "SNAPSHOT"):
+		return DependencySlice
+	case !lib && !strings.HasPrefix(path, "META-INF/"):
+		return LaunchSlice
+	case lib && filepath.Ext(path) == ".jar" && strings.Contains(path, "SNAPSHOT"):
+		return SnapshotSlice
+	default:
+		return RemainderSlice
+	}
+}
+
+// inLib returns true if a path, relative to the application root, is in one of the Libs.  Every path is in the Libs
+// of an application without Spring-Boot-Lib.
+func (m Metadata) inLib(path string) bool {
+	if m.Lib == "" {
+		return true
+	}
+
+	return hasAnyPrefix(path, m.Libs())
+}
+
+// inProvidedLib returns true if a path, relative to the application root, is in one of the ProvidedLibs.
+func (m Metadata) inProvidedLib(path string) bool {
+	for _, p := range m.ProvidedLibs() {
+		if strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func libProvidedEnabled() (bool, error) {
+	return config.LookupBool(LibProvided, false)
+}
+
+// versionMatches returns true if the Spring-Boot-Version satisfies a semver constraint.  Versions that cannot be
+// interpreted numerically never match.
+func (m Metadata) versionMatches(constraint string) bool {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false
+	}
+
+	n := numeric.FindString(m.Version)
+	if n == "" {
+		return false
+	}
+
+	v, err := semver.NewVersion(n)
+	if err != nil {
+		return false
+	}
+
+	return c.Check(v)
+}
+
+func (m *Metadata) discoverStartClass(application application.Application, logger logger.Logger) error {
+	c := filepath.Join(application.Root, m.Classes)
+	if ok, err := helper.FileExists(c); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	s, err := FindStartClasses(c, logger)
+	if err != nil {
+		return err
+	}
+
+	switch len(s) {
+	case 0:
+		logger.BodyWarning("Start-Class not found in manifest and no @SpringBootApplication class with a main method found in %s", m.Classes)
+	case 1:
+		logger.BodyWarning("Start-Class not found in manifest, using %s", s[0])
+		m.StartClass = s[0]
+	default:
+		logger.BodyWarning("Start-Class not found in manifest and multiple candidates found: %s", strings.Join(s, ", "))
+	}
+
+	return nil
+}
+
 // NewMetadata creates a new Metadata returning false if Spring-Boot-Version is not defined.
 func NewMetadata(application application.Application, logger logger.Logger) (Metadata, bool, error) {
-	md := Metadata{}
-
-	m, err := manifest.NewManifest(application, logger)
+	m, err := NewManifest(application, logger)
 	if err != nil {
 		return Metadata{}, false, err
 	}
 
-	if err := m.Decode(&md); err != nil {
+	md, err := decodeManifest(m)
+	if err != nil {
 		return Metadata{}, false, err
 	}
 
 	if md.Version == "" {
+		logger.Debug("Spring-Boot-Version not found in manifest")
 		return Metadata{}, false, nil
 	}
+	logger.Debug("Spring-Boot-Version: %s, Start-Class: %s, Spring-Boot-Classes: %s, Spring-Boot-Lib: %s",
+		md.Version, md.StartClass, md.Classes, md.Lib)
+
+	if md.StartClass == "" && md.Classes != "" {
+		if err := md.discoverStartClass(application, logger); err != nil {
+			return Metadata{}, false, err
+		}
+	}
+
+	if md.IndexedLibs, err = md.indexedLibs(application.Root); err != nil {
+		return Metadata{}, false, err
+	}
 
 	j, err := helper.FindFiles(application.Root, regexp.MustCompile(".*\\.jar$"))
 	if err != nil {
 		return Metadata{}, false, err
 	}
 
+	if ok, err := libProvidedEnabled(); err != nil {
+		return Metadata{}, false, err
+	} else if p := md.ProvidedLibs(); !ok && len(p) > 0 {
+		logger.Debug("Excluding JARs in %s from class path", strings.Join(p, ", "))
+		j = md.withoutProvided(application.Root, j)
+	}
+
+	if md.ClassPathIndex != "" {
+		logger.Debug("Ordering JARs by %s", md.ClassPathIndex)
+		if j, err = md.orderJARs(application.Root, j); err != nil {
+			return Metadata{}, false, err
+		}
+	}
+
+	if isPropertiesLauncher(m.GetString("Main-Class", "")) {
+		if md.LoaderPath, err = loaderPath(application.Root, md.Classes, m, logger); err != nil {
+			return Metadata{}, false, err
+		}
+	}
+
 	md.ClassPath = append(md.ClassPath, filepath.Join(application.Root, md.Classes))
+
+	if len(md.LoaderPath) > 0 {
+		var l []string
+		if l, j, err = md.loaderClassPath(application.Root, j); err != nil {
+			return Metadata{}, false, err
+		}
+		md.ClassPath = append(md.ClassPath, l...)
+	}
+
 	md.ClassPath = append(md.ClassPath, j...)
+	logger.Debug("Class path: %s", strings.Join(md.ClassPath, string(filepath.ListSeparator)))
 	return md, true, nil
 }
+
+// ParseManifest interprets the main section of a META-INF/MANIFEST.MF exactly as the buildpack does, without an
+// application.  Only values declared by the manifest are populated.  ClassPath, LoaderPath, and a Start-Class
+// discovered from Spring-Boot-Classes require the application's files and are empty.  Version is empty if the manifest
+// does not describe a Spring Boot application.
+func ParseManifest(in io.Reader) (Metadata, error) {
+	p, err := parseManifest(io.LimitReader(in, ManifestLimit+1))
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	return decodeManifest(manifest.Manifest{Properties: p})
+}
+
+func decodeManifest(m manifest.Manifest) (Metadata, error) {
+	md := Metadata{}
+
+	if err := m.Decode(&md); err != nil {
+		return Metadata{}, err
+	}
+
+	md.Manifest = m.Map()
+	return md, nil
+}
+
+// withoutProvided removes JARs in the provided lib directories.
+func (m Metadata) withoutProvided(root string, jars []string) []string {
+	var p []string
+	for _, l := range m.ProvidedLibs() {
+		p = append(p, filepath.Join(root, l)+string(filepath.Separator))
+	}
+
+	var r []string
+	for _, j := range jars {
+		if !hasAnyPrefix(j, p) {
+			r = append(r, j)
+		}
+	}
+	return r
+}
+
+// indexedLibs returns the directories of the JARs listed by the Spring-Boot-Classpath-Index and
+// Spring-Boot-Layers-Index that are not in Spring-Boot-Lib, its provided lib directory, or Spring-Boot-Classes, in the
+// order they are first listed.
+func (m Metadata) indexedLibs(root string) ([]string, error) {
+	var jars []string
+
+	if m.ClassPathIndex != "" {
+		c, err := m.classPathIndex(root)
+		if err != nil {
+			return nil, err
+		}
+		jars = append(jars, c...)
+	}
+
+	if m.LayersIndex != "" {
+		i, err := NewLayersIndex(filepath.Join(root, m.LayersIndex))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, l := range i {
+			jars = append(jars, l.Paths...)
+		}
+	}
+
+	var known []string
+	for _, d := range append([]string{m.Lib, m.Classes}, m.ProvidedLibs()...) {
+		if d != "" {
+			known = append(known, path.Clean(filepath.ToSlash(d))+"/")
+		}
+	}
+
+	var l []string
+	for _, j := range jars {
+		d := path.Dir(j)
+		if path.Ext(j) != ".jar" || d == "." || hasAnyPrefix(d+"/", known) {
+			continue
+		}
+
+		known = append(known, d+"/")
+		l = append(l, d)
+	}
+
+	return l, nil
+}
+
+// classPathIndex returns the entries of the Spring-Boot-Classpath-Index, relative to the application root and separated
+// by '/'.  Index entries are either of the form `- "BOOT-INF/lib/<name>.jar"` or, before Spring Boot 2.3.0,
+// `<name>.jar` relative to Spring-Boot-Lib.
+func (m Metadata) classPathIndex(root string) ([]string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(root, m.ClassPathIndex))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read Spring-Boot-Classpath-Index %s: %w", m.ClassPathIndex, err)
+	}
+
+	var e []string
+	for _, l := range strings.Split(string(b), "\n") {
+		l = strings.Trim(strings.TrimPrefix(strings.TrimSpace(l), "- "), `"`)
+		if l == "" {
+			continue
+		}
+
+		if !strings.Contains(l, "/") && m.Lib != "" {
+			l = path.Join(m.Lib, l)
+		}
+
+		e = append(e, path.Clean(l))
+	}
+
+	return e, nil
+}
+
+// orderJARs orders JARs by the Spring-Boot-Classpath-Index.  JARs that are not in the index follow those that are.
+func (m Metadata) orderJARs(root string, jars []string) ([]string, error) {
+	c, err := m.classPathIndex(root)
+	if err != nil {
+		return nil, err
+	}
+
+	rank := make(map[string]int)
+	for _, l := range c {
+		if _, ok := rank[l]; !ok {
+			rank[l] = len(rank)
+		}
+	}
+
+	position := func(jar string) int {
+		rel, err := filepath.Rel(root, jar)
+		if err != nil {
+			return len(rank)
+		}
+
+		if r, ok := rank[filepath.ToSlash(rel)]; ok {
+			return r
+		}
+		return len(rank)
+	}
+
+	o := append([]string{}, jars...)
+	sort.SliceStable(o, func(i, j int) bool {
+		return position(o[i]) < position(o[j])
+	})
+
+	return o, nil
+}
+
+// hasAnyPrefix returns true if s begins with any of the prefixes.
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -18,6 +18,7 @@ package springboot_test
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/cloudfoundry/libcfbuildpack/v2/test"
@@ -63,10 +64,355 @@ Spring-Boot-Version: test-version`)
 					filepath.Join(f.Detect.Application.Root, "test-classes"),
 					filepath.Join(f.Detect.Application.Root, "test-lib", "test.jar"),
 				},
-				Lib:        "test-lib",
+				Lib: "test-lib",
+				Manifest: map[string]string{
+					"Spring-Boot-Classes": "test-classes",
+					"Spring-Boot-Lib":     "test-lib",
+					"Start-Class":         "test-start-class",
+					"Spring-Boot-Version": "test-version",
+				},
 				StartClass: "test-start-class",
 				Version:    "test-version",
 			}))
 		})
+
+		it("selects manifest headers", func() {
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Build-Jdk: 11.0.6
+Created-By: test-created-by
+Implementation-Title: test-title
+Spring-Boot-Version: test-version`)
+
+			md, ok, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(md.Manifest).To(gomega.HaveKeyWithValue("Created-By", "test-created-by"))
+			g.Expect(md.SelectedHeaders()).To(gomega.Equal(map[string]string{
+				"Build-Jdk":            "11.0.6",
+				"Implementation-Title": "test-title",
+			}))
+		})
+
+		it("orders JARs by Spring-Boot-Classpath-Index", func() {
+			test.TouchFile(t, f.Detect.Application.Root, "test-lib", "test-1.jar")
+			test.TouchFile(t, f.Detect.Application.Root, "test-lib", "test-2.jar")
+			test.TouchFile(t, f.Detect.Application.Root, "test-lib", "test-3.jar")
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "test-index", "classpath.idx"), `- "test-lib/test-3.jar"
+- "test-lib/test-1.jar"
+`)
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Classpath-Index: test-index/classpath.idx
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			md, ok, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(md.ClassPath).To(gomega.Equal([]string{
+				filepath.Join(f.Detect.Application.Root, "test-classes"),
+				filepath.Join(f.Detect.Application.Root, "test-lib", "test-3.jar"),
+				filepath.Join(f.Detect.Application.Root, "test-lib", "test-1.jar"),
+				filepath.Join(f.Detect.Application.Root, "test-lib", "test-2.jar"),
+			}))
+		})
+
+		it("orders JARs by legacy Spring-Boot-Classpath-Index", func() {
+			test.TouchFile(t, f.Detect.Application.Root, "test-lib", "test-1.jar")
+			test.TouchFile(t, f.Detect.Application.Root, "test-lib", "test-2.jar")
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "test-index", "classpath.idx"), "test-2.jar\ntest-1.jar\n")
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Classpath-Index: test-index/classpath.idx
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			md, ok, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(md.ClassPath).To(gomega.Equal([]string{
+				filepath.Join(f.Detect.Application.Root, "test-classes"),
+				filepath.Join(f.Detect.Application.Root, "test-lib", "test-2.jar"),
+				filepath.Join(f.Detect.Application.Root, "test-lib", "test-1.jar"),
+			}))
+		})
+
+		when("Main-Class is PropertiesLauncher", func() {
+
+			it.Before(func() {
+				test.TouchFile(t, f.Detect.Application.Root, "test-lib", "test-1.jar")
+				test.TouchFile(t, f.Detect.Application.Root, "test-loader", "test-2-SNAPSHOT.jar")
+				test.TouchFile(t, f.Detect.Application.Root, "test-3.jar")
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Loader-Path: test-manifest
+Main-Class: org.springframework.boot.loader.PropertiesLauncher
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+			})
+
+			it("incorporates loader.path into class path", func() {
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "loader.properties"),
+					"# test-comment\nloader.path=test-loader/, test-3.jar,${test.placeholder},file:/test-external\n")
+
+				md, ok, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(md.LoaderPath).To(gomega.Equal([]string{"test-loader", "test-3.jar", "/test-external"}))
+				g.Expect(md.ClassPath).To(gomega.Equal([]string{
+					filepath.Join(f.Detect.Application.Root, "test-classes"),
+					filepath.Join(f.Detect.Application.Root, "test-loader"),
+					filepath.Join(f.Detect.Application.Root, "test-loader", "test-2-SNAPSHOT.jar"),
+					filepath.Join(f.Detect.Application.Root, "test-3.jar"),
+					"/test-external",
+					filepath.Join(f.Detect.Application.Root, "test-lib", "test-1.jar"),
+				}))
+			})
+
+			it("slices loader.path as Spring-Boot-Lib", func() {
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "test-classes", "loader.properties"), "loader.path: test-loader")
+
+				md, _, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(md.Slice("test-loader/test-2-SNAPSHOT.jar")).To(gomega.Equal(springboot.SnapshotSlice))
+				g.Expect(md.Slice("test-loader/test.properties")).To(gomega.Equal(springboot.RemainderSlice))
+				g.Expect(md.Slice("test-3.jar")).To(gomega.Equal(springboot.LaunchSlice))
+			})
+
+			it("uses Loader-Path manifest header", func() {
+				md, _, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(md.LoaderPath).To(gomega.Equal([]string{"test-manifest"}))
+			})
+		})
+
+		it("ignores loader.properties for other launchers", func() {
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "loader.properties"), "loader.path=test-loader")
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Main-Class: org.springframework.boot.loader.JarLauncher
+Spring-Boot-Version: test-version`)
+
+			md, _, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(md.LoaderPath).To(gomega.BeNil())
+		})
+
+		when("provided lib directory exists", func() {
+
+			it.Before(func() {
+				test.TouchFile(t, f.Detect.Application.Root, "test-lib", "test-1.jar")
+				test.TouchFile(t, f.Detect.Application.Root, "test-lib-provided", "test-2.jar")
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib/
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+			})
+
+			it("excludes provided JARs from class path", func() {
+				md, _, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(md.ProvidedLibs()).To(gomega.Equal([]string{"test-lib-provided"}))
+				g.Expect(md.ClassPath).To(gomega.Equal([]string{
+					filepath.Join(f.Detect.Application.Root, "test-classes"),
+					filepath.Join(f.Detect.Application.Root, "test-lib", "test-1.jar"),
+				}))
+				g.Expect(md.Slice("test-lib-provided/test-2.jar")).To(gomega.Equal(springboot.ProvidedSlice))
+				g.Expect(md.Slice("test-lib/test-1.jar")).To(gomega.Equal(springboot.DependencySlice))
+			})
+
+			it("includes provided JARs in class path when configured", func() {
+				defer test.ReplaceEnv(t, springboot.LibProvided, "true")()
+
+				md, _, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(md.ClassPath).To(gomega.Equal([]string{
+					filepath.Join(f.Detect.Application.Root, "test-classes"),
+					filepath.Join(f.Detect.Application.Root, "test-lib", "test-1.jar"),
+					filepath.Join(f.Detect.Application.Root, "test-lib-provided", "test-2.jar"),
+				}))
+			})
+
+			it("returns error for invalid value", func() {
+				defer test.ReplaceEnv(t, springboot.LibProvided, "test-value")()
+
+				_, _, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+				g.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+
+		when("indexes list JARs outside of Spring-Boot-Lib", func() {
+
+			it.Before(func() {
+				test.TouchFile(t, f.Detect.Application.Root, "test-lib", "test-1.jar")
+				test.TouchFile(t, f.Detect.Application.Root, "test-lib-provided", "test-2.jar")
+				test.TouchFile(t, f.Detect.Application.Root, "test-extra", "test-3-SNAPSHOT.jar")
+				test.TouchFile(t, f.Detect.Application.Root, "test-layered", "test-4.jar")
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "test-index", "classpath.idx"), `- "test-lib/test-1.jar"
+- "test-extra/test-3-SNAPSHOT.jar"
+`)
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "test-index", "layers.idx"), `- "dependencies":
+  - "test-lib/"
+  - "test-layered/test-4.jar"
+- "application":
+  - "test-classes/"
+  - "META-INF/"
+`)
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Classpath-Index: test-index/classpath.idx
+Spring-Boot-Layers-Index: test-index/layers.idx
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+			})
+
+			it("returns Spring-Boot-Lib and indexed directories as libs", func() {
+				md, _, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(md.Libs()).To(gomega.Equal([]string{"test-lib", "test-extra", "test-layered"}))
+				g.Expect(md.ProvidedLibs()).To(gomega.Equal([]string{"test-lib-provided"}))
+			})
+
+			it("slices JARs of every lib", func() {
+				md, _, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(md.Slice("test-lib/test-1.jar")).To(gomega.Equal(springboot.DependencySlice))
+				g.Expect(md.Slice("test-extra/test-3-SNAPSHOT.jar")).To(gomega.Equal(springboot.SnapshotSlice))
+				g.Expect(md.Slice("test-layered/test-4.jar")).To(gomega.Equal(springboot.DependencySlice))
+				g.Expect(md.Slice("test-lib-provided/test-2.jar")).To(gomega.Equal(springboot.ProvidedSlice))
+				g.Expect(md.Slice("test-other/test-5.jar")).To(gomega.Equal(springboot.LaunchSlice))
+			})
+		})
+
+		it("treats Spring-Boot-Lib as a single directory", func() {
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib,test-extra
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			md, _, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(md.Libs()).To(gomega.Equal([]string{"test-lib,test-extra"}))
+		})
+
+		it("returns error for missing Spring-Boot-Classpath-Index", func() {
+			test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classpath-Index: test-index/classpath.idx
+Spring-Boot-Version: test-version`)
+
+			_, _, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		when("Start-Class is missing", func() {
+
+			it.Before(func() {
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Spring-Boot-Version: test-version`)
+			})
+
+			it("uses @SpringBootApplication class with main method", func() {
+				test.CopyDirectory(t, filepath.Join("testdata", "main_class"), filepath.Join(f.Detect.Application.Root, "test-classes"))
+
+				md, ok, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(md.StartClass).To(gomega.Equal("test.Application"))
+			})
+
+			it("does not choose between multiple candidates", func() {
+				test.CopyFile(t, filepath.Join("testdata", "main_class", "test", "Application.class"),
+					filepath.Join(f.Detect.Application.Root, "test-classes", "test", "one", "Application.class"))
+				test.CopyFile(t, filepath.Join("testdata", "main_class", "test", "Application.class"),
+					filepath.Join(f.Detect.Application.Root, "test-classes", "test", "two", "Application.class"))
+
+				md, ok, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(md.StartClass).To(gomega.BeEmpty())
+			})
+
+			it("skips invalid class files", func() {
+				test.CopyFile(t, filepath.Join("testdata", "main_class", "test", "Application.class"),
+					filepath.Join(f.Detect.Application.Root, "test-classes", "test", "Application.class"))
+				test.WriteFile(t, filepath.Join(f.Detect.Application.Root, "test-classes", "test", "Invalid.class"), "test")
+
+				md, ok, err := springboot.NewMetadata(f.Detect.Application, f.Detect.Logger)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(md.StartClass).To(gomega.Equal("test.Application"))
+			})
+		})
+
+		when("ParseManifest", func() {
+
+			it("parses manifest without an application", func() {
+				md, err := springboot.ParseManifest(strings.NewReader(`Manifest-Version: 1.0
+Spring-Boot-Classes: BOOT-INF/classes/
+Spring-Boot-Lib: BOOT-INF/lib/
+Start-Class: test.Applica
+ tion
+Spring-Boot-Version: 2.3.0.RELEASE
+
+Name: test-entry
+Spring-Boot-Version: test-entry-version
+`))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(md).To(gomega.Equal(springboot.Metadata{
+					Classes:    "BOOT-INF/classes/",
+					ClassPath:  []string{},
+					Lib:        "BOOT-INF/lib/",
+					StartClass: "test.Application",
+					Version:    "2.3.0.RELEASE",
+					Manifest: map[string]string{
+						"Manifest-Version":    "1.0",
+						"Spring-Boot-Classes": "BOOT-INF/classes/",
+						"Spring-Boot-Lib":     "BOOT-INF/lib/",
+						"Start-Class":         "test.Application",
+						"Spring-Boot-Version": "2.3.0.RELEASE",
+					},
+				}))
+			})
+
+			it("returns empty version for non Spring Boot manifest", func() {
+				md, err := springboot.ParseManifest(strings.NewReader("Manifest-Version: 1.0\n"))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(md.Version).To(gomega.BeEmpty())
+			})
+
+			it("returns error for manifest larger than limit", func() {
+				_, err := springboot.ParseManifest(strings.NewReader(strings.Repeat("A: B\n", springboot.ManifestLimit)))
+				g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("manifest is larger than")))
+			})
+		})
 	}, spec.Report(report.Terminal{}))
 }
@@ -0,0 +1,159 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/buildpacks/libbuildpack/v2/application"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// BuiltArtifact is the environment variable that contains a glob, relative to the module, identifying the built
+	// Spring Boot application.  The glob must match exactly one exploded directory or JAR.
+	BuiltArtifact = "BP_SPRING_BOOT_BUILT_ARTIFACT"
+
+	// Module is the environment variable that contains the subdirectory of the application containing the module to
+	// be used.
+	Module = "BP_SPRING_BOOT_MODULE"
+)
+
+type builtArtifact struct {
+	Path   string `toml:"path"`
+	SHA256 string `toml:"sha256"`
+}
+
+func (b builtArtifact) Identity() (string, string) {
+	return "Built Artifact", filepath.Base(b.Path)
+}
+
+// NewApplication creates an application rooted at the configured module and built artifact.  If the built artifact is
+// a JAR, including a fully executable JAR with a launch script, it is exploded into a layer marked build, cache, and
+// launch.  Without a built artifact, a Gradle application plugin distribution is laid out in the same layer.
+func NewApplication(application application.Application, layer layers.Layer) (application.Application, error) {
+	root := application.Root
+
+	if m, ok := config.Lookup(Module); ok {
+		if filepath.IsAbs(m) {
+			return application, fmt.Errorf("%s %s must be relative to the application", Module, m)
+		}
+
+		root = filepath.Join(root, m)
+
+		if i, err := os.Stat(root); err != nil {
+			return application, fmt.Errorf("unable to find %s %s: %w", Module, m, err)
+		} else if !i.IsDir() {
+			return application, fmt.Errorf("%s %s is not a directory", Module, m)
+		}
+
+		if err := contained(application.Root, root); err != nil {
+			return application, fmt.Errorf("invalid %s %s: %w", Module, m, err)
+		}
+	}
+
+	a, ok := config.Lookup(BuiltArtifact)
+	if !ok {
+		if d, ok, err := NewDistribution(root); err != nil {
+			return application, err
+		} else if ok {
+			if err := d.Contribute(layer); err != nil {
+				return application, err
+			}
+			root = layer.Root
+		}
+
+		application.Root = root
+		return application, nil
+	}
+
+	c, err := filepath.Glob(filepath.Join(root, a))
+	if err != nil {
+		return application, fmt.Errorf("invalid %s %s: %w", BuiltArtifact, a, err)
+	}
+	if len(c) != 1 {
+		return application, fmt.Errorf("%s %s must match exactly one artifact, found %d: %s",
+			BuiltArtifact, a, len(c), strings.Join(c, ", "))
+	}
+
+	i, err := os.Stat(c[0])
+	if err != nil {
+		return application, err
+	}
+
+	if err := contained(application.Root, c[0]); err != nil {
+		return application, fmt.Errorf("invalid %s %s: %w", BuiltArtifact, a, err)
+	}
+
+	if i.IsDir() {
+		application.Root = c[0]
+		return application, nil
+	}
+
+	h, err := hash(c[0])
+	if err != nil {
+		return application, err
+	}
+
+	if err := layer.Contribute(builtArtifact{c[0], h}, func(layer layers.Layer) error {
+		if err := os.RemoveAll(layer.Root); err != nil {
+			return err
+		}
+
+		if n, ok, err := launchScriptLength(c[0]); err != nil {
+			return err
+		} else if ok {
+			layer.Logger.Body("Ignoring %d byte launch script of fully executable JAR", n)
+		}
+
+		layer.Logger.Body("Expanding to %s", layer.Root)
+		if err := extractArchive(c[0], layer.Root); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Build, layers.Cache, layers.Launch); err != nil {
+		return application, err
+	}
+
+	application.Root = layer.Root
+	return application, nil
+}
+
+// contained returns an error unless path, with symbolic links resolved, is root or beneath it.
+func contained(root string, path string) error {
+	r, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+
+	p, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+
+	if rel, err := filepath.Rel(r, p); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("%s is outside of %s", p, r)
+	}
+
+	return nil
+}
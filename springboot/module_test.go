@@ -0,0 +1,210 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestModule(t *testing.T) {
+	spec.Run(t, "Module", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("uses application root by default", func() {
+			a, err := springboot.NewApplication(f.Build.Application, f.Build.Layers.Layer("application"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(a.Root).To(gomega.Equal(f.Build.Application.Root))
+		})
+
+		it("uses module", func() {
+			defer test.ReplaceEnv(t, springboot.Module, "test-module")()
+			g.Expect(os.MkdirAll(filepath.Join(f.Build.Application.Root, "test-module"), 0755)).To(gomega.Succeed())
+
+			a, err := springboot.NewApplication(f.Build.Application, f.Build.Layers.Layer("application"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(a.Root).To(gomega.Equal(filepath.Join(f.Build.Application.Root, "test-module")))
+		})
+
+		it("returns error if module does not exist", func() {
+			defer test.ReplaceEnv(t, springboot.Module, "test-module")()
+
+			_, err := springboot.NewApplication(f.Build.Application, f.Build.Layers.Layer("application"))
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("returns error if module is outside of application", func() {
+			defer test.ReplaceEnv(t, springboot.Module, filepath.Join("..", "test-outside"))()
+			g.Expect(os.MkdirAll(filepath.Join(f.Build.Application.Root, "..", "test-outside"), 0755)).To(gomega.Succeed())
+
+			_, err := springboot.NewApplication(f.Build.Application, f.Build.Layers.Layer("application"))
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("is outside of")))
+		})
+
+		it("returns error if module links outside of application", func() {
+			defer test.ReplaceEnv(t, springboot.Module, "test-module")()
+			outside := test.ScratchDir(t, "outside")
+			g.Expect(os.Symlink(outside, filepath.Join(f.Build.Application.Root, "test-module"))).To(gomega.Succeed())
+
+			_, err := springboot.NewApplication(f.Build.Application, f.Build.Layers.Layer("application"))
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("is outside of")))
+		})
+
+		it("returns error if module is absolute", func() {
+			defer test.ReplaceEnv(t, springboot.Module, f.Build.Application.Root)()
+
+			_, err := springboot.NewApplication(f.Build.Application, f.Build.Layers.Layer("application"))
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("must be relative to the application")))
+		})
+
+		it("returns error if built artifact is outside of application", func() {
+			defer test.ReplaceEnv(t, springboot.BuiltArtifact, filepath.Join("..", "test-outside-exploded"))()
+			g.Expect(os.MkdirAll(filepath.Join(f.Build.Application.Root, "..", "test-outside-exploded"), 0755)).To(gomega.Succeed())
+
+			_, err := springboot.NewApplication(f.Build.Application, f.Build.Layers.Layer("application"))
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("is outside of")))
+		})
+
+		it("uses built artifact directory", func() {
+			defer test.ReplaceEnv(t, springboot.Module, "test-module")()
+			defer test.ReplaceEnv(t, springboot.BuiltArtifact, "target/*-exploded")()
+			g.Expect(os.MkdirAll(filepath.Join(f.Build.Application.Root, "test-module", "target", "test-exploded"), 0755)).To(gomega.Succeed())
+
+			a, err := springboot.NewApplication(f.Build.Application, f.Build.Layers.Layer("application"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(a.Root).To(gomega.Equal(filepath.Join(f.Build.Application.Root, "test-module", "target", "test-exploded")))
+		})
+
+		it("explodes built artifact JAR", func() {
+			defer test.ReplaceEnv(t, springboot.BuiltArtifact, "target/*.jar")()
+
+			j := filepath.Join(f.Build.Application.Root, "target", "test.jar")
+			g.Expect(os.MkdirAll(filepath.Dir(j), 0755)).To(gomega.Succeed())
+			out, err := os.Create(j)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			w := zip.NewWriter(out)
+			e, err := w.Create("META-INF/MANIFEST.MF")
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			_, err = e.Write([]byte("Spring-Boot-Version: test-version"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(w.Close()).To(gomega.Succeed())
+			g.Expect(out.Close()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("application")
+			a, err := springboot.NewApplication(f.Build.Application, layer)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(a.Root).To(gomega.Equal(layer.Root))
+			g.Expect(layer).To(test.HaveLayerMetadata(true, true, true))
+			g.Expect(filepath.Join(layer.Root, "META-INF", "MANIFEST.MF")).To(gomega.BeARegularFile())
+		})
+
+		when("built artifact is a fully executable JAR", func() {
+
+			script := "#!/bin/bash\n# Spring Boot launch script\nexit 0\n"
+
+			writeExecutableJAR := func(offset bool) string {
+				j := filepath.Join(f.Build.Application.Root, "target", "test.jar")
+				g.Expect(os.MkdirAll(filepath.Dir(j), 0755)).To(gomega.Succeed())
+				out, err := os.Create(j)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				_, err = out.WriteString(script)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				w := zip.NewWriter(out)
+				if offset {
+					w.SetOffset(int64(len(script)))
+				}
+				e, err := w.Create("META-INF/MANIFEST.MF")
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				_, err = e.Write([]byte("Spring-Boot-Version: test-version"))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(w.Close()).To(gomega.Succeed())
+				g.Expect(out.Close()).To(gomega.Succeed())
+				return j
+			}
+
+			it("explodes JAR with offsets from start of file", func() {
+				defer test.ReplaceEnv(t, springboot.BuiltArtifact, "target/*.jar")()
+				writeExecutableJAR(true)
+
+				layer := f.Build.Layers.Layer("application")
+				a, err := springboot.NewApplication(f.Build.Application, layer)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(a.Root).To(gomega.Equal(layer.Root))
+				g.Expect(filepath.Join(layer.Root, "META-INF", "MANIFEST.MF")).To(test.HaveContent("Spring-Boot-Version: test-version"))
+			})
+
+			it("explodes JAR with offsets from end of launch script", func() {
+				defer test.ReplaceEnv(t, springboot.BuiltArtifact, "target/*.jar")()
+				writeExecutableJAR(false)
+
+				layer := f.Build.Layers.Layer("application")
+				a, err := springboot.NewApplication(f.Build.Application, layer)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(a.Root).To(gomega.Equal(layer.Root))
+				g.Expect(filepath.Join(layer.Root, "META-INF", "MANIFEST.MF")).To(test.HaveContent("Spring-Boot-Version: test-version"))
+			})
+		})
+
+		it("explodes executable JAR of distribution", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "bin", "test"), `CLASSPATH=$APP_HOME/lib/test-boot.jar
+exec java -classpath "$CLASSPATH" org.springframework.boot.loader.JarLauncher "$@"
+`)
+			j := filepath.Join(f.Build.Application.Root, "lib", "test-boot.jar")
+			g.Expect(os.MkdirAll(filepath.Dir(j), 0755)).To(gomega.Succeed())
+			out, err := os.Create(j)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			w := zip.NewWriter(out)
+			e, err := w.Create("META-INF/MANIFEST.MF")
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			_, err = e.Write([]byte("Spring-Boot-Version: test-version"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(w.Close()).To(gomega.Succeed())
+			g.Expect(out.Close()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("application")
+			a, err := springboot.NewApplication(f.Build.Application, layer)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(a.Root).To(gomega.Equal(layer.Root))
+			g.Expect(layer).To(test.HaveLayerMetadata(true, true, true))
+			g.Expect(filepath.Join(layer.Root, "META-INF", "MANIFEST.MF")).To(test.HaveContent("Spring-Boot-Version: test-version"))
+		})
+
+		it("returns error if built artifact is ambiguous", func() {
+			defer test.ReplaceEnv(t, springboot.BuiltArtifact, "*.jar")()
+			test.TouchFile(t, f.Build.Application.Root, "test-1.jar")
+			test.TouchFile(t, f.Build.Application.Root, "test-2.jar")
+
+			_, err := springboot.NewApplication(f.Build.Application, f.Build.Layers.Layer("application"))
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("found 2")))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/buildpacks/libbuildpack/v2/application"
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/launch"
+)
+
+// Applications is the environment variable that contains a comma-separated list of globs, relative to the
+// application root, of directories that each contain an exploded Spring Boot application (e.g. apps/*).  When set,
+// every application found is contributed as its own process type.
+const Applications = "BP_SPRING_BOOT_APPLICATIONS"
+
+// processType matches the names that the lifecycle accepts as process types.
+var processType = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// NamedApplication is one of the Spring Boot applications of a MultiApplication.
+type NamedApplication struct {
+	// Name is the name of the application, the base name of its directory.
+	Name string
+
+	// Metadata is metadata about the Spring Boot application.
+	Metadata Metadata
+}
+
+// MultiApplication represents an application root containing multiple exploded Spring Boot applications, each
+// contributed as a web-<name> process type with its own class path, followed by $CLASSPATH so that entries that other
+// buildpacks contribute (e.g. agents) are still available.
+type MultiApplication struct {
+	// Applications are the Spring Boot applications, ordered by name.
+	Applications []NamedApplication
+
+	layers layers.Layers
+	logger logger.Logger
+}
+
+// Contribute makes the contribution to launch.
+func (m MultiApplication) Contribute() error {
+	m.logger.Header("%d Spring Boot applications", len(m.Applications))
+
+	md := launch.Metadata{}
+
+	for _, a := range m.Applications {
+		m.logger.Body("%s: Spring Boot %s, %s", a.Name, a.Metadata.Version, a.Metadata.StartClass)
+
+		c := strings.Join(a.Metadata.ClassPath, string(filepath.ListSeparator)) + string(filepath.ListSeparator) + "$CLASSPATH"
+		command, err := newCommand(a.Metadata, c)
+		if err != nil {
+			return err
+		}
+
+		md.Processes = append(md.Processes, launch.Process{Type: "web-" + a.Name, Command: command})
+	}
+
+	return launch.WriteApplicationMetadata(m.layers, md)
+}
+
+// Plan returns the dependency information for this application.
+func (m MultiApplication) Plan() buildpackplan.Plan {
+	var a []map[string]interface{}
+	for _, n := range m.Applications {
+		a = append(a, map[string]interface{}{
+			"name":        n.Name,
+			"start-class": n.Metadata.StartClass,
+			"version":     n.Metadata.Version,
+		})
+	}
+
+	return buildpackplan.Plan{
+		Name:     Dependency,
+		Metadata: buildpackplan.Metadata{"applications": a},
+	}
+}
+
+// NewMultiApplication creates a new MultiApplication instance.  OK is true if $BP_SPRING_BOOT_APPLICATIONS is set.  It
+// is an error if a glob is invalid, if an application is not named as a valid process type, if two applications have
+// the same name, or if no Spring Boot application is found.
+func NewMultiApplication(build build.Build) (MultiApplication, bool, error) {
+	s, ok := config.Lookup(Applications)
+	if !ok {
+		return MultiApplication{}, false, nil
+	}
+
+	names := make(map[string]string)
+	var apps []NamedApplication
+
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+
+		d, err := filepath.Glob(filepath.Join(build.Application.Root, p))
+		if err != nil {
+			return MultiApplication{}, false, fmt.Errorf("invalid %s pattern %s: %w", Applications, p, err)
+		}
+
+		for _, root := range d {
+			if i, err := os.Stat(root); err != nil {
+				return MultiApplication{}, false, err
+			} else if !i.IsDir() {
+				continue
+			}
+
+			md, ok, err := NewMetadata(application.Application{Root: root}, build.Logger)
+			if err != nil {
+				return MultiApplication{}, false, err
+			}
+			if !ok {
+				continue
+			}
+
+			n := filepath.Base(root)
+			if !processType.MatchString(n) {
+				return MultiApplication{}, false, fmt.Errorf("Spring Boot application %s is named %s, which is not a valid process type: names may contain only letters, digits, _, ., and -", root, n)
+			}
+
+			if r, ok := names[n]; ok {
+				if r == root {
+					continue
+				}
+				return MultiApplication{}, false, fmt.Errorf("Spring Boot applications %s and %s are both named %s", r, root, n)
+			}
+			names[n] = root
+
+			apps = append(apps, NamedApplication{Name: n, Metadata: md})
+		}
+	}
+
+	if len(apps) == 0 {
+		return MultiApplication{}, false, fmt.Errorf("%s %s matches no Spring Boot applications", Applications, s)
+	}
+
+	sort.Slice(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+
+	return MultiApplication{apps, build.Layers, build.Logger}, true, nil
+}
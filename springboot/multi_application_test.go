@@ -0,0 +1,137 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestMultiApplication(t *testing.T) {
+	spec.Run(t, "MultiApplication", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		manifest := func(dir string, startClass string) {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, dir, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: `+startClass+`
+Spring-Boot-Version: 2.7.0`)
+		}
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("returns false when $BP_SPRING_BOOT_APPLICATIONS is not set", func() {
+			_, ok, err := springboot.NewMultiApplication(f.Build)
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("finds Spring Boot applications", func() {
+			defer test.ReplaceEnv(t, springboot.Applications, "apps/*, services/c")()
+			manifest("apps/b", "test-start-class-b")
+			manifest("apps/a", "test-start-class-a")
+			manifest("services/c", "test-start-class-c")
+			test.TouchFile(t, f.Build.Application.Root, "apps", "README.md")
+			test.TouchFile(t, f.Build.Application.Root, "apps", "docs", "index.html")
+
+			m, ok, err := springboot.NewMultiApplication(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(m.Applications).To(gomega.HaveLen(3))
+			g.Expect(m.Applications[0].Name).To(gomega.Equal("a"))
+			g.Expect(m.Applications[0].Metadata.StartClass).To(gomega.Equal("test-start-class-a"))
+			g.Expect(m.Applications[1].Name).To(gomega.Equal("b"))
+			g.Expect(m.Applications[2].Name).To(gomega.Equal("c"))
+		})
+
+		it("returns error when no Spring Boot applications are found", func() {
+			defer test.ReplaceEnv(t, springboot.Applications, "apps/*")()
+			test.TouchFile(t, f.Build.Application.Root, "apps", "a", "test-file")
+
+			_, _, err := springboot.NewMultiApplication(f.Build)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("matches no Spring Boot applications")))
+		})
+
+		it("returns error when applications have the same name", func() {
+			defer test.ReplaceEnv(t, springboot.Applications, "apps/*,services/*")()
+			manifest("apps/a", "test-start-class-1")
+			manifest("services/a", "test-start-class-2")
+
+			_, _, err := springboot.NewMultiApplication(f.Build)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("are both named a")))
+		})
+
+		it("returns error when an application is not named as a valid process type", func() {
+			defer test.ReplaceEnv(t, springboot.Applications, "apps/*")()
+			manifest("apps/test app", "test-start-class")
+
+			_, _, err := springboot.NewMultiApplication(f.Build)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("is named test app, which is not a valid process type")))
+		})
+
+		it("returns error for invalid pattern", func() {
+			defer test.ReplaceEnv(t, springboot.Applications, "[")()
+
+			_, _, err := springboot.NewMultiApplication(f.Build)
+			g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("invalid BP_SPRING_BOOT_APPLICATIONS pattern [")))
+		})
+
+		it("contributes a process type per application", func() {
+			defer test.ReplaceEnv(t, springboot.Applications, "apps/*")()
+			manifest("apps/a", "test-start-class-a")
+			manifest("apps/b", "test-start-class-b")
+			test.TouchFile(t, f.Build.Application.Root, "apps", "a", "test-lib", "test-1.jar")
+
+			m, ok, err := springboot.NewMultiApplication(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(m.Contribute()).To(gomega.Succeed())
+
+			a := filepath.Join(f.Build.Application.Root, "apps", "a")
+			b := filepath.Join(f.Build.Application.Root, "apps", "b")
+			g.Expect(filepath.Join(f.Build.Layers.Root, "launch.toml")).To(test.HaveContent(`[[processes]]
+  type = "web-a"
+  command = "java -cp ` + strings.Join([]string{filepath.Join(a, "test-classes"), filepath.Join(a, "test-lib", "test-1.jar"), "$CLASSPATH"}, ":") + ` $JAVA_OPTS test-start-class-a $BPL_SPRING_BOOT_ARGS"
+  direct = false
+
+[[processes]]
+  type = "web-b"
+  command = "java -cp ` + filepath.Join(b, "test-classes") + `:$CLASSPATH $JAVA_OPTS test-start-class-b $BPL_SPRING_BOOT_ARGS"
+  direct = false
+`))
+
+			p := m.Plan()
+			g.Expect(p.Metadata["applications"]).To(gomega.HaveLen(2))
+		})
+	}, spec.Report(report.Terminal{}))
+}
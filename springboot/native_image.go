@@ -0,0 +1,218 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"archive/zip"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+// NativeImageDirectory is the directory, relative to a class path entry, containing native-image configuration such as
+// reachability metadata.
+const NativeImageDirectory = "META-INF/native-image"
+
+// NativeImageConfig is the native-image configuration of an application and its dependencies, aggregated so that a
+// native-image build has complete reachability metadata.
+type NativeImageConfig struct {
+	// Files are the CRC-32 checksums of the configuration files, keyed by path relative to their class path entry.
+	Files map[string]uint32 `toml:"files"`
+
+	// Sources are the class path entries containing configuration, in class path order.
+	Sources []string `toml:"sources"`
+
+	sources map[string]string
+}
+
+func (n NativeImageConfig) Identity() (string, string) {
+	return "Native Image Configuration", fmt.Sprintf("(%d files)", len(n.Files))
+}
+
+// Contribute copies the configuration files to a layer marked build and exposes its location as
+// $SPRING_BOOT_NATIVE_IMAGE_CONFIG.  When more than one class path entry contains a file, the first wins.
+func (n NativeImageConfig) Contribute(layer layers.Layer) error {
+	return layer.Contribute(n, func(layer layers.Layer) error {
+		for _, s := range n.Sources {
+			if err := n.copy(s, layer.Root); err != nil {
+				return err
+			}
+		}
+
+		if err := layer.OverrideBuildEnv("SPRING_BOOT_NATIVE_IMAGE_CONFIG", layer.Root); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Build)
+}
+
+// PlanMetadata adds the location of the configuration in layer and its sources to plan metadata.
+func (n NativeImageConfig) PlanMetadata(metadata buildpackplan.Metadata, layer layers.Layer) {
+	var s []string
+	for _, e := range n.Sources {
+		s = append(s, filepath.Base(e))
+	}
+
+	metadata["native-image"] = buildpackplan.Metadata{"path": layer.Root, "sources": s}
+}
+
+func (n NativeImageConfig) copy(source string, destination string) error {
+	if filepath.Ext(source) != ".jar" {
+		for f, s := range n.sources {
+			if s != source {
+				continue
+			}
+
+			if err := helper.CopyFile(filepath.Join(source, filepath.FromSlash(f)), filepath.Join(destination, filepath.FromSlash(f))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	z, err := zip.OpenReader(source)
+	if err != nil {
+		return err
+	}
+	defer z.Close()
+
+	for _, f := range z.File {
+		name := path.Clean(f.Name)
+		if strings.HasSuffix(f.Name, "/") || n.sources[name] != source {
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+
+		err = helper.WriteFileFromReader(filepath.Join(destination, filepath.FromSlash(name)), 0644, r)
+		_ = r.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewNativeImageConfig creates a new NativeImageConfig instance from the META-INF/native-image directories of the class
+// path entries.  OK is true if any configuration files exist.
+func NewNativeImageConfig(classPath []string) (NativeImageConfig, bool, error) {
+	n := NativeImageConfig{Files: make(map[string]uint32), sources: make(map[string]string)}
+
+	add := func(source string, name string, r io.Reader) error {
+		if _, ok := n.sources[name]; ok {
+			return nil
+		}
+
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+
+		n.Files[name] = crc32.ChecksumIEEE(b)
+		n.sources[name] = source
+		if len(n.Sources) == 0 || n.Sources[len(n.Sources)-1] != source {
+			n.Sources = append(n.Sources, source)
+		}
+		return nil
+	}
+
+	for _, e := range classPath {
+		i, err := os.Stat(e)
+		if err != nil {
+			continue
+		}
+
+		if i.IsDir() {
+			d := filepath.Join(e, filepath.FromSlash(NativeImageDirectory))
+			if err := filepath.Walk(d, func(p string, info os.FileInfo, err error) error {
+				if os.IsNotExist(err) {
+					return nil
+				} else if err != nil || info.IsDir() {
+					return err
+				}
+
+				rel, err := filepath.Rel(e, p)
+				if err != nil {
+					return err
+				}
+
+				f, err := os.Open(p)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+
+				return add(e, filepath.ToSlash(rel), f)
+			}); err != nil {
+				return NativeImageConfig{}, false, err
+			}
+			continue
+		}
+
+		if filepath.Ext(e) != ".jar" {
+			continue
+		}
+
+		if err := nativeImageJAR(e, add); err != nil {
+			return NativeImageConfig{}, false, err
+		}
+	}
+
+	return n, len(n.Files) > 0, nil
+}
+
+func nativeImageJAR(jar string, add func(source string, name string, r io.Reader) error) error {
+	z, err := zip.OpenReader(jar)
+	if err != nil {
+		return nil
+	}
+	defer z.Close()
+
+	for _, f := range z.File {
+		if strings.HasSuffix(f.Name, "/") || !strings.HasPrefix(path.Clean(f.Name), NativeImageDirectory+"/") {
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return err
+		}
+
+		err = add(jar, path.Clean(f.Name), r)
+		_ = r.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestNativeImageConfig(t *testing.T) {
+	spec.Run(t, "NativeImageConfig", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			classes string
+			f       *test.BuildFactory
+			jar     string
+		)
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+
+			classes = filepath.Join(f.Build.Application.Root, "test-classes")
+			test.WriteFile(t, filepath.Join(classes, "META-INF", "native-image", "test", "app", "reflect-config.json"), "test-app")
+			test.WriteFile(t, filepath.Join(classes, "META-INF", "native-image", "test", "shared", "resource-config.json"), "test-app")
+
+			jar = filepath.Join(f.Build.Application.Root, "test-lib", "test-1.2.3.jar")
+			g.Expect(os.MkdirAll(filepath.Dir(jar), 0755)).To(gomega.Succeed())
+			w, err := os.Create(jar)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			z := zip.NewWriter(w)
+			for n, c := range map[string]string{
+				"META-INF/native-image/test/lib/reflect-config.json":     "test-lib",
+				"META-INF/native-image/test/shared/resource-config.json": "test-lib",
+				"META-INF/MANIFEST.MF":                                   "test-manifest",
+			} {
+				e, err := z.Create(n)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				_, err = e.Write([]byte(c))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			}
+			g.Expect(z.Close()).To(gomega.Succeed())
+			g.Expect(w.Close()).To(gomega.Succeed())
+		})
+
+		it("returns false without configuration", func() {
+			_, ok, err := springboot.NewNativeImageConfig([]string{filepath.Join(f.Build.Application.Root, "test-other")})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("aggregates configuration from classes and JARs", func() {
+			n, ok, err := springboot.NewNativeImageConfig([]string{classes, jar})
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(n.Sources).To(gomega.Equal([]string{classes, jar}))
+			g.Expect(n.Files).To(gomega.HaveLen(3))
+
+			layer := f.Build.Layers.Layer("native-image")
+			g.Expect(n.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(true, false, false))
+			g.Expect(layer).To(test.HaveOverrideBuildEnvironment("SPRING_BOOT_NATIVE_IMAGE_CONFIG", layer.Root))
+			g.Expect(filepath.Join(layer.Root, "META-INF", "native-image", "test", "app", "reflect-config.json")).To(test.HaveContent("test-app"))
+			g.Expect(filepath.Join(layer.Root, "META-INF", "native-image", "test", "lib", "reflect-config.json")).To(test.HaveContent("test-lib"))
+			g.Expect(filepath.Join(layer.Root, "META-INF", "native-image", "test", "shared", "resource-config.json")).To(test.HaveContent("test-app"))
+			g.Expect(filepath.Join(layer.Root, "META-INF", "MANIFEST.MF")).NotTo(gomega.BeAnExistingFile())
+
+			m := buildpackplan.Metadata{}
+			n.PlanMetadata(m, layer)
+			g.Expect(m).To(gomega.HaveKeyWithValue("native-image", buildpackplan.Metadata{
+				"path":    layer.Root,
+				"sources": []string{"test-classes", "test-1.2.3.jar"},
+			}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"path/filepath"
+
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+// NestedDependencies is the environment variable that, when true, also records the JAR dependencies nested, one level
+// deep, in JAR dependencies (e.g. executable or uber JARs).
+const NestedDependencies = "BP_SPRING_BOOT_NESTED_DEPENDENCIES"
+
+// NewNestedJARDependencies returns the JAR dependencies nested directly in a JAR, each with NestedIn set to the name of
+// the JAR.  JARs nested more deeply are not scanned.  Files that are not JARs have no nested dependencies.
+func NewNestedJARDependencies(path string) (JARDependencies, error) {
+	z, err := openArchive(path)
+	if err == zip.ErrFormat {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer z.Close()
+
+	var d JARDependencies
+	for _, f := range z.File {
+		if f.FileInfo().IsDir() || filepath.Ext(f.Name) != ".jar" {
+			continue
+		}
+
+		m := pattern.FindStringSubmatch("./" + filepath.Base(f.Name))
+		if m == nil {
+			continue
+		}
+
+		h, err := hashEntry(f)
+		if err != nil {
+			return nil, err
+		}
+
+		d = append(d, JARDependency{Name: m[1], Version: m[2], SHA256: h, NestedIn: filepath.Base(path)})
+	}
+
+	return d, nil
+}
+
+func hashEntry(f *zip.File) (string, error) {
+	in, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	s := sha256.New()
+	if _, err := io.Copy(s, in); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(s.Sum(nil)), nil
+}
+
+func nestedDependenciesEnabled() (bool, error) {
+	return config.LookupBool(NestedDependencies, false)
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestNestedJAR(t *testing.T) {
+	spec.Run(t, "Nested JAR", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var root string
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "nested-jar")
+		})
+
+		writeJAR := func(path string, entries map[string]string) {
+			g.Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(gomega.Succeed())
+
+			out, err := os.Create(path)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			defer out.Close()
+
+			z := zip.NewWriter(out)
+			for n, c := range entries {
+				w, err := z.Create(n)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				_, err = w.Write([]byte(c))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			}
+			g.Expect(z.Close()).To(gomega.Succeed())
+		}
+
+		it("returns nested JAR dependencies", func() {
+			p := filepath.Join(root, "test-uber-1.0.0.jar")
+			writeJAR(p, map[string]string{
+				"BOOT-INF/lib/test-nested-2.0.0.jar": "test-1",
+				"BOOT-INF/lib/not-a-dependency.jar":  "test-2",
+				"BOOT-INF/classes/Test.class":        "test-3",
+			})
+
+			g.Expect(springboot.NewNestedJARDependencies(p)).To(gomega.Equal(springboot.JARDependencies{
+				{
+					Name:     "test-nested",
+					Version:  "2.0.0",
+					SHA256:   "ed1e1dcf971990c1b89676ae785436106f7548b1ae41d174ca9d3bfb9661a477",
+					NestedIn: "test-uber-1.0.0.jar",
+				},
+			}))
+		})
+
+		it("returns nested JAR dependencies of fully executable JARs", func() {
+			p := filepath.Join(root, "test-uber-1.0.0.jar")
+			b, err := os.Create(p)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			_, err = b.WriteString("#!/bin/bash\nexit 0\n")
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			z := zip.NewWriter(b)
+			w, err := z.Create("BOOT-INF/lib/test-nested-2.0.0.jar")
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			_, err = w.Write([]byte("test-1"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(z.Close()).To(gomega.Succeed())
+			g.Expect(b.Close()).To(gomega.Succeed())
+
+			g.Expect(springboot.NewNestedJARDependencies(p)).To(gomega.Equal(springboot.JARDependencies{
+				{
+					Name:     "test-nested",
+					Version:  "2.0.0",
+					SHA256:   "ed1e1dcf971990c1b89676ae785436106f7548b1ae41d174ca9d3bfb9661a477",
+					NestedIn: "test-uber-1.0.0.jar",
+				},
+			}))
+		})
+
+		it("returns no dependencies for files that are not JARs", func() {
+			p := filepath.Join(root, "test-1.0.0.jar")
+			test.WriteFile(t, p, "test")
+
+			g.Expect(springboot.NewNestedJARDependencies(p)).To(gomega.BeEmpty())
+		})
+	}, spec.Report(report.Terminal{}))
+}
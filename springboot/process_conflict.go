@@ -0,0 +1,78 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/launch"
+)
+
+const (
+	// ProcessConflict is the environment variable that configures how process types that were already contributed by
+	// a buildpack that ran earlier are resolved.
+	ProcessConflict = "BP_SPRING_BOOT_PROCESS_CONFLICT"
+
+	// ProcessConflictDefer keeps the process types of the buildpack that ran earlier.
+	ProcessConflictDefer = "defer"
+
+	// ProcessConflictFail fails the build.
+	ProcessConflictFail = "fail"
+
+	// ProcessConflictOverride replaces the process types of the buildpack that ran earlier.
+	ProcessConflictOverride = "override"
+)
+
+// ResolveProcessConflicts resolves the process types that were already contributed by buildpacks that ran earlier,
+// keyed by type, according to a ProcessConflict policy.  The processes to contribute are returned with a warning for
+// each conflict.
+func ResolveProcessConflicts(processes launch.Processes, existing map[string]string, policy string) (launch.Processes, []string, error) {
+	var (
+		p launch.Processes
+		w []string
+	)
+
+	for _, c := range processes {
+		b, ok := existing[c.Type]
+		if !ok {
+			p = append(p, c)
+			continue
+		}
+
+		switch policy {
+		case ProcessConflictDefer:
+			w = append(w, fmt.Sprintf("Process type %s was contributed by %s, not contributing it", c.Type, b))
+		case ProcessConflictFail:
+			return nil, nil, fmt.Errorf("process type %s was contributed by %s and %s is %s", c.Type, b, ProcessConflict, policy)
+		default:
+			w = append(w, fmt.Sprintf("Process type %s was contributed by %s, replacing it.  Set %s to %s to keep it.",
+				c.Type, b, ProcessConflict, ProcessConflictDefer))
+			p = append(p, c)
+		}
+	}
+
+	return p, w, nil
+}
+
+func processConflictPolicy() string {
+	if p, ok := config.Lookup(ProcessConflict); ok {
+		return p
+	}
+
+	return ProcessConflictOverride
+}
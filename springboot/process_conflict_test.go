@@ -0,0 +1,66 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/spring-boot-cnb/launch"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestProcessConflict(t *testing.T) {
+	spec.Run(t, "ProcessConflict", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		processes := launch.Processes{
+			{Type: "spring-boot", Command: "test-command"},
+			{Type: "web", Command: "test-command"},
+		}
+		existing := map[string]string{"web": "other-buildpack"}
+
+		it("contributes processes without conflicts", func() {
+			p, w, err := springboot.ResolveProcessConflicts(processes, map[string]string{}, springboot.ProcessConflictFail)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p).To(gomega.Equal(processes))
+			g.Expect(w).To(gomega.BeEmpty())
+		})
+
+		it("overrides conflicting processes with a warning", func() {
+			p, w, err := springboot.ResolveProcessConflicts(processes, existing, springboot.ProcessConflictOverride)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p).To(gomega.Equal(processes))
+			g.Expect(w).To(gomega.ConsistOf(gomega.ContainSubstring("web was contributed by other-buildpack, replacing it")))
+		})
+
+		it("defers conflicting processes with a warning", func() {
+			p, w, err := springboot.ResolveProcessConflicts(processes, existing, springboot.ProcessConflictDefer)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p).To(gomega.Equal(launch.Processes{{Type: "spring-boot", Command: "test-command"}}))
+			g.Expect(w).To(gomega.ConsistOf(gomega.ContainSubstring("web was contributed by other-buildpack, not contributing it")))
+		})
+
+		it("fails on conflicting processes", func() {
+			_, _, err := springboot.ResolveProcessConflicts(processes, existing, springboot.ProcessConflictFail)
+			g.Expect(err).To(gomega.MatchError("process type web was contributed by other-buildpack and BP_SPRING_BOOT_PROCESS_CONFLICT is fail"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import "path/filepath"
+
+const (
+	// ContextPathLabel is the label that contains the server.servlet.context-path of an application.
+	ContextPathLabel = "org.springframework.boot.server.context-path"
+
+	// ManagementBasePathLabel is the label that contains the management.endpoints.web.base-path of an
+	// application.
+	ManagementBasePathLabel = "org.springframework.boot.management.base-path"
+
+	// ManagementPortLabel is the label that contains the management.server.port of an application.
+	ManagementPortLabel = "org.springframework.boot.management.port"
+)
+
+var propertyLabels = []struct {
+	key   string
+	label string
+}{
+	{"server.servlet.context-path", ContextPathLabel},
+	{"management.server.port", ManagementPortLabel},
+	{"management.endpoints.web.base-path", ManagementBasePathLabel},
+}
+
+// NewPropertyLabels returns labels for the context path and management configuration in application.properties,
+// so that ingress and probes generated from plan metadata use the right paths and port.  Properties that are not
+// configured are not labeled.
+func NewPropertyLabels(root string, metadata Metadata) (map[string]string, error) {
+	l := map[string]string{}
+
+	for _, p := range propertyLabels {
+		v, ok, err := readProperty(filepath.Join(root, metadata.Classes, "application.properties"), p.key)
+		if err != nil {
+			return nil, err
+		} else if ok && v != "" {
+			l[p.label] = v
+		}
+	}
+
+	return l, nil
+}
@@ -0,0 +1,57 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestPropertyLabels(t *testing.T) {
+	spec.Run(t, "PropertyLabels", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var root string
+
+		it.Before(func() {
+			root = test.ScratchDir(t, "property-labels")
+		})
+
+		it("returns no labels without application.properties", func() {
+			g.Expect(springboot.NewPropertyLabels(root, springboot.Metadata{Classes: "test-classes"})).To(gomega.BeEmpty())
+		})
+
+		it("labels configured properties", func() {
+			test.WriteFile(t, filepath.Join(root, "test-classes", "application.properties"), `server.servlet.context-path=/test-context
+management.server.port=9091
+management.endpoints.web.base-path=
+`)
+
+			g.Expect(springboot.NewPropertyLabels(root, springboot.Metadata{Classes: "test-classes"})).To(gomega.Equal(map[string]string{
+				springboot.ContextPathLabel:    "/test-context",
+				springboot.ManagementPortLabel: "9091",
+			}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
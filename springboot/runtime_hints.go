@@ -0,0 +1,141 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// LargeResourceSize is the uncompressed size, in bytes, of the resources in a JAR above which it is reported as a
+	// large resource JAR.
+	LargeResourceSize = 1024 * 1024
+
+	// RuntimeHintsEnabled is the environment variable that, when true, contributes hints that a JRE buildpack can use
+	// to assemble a trimmed runtime.
+	RuntimeHintsEnabled = "BP_SPRING_BOOT_RUNTIME_HINTS"
+
+	// RuntimeHintsFile is the name of the file containing the runtime hints of an application.
+	RuntimeHintsFile = "runtime-hints.json"
+
+	// LocaleProviderICU4J indicates that locale data is provided by icu4j rather than the JDK.
+	LocaleProviderICU4J = "icu4j"
+
+	// LocaleProviderJDK indicates that locale data is provided by the JDK.
+	LocaleProviderJDK = "jdk"
+)
+
+// LargeResource is a JAR on the class path whose resources, other than class files, are larger than
+// LargeResourceSize.
+type LargeResource struct {
+	// Name is the file name of the JAR.
+	Name string `json:"name" toml:"name"`
+
+	// Size is the uncompressed size, in bytes, of the resources in the JAR.
+	Size uint64 `json:"size" toml:"size"`
+}
+
+// RuntimeHints are hints, derived from the class path of an application, that a JRE buildpack can use to assemble a
+// trimmed runtime (e.g. with jlink).
+type RuntimeHints struct {
+	// LargeResources are the JARs on the class path with large resources, ordered by name.
+	LargeResources []LargeResource `json:"large-resources" toml:"large-resources"`
+
+	// LocaleProvider is the provider of locale data: icu4j or jdk.  When icu4j, the JDK's jdk.localedata module is
+	// not required.
+	LocaleProvider string `json:"locale-provider" toml:"locale-provider"`
+
+	// Modules are the JDK modules that export packages referenced by classes on the class path, ordered by name.
+	Modules []string `json:"modules" toml:"modules"`
+}
+
+func (r RuntimeHints) Identity() (string, string) {
+	return "Runtime Hints", fmt.Sprintf("(%d modules)", len(r.Modules))
+}
+
+// Contribute writes the runtime hints to a layer marked build and exposes its location as $SPRING_BOOT_RUNTIME_HINTS.
+func (r RuntimeHints) Contribute(layer layers.Layer) error {
+	return layer.Contribute(r, func(layer layers.Layer) error {
+		j, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		f := filepath.Join(layer.Root, RuntimeHintsFile)
+		if err := helper.WriteFile(f, 0644, "%s", j); err != nil {
+			return err
+		}
+
+		if err := layer.OverrideSharedEnv("SPRING_BOOT_RUNTIME_HINTS", f); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Build)
+}
+
+// NewRuntimeHints creates a new RuntimeHints instance from the JARs on the class path and the JDK modules they
+// require.
+func NewRuntimeHints(classPath []string, modules []string) (RuntimeHints, error) {
+	r := RuntimeHints{LargeResources: []LargeResource{}, LocaleProvider: LocaleProviderJDK, Modules: modules}
+	if _, ok := FindJARDependency(classPath, "icu4j"); ok {
+		r.LocaleProvider = LocaleProviderICU4J
+	}
+
+	for _, e := range classPath {
+		if filepath.Ext(e) != ".jar" {
+			continue
+		}
+
+		z, err := zip.OpenReader(e)
+		if err != nil {
+			continue
+		}
+
+		var size uint64
+		for _, f := range z.File {
+			if !strings.HasSuffix(f.Name, "/") && filepath.Ext(f.Name) != ".class" {
+				size += f.UncompressedSize64
+			}
+		}
+		_ = z.Close()
+
+		if size > LargeResourceSize {
+			r.LargeResources = append(r.LargeResources, LargeResource{Name: filepath.Base(e), Size: size})
+		}
+	}
+
+	sort.Slice(r.LargeResources, func(i, j int) bool {
+		return r.LargeResources[i].Name < r.LargeResources[j].Name
+	})
+
+	return r, nil
+}
+
+func runtimeHintsEnabled() (bool, error) {
+	return config.LookupBool(RuntimeHintsEnabled, false)
+}
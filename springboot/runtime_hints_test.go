@@ -0,0 +1,95 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestRuntimeHints(t *testing.T) {
+	spec.Run(t, "RuntimeHints", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		modules := []string{"java.base", "java.sql"}
+
+		it("reports JDK modules", func() {
+			r, err := springboot.NewRuntimeHints([]string{filepath.Join("testdata", "runtime_hints")}, modules)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(r).To(gomega.Equal(springboot.RuntimeHints{
+				LargeResources: []springboot.LargeResource{},
+				LocaleProvider: springboot.LocaleProviderJDK,
+				Modules:        modules,
+			}))
+		})
+
+		it("reports large resources and icu4j", func() {
+			jar := filepath.Join(test.ScratchDir(t, "runtime-hints"), "icu4j-70.1.jar")
+			w, err := os.Create(jar)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			z := zip.NewWriter(w)
+			e, err := z.Create("com/ibm/icu/impl/data/icudt70b/test.res")
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			_, err = e.Write(bytes.Repeat([]byte{0}, springboot.LargeResourceSize+1))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(z.Close()).To(gomega.Succeed())
+			g.Expect(w.Close()).To(gomega.Succeed())
+
+			r, err := springboot.NewRuntimeHints([]string{jar}, modules)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(r).To(gomega.Equal(springboot.RuntimeHints{
+				LargeResources: []springboot.LargeResource{{Name: "icu4j-70.1.jar", Size: springboot.LargeResourceSize + 1}},
+				LocaleProvider: springboot.LocaleProviderICU4J,
+				Modules:        modules,
+			}))
+		})
+
+		it("contributes runtime hints", func() {
+			f := test.NewBuildFactory(t)
+
+			r, err := springboot.NewRuntimeHints(nil, modules)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			layer := f.Build.Layers.Layer("runtime-hints")
+			g.Expect(r.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(true, false, false))
+			g.Expect(layer).To(test.HaveOverrideSharedEnvironment("SPRING_BOOT_RUNTIME_HINTS",
+				filepath.Join(layer.Root, springboot.RuntimeHintsFile)))
+			g.Expect(filepath.Join(layer.Root, springboot.RuntimeHintsFile)).To(test.HaveContent(`{
+  "large-resources": [],
+  "locale-provider": "jdk",
+  "modules": [
+    "java.base",
+    "java.sql"
+  ]
+}`))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+// ScanTimeout is the environment variable that limits how long each scan of the application (e.g. slicing or
+// scanning dependencies) may take, e.g. 10m.  Scans are not limited by default.
+const ScanTimeout = "BP_SPRING_BOOT_SCAN_TIMEOUT"
+
+// scanContext returns the context that a scan runs in, which is done after $BP_SPRING_BOOT_SCAN_TIMEOUT, if it is set
+// to a positive duration.
+func scanContext() (context.Context, context.CancelFunc, error) {
+	t, err := config.LookupDuration(ScanTimeout, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if t <= 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		return ctx, cancel, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t)
+	return ctx, cancel, nil
+}
+
+// scanError returns the reason a scan stopped early, naming $BP_SPRING_BOOT_SCAN_TIMEOUT if it timed out.
+func scanError(name string, err error) error {
+	if err == context.DeadlineExceeded {
+		return fmt.Errorf("%s did not complete within %s: %w", name, ScanTimeout, err)
+	}
+
+	return fmt.Errorf("%s stopped: %w", name, err)
+}
@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+const (
+	// SecurityLabel is the label that contains the Spring Security version of an application.
+	SecurityLabel = "org.springframework.boot.security.version"
+
+	// WarnNoSecurity is the environment variable that, when set to true, warns if a web application does not contain
+	// Spring Security.
+	WarnNoSecurity = "BP_SPRING_BOOT_WARN_NO_SECURITY"
+)
+
+// FindSecurity returns the version of the first spring-security-* JAR in a class path.  OK is false if none is present.
+func FindSecurity(paths []string) (string, bool) {
+	for _, p := range paths {
+		if m := pattern.FindStringSubmatch(p); m != nil && strings.HasPrefix(m[1], "spring-security-") {
+			return m[2], true
+		}
+	}
+
+	return "", false
+}
+
+func warnNoSecurity() (bool, error) {
+	return config.LookupBool(WarnNoSecurity, false)
+}
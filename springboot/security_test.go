@@ -0,0 +1,47 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"testing"
+
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestSecurity(t *testing.T) {
+	spec.Run(t, "Security", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		it("returns false without Spring Security", func() {
+			_, ok := springboot.FindSecurity([]string{"/test-lib/spring-core-5.2.4.RELEASE.jar"})
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("returns version of Spring Security", func() {
+			v, ok := springboot.FindSecurity([]string{
+				"/test-lib/spring-core-5.2.4.RELEASE.jar",
+				"/test-lib/spring-security-web-5.3.0.RELEASE.jar",
+			})
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(v).To(gomega.Equal("5.3.0.RELEASE"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,185 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// SharedDependenciesEnabled is the environment variable that, when true, relocates the JARs in Spring-Boot-Lib to
+	// layers named by their SHA256, so that images built with identical dependencies share identical layers.
+	SharedDependenciesEnabled = "BP_SPRING_BOOT_SHARED_DEPENDENCIES"
+
+	// SharedDependenciesLimit is the environment variable that contains the maximum number of layers JARs are
+	// relocated to.  Images are limited to about 128 layers by overlayfs, so the largest JARs are relocated and the
+	// others remain in the application.
+	SharedDependenciesLimit = "BP_SPRING_BOOT_SHARED_DEPENDENCIES_LIMIT"
+
+	// DefaultSharedDependenciesLimit is the default maximum number of layers JARs are relocated to.
+	DefaultSharedDependenciesLimit = 64
+)
+
+// SharedDependency is a JAR dependency relocated to a layer named by its SHA256.  JARs with the same SHA256 are
+// relocated to the same layer, with the name of the first of them.
+type SharedDependency struct {
+	// Name is the file name of the JAR.
+	Name string `toml:"name"`
+
+	// SHA256 is the SHA256 of the JAR.
+	SHA256 string `toml:"sha256"`
+
+	layer   layers.Layer
+	size    int64
+	sources []string
+}
+
+func (s SharedDependency) Identity() (string, string) {
+	return s.Name, s.SHA256[:12]
+}
+
+// Contribute copies the JAR to a layer marked launch and removes every copy of it from the application.
+func (s SharedDependency) Contribute() error {
+	if err := s.layer.Contribute(s, func(layer layers.Layer) error {
+		if err := helper.CopyFile(s.sources[0], filepath.Join(layer.Root, s.Name)); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch); err != nil {
+		return err
+	}
+
+	for _, p := range s.sources {
+		if err := os.Remove(p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Path returns the path of the JAR in the layer.
+func (s SharedDependency) Path() string {
+	return filepath.Join(s.layer.Root, s.Name)
+}
+
+// SharedDependencies are the JAR dependencies relocated to layers named by their SHA256.
+type SharedDependencies []SharedDependency
+
+// ClassPath returns a class path with the relocated JARs replaced by their paths in the layers.  JARs with the same
+// SHA256 appear once, at the position of the first of them.
+func (s SharedDependencies) ClassPath(classPath []string) []string {
+	r := make(map[string]string, len(s))
+	for _, d := range s {
+		for _, p := range d.sources {
+			r[p] = d.Path()
+		}
+	}
+
+	var c []string
+	seen := make(map[string]bool)
+	for _, p := range classPath {
+		if l, ok := r[p]; ok {
+			if seen[l] {
+				continue
+			}
+			seen[l] = true
+			p = l
+		}
+		c = append(c, p)
+	}
+
+	return c
+}
+
+// Contribute relocates each JAR to its layer.
+func (s SharedDependencies) Contribute() error {
+	for _, d := range s {
+		if err := d.Contribute(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewSharedDependencies creates a new SharedDependencies instance from the JARs in Spring-Boot-Lib that are on the
+// class path.  Only the $BP_SPRING_BOOT_SHARED_DEPENDENCIES_LIMIT largest distinct JARs are relocated.  OK is true if
+// $BP_SPRING_BOOT_SHARED_DEPENDENCIES is true.
+func NewSharedDependencies(root string, metadata Metadata, layers layers.Layers) (SharedDependencies, bool, error) {
+	if ok, err := config.LookupBool(SharedDependenciesEnabled, false); err != nil || !ok {
+		return nil, false, err
+	}
+
+	limit, err := config.LookupInt(SharedDependenciesLimit, DefaultSharedDependenciesLimit)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var lib []string
+	for _, l := range metadata.Libs() {
+		lib = append(lib, filepath.Join(root, l)+string(filepath.Separator))
+	}
+
+	var s SharedDependencies
+	i := make(map[string]int)
+	for _, p := range metadata.ClassPath {
+		if !hasAnyPrefix(p, lib) || filepath.Ext(p) != ".jar" {
+			continue
+		}
+
+		h, err := hash(p)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if j, ok := i[h]; ok {
+			s[j].sources = append(s[j].sources, p)
+			continue
+		}
+
+		f, err := os.Stat(p)
+		if err != nil {
+			return nil, false, err
+		}
+
+		i[h] = len(s)
+		s = append(s, SharedDependency{
+			Name:    filepath.Base(p),
+			SHA256:  h,
+			layer:   layers.Layer("sha256-" + h),
+			size:    f.Size(),
+			sources: []string{p},
+		})
+	}
+
+	if len(s) > limit {
+		sort.SliceStable(s, func(i, j int) bool { return s[i].size > s[j].size })
+		s = s[:limit]
+		sort.Slice(s, func(j, k int) bool { return i[s[j].SHA256] < i[s[k].SHA256] })
+	}
+
+	return s, true, nil
+}
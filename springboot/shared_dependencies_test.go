@@ -0,0 +1,137 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestSharedDependencies(t *testing.T) {
+	spec.Run(t, "SharedDependencies", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			f  *test.BuildFactory
+			md springboot.Metadata
+		)
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+
+			root := f.Build.Application.Root
+			test.WriteFile(t, filepath.Join(root, "test-lib", "test-1.jar"), "test-content")
+			test.WriteFile(t, filepath.Join(root, "test-other", "test-2.jar"), "test-content")
+
+			md = springboot.Metadata{
+				Lib: "test-lib",
+				ClassPath: []string{
+					filepath.Join(root, "test-classes"),
+					filepath.Join(root, "test-lib", "test-1.jar"),
+					filepath.Join(root, "test-other", "test-2.jar"),
+				},
+			}
+		})
+
+		it("returns false when not enabled", func() {
+			_, ok, err := springboot.NewSharedDependencies(f.Build.Application.Root, md, f.Build.Layers)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("relocates Spring-Boot-Lib JARs to layers named by SHA256", func() {
+			defer test.ReplaceEnv(t, springboot.SharedDependenciesEnabled, "true")()
+
+			s, ok, err := springboot.NewSharedDependencies(f.Build.Application.Root, md, f.Build.Layers)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+
+			g.Expect(s.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("sha256-0a3666a0710c08aa6d0de92ce72beeb5b93124cce1bf3701c9d6cdeb543cb73e")
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(filepath.Join(layer.Root, "test-1.jar")).To(test.HaveContent("test-content"))
+			g.Expect(filepath.Join(f.Build.Application.Root, "test-lib", "test-1.jar")).NotTo(gomega.BeAnExistingFile())
+			g.Expect(filepath.Join(f.Build.Application.Root, "test-other", "test-2.jar")).To(gomega.BeAnExistingFile())
+
+			g.Expect(s.ClassPath(md.ClassPath)).To(gomega.Equal([]string{
+				filepath.Join(f.Build.Application.Root, "test-classes"),
+				filepath.Join(layer.Root, "test-1.jar"),
+				filepath.Join(f.Build.Application.Root, "test-other", "test-2.jar"),
+			}))
+		})
+
+		it("relocates JARs with the same SHA256 to one layer", func() {
+			defer test.ReplaceEnv(t, springboot.SharedDependenciesEnabled, "true")()
+			root := f.Build.Application.Root
+			test.WriteFile(t, filepath.Join(root, "test-lib", "test-3.jar"), "test-content")
+			md.ClassPath = append(md.ClassPath, filepath.Join(root, "test-lib", "test-3.jar"))
+
+			s, ok, err := springboot.NewSharedDependencies(root, md, f.Build.Layers)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(s).To(gomega.HaveLen(1))
+
+			g.Expect(s.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("sha256-0a3666a0710c08aa6d0de92ce72beeb5b93124cce1bf3701c9d6cdeb543cb73e")
+			g.Expect(filepath.Join(layer.Root, "test-1.jar")).To(test.HaveContent("test-content"))
+			g.Expect(filepath.Join(root, "test-lib", "test-1.jar")).NotTo(gomega.BeAnExistingFile())
+			g.Expect(filepath.Join(root, "test-lib", "test-3.jar")).NotTo(gomega.BeAnExistingFile())
+
+			g.Expect(s.ClassPath(md.ClassPath)).To(gomega.Equal([]string{
+				filepath.Join(root, "test-classes"),
+				filepath.Join(layer.Root, "test-1.jar"),
+				filepath.Join(root, "test-other", "test-2.jar"),
+			}))
+		})
+
+		it("relocates only the largest JARs above BP_SPRING_BOOT_SHARED_DEPENDENCIES_LIMIT", func() {
+			defer test.ReplaceEnv(t, springboot.SharedDependenciesEnabled, "true")()
+			defer test.ReplaceEnv(t, springboot.SharedDependenciesLimit, "1")()
+			root := f.Build.Application.Root
+			test.WriteFile(t, filepath.Join(root, "test-lib", "test-3.jar"), "test-larger-content")
+			md.ClassPath = append(md.ClassPath, filepath.Join(root, "test-lib", "test-3.jar"))
+
+			s, ok, err := springboot.NewSharedDependencies(root, md, f.Build.Layers)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(s).To(gomega.HaveLen(1))
+			g.Expect(s[0].Name).To(gomega.Equal("test-3.jar"))
+
+			g.Expect(s.Contribute()).To(gomega.Succeed())
+
+			g.Expect(filepath.Join(root, "test-lib", "test-1.jar")).To(gomega.BeAnExistingFile())
+			g.Expect(filepath.Join(root, "test-lib", "test-3.jar")).NotTo(gomega.BeAnExistingFile())
+		})
+
+		it("returns error for invalid BP_SPRING_BOOT_SHARED_DEPENDENCIES_LIMIT", func() {
+			defer test.ReplaceEnv(t, springboot.SharedDependenciesEnabled, "true")()
+			defer test.ReplaceEnv(t, springboot.SharedDependenciesLimit, "test-value")()
+
+			_, _, err := springboot.NewSharedDependencies(f.Build.Application.Root, md, f.Build.Layers)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/events"
+)
+
+// SliceManifestFile is the name of the file, in a layer marked cache, that records the slices of the previous build.
+const SliceManifestFile = "slices.json"
+
+// SliceManifest maps the name of each slice to the SHA256 of each of its files, relative to the workspace, or the
+// target of each of its symbolic links.
+type SliceManifest map[string]map[string]string
+
+// SliceChange describes how a slice changed since the previous build.
+type SliceChange struct {
+	// Slice is the name of the slice.
+	Slice string
+
+	// Added are the files that were not in the slice previously.
+	Added []string
+
+	// Modified are the files whose contents changed.
+	Modified []string
+
+	// Removed are the files that are no longer in the slice.
+	Removed []string
+}
+
+// Diff returns the changes to each slice, in name order, since the previous manifest.  Slices that did not change are
+// omitted.
+func (m SliceManifest) Diff(previous SliceManifest) []SliceChange {
+	names := make(map[string]bool)
+	for n := range m {
+		names[n] = true
+	}
+	for n := range previous {
+		names[n] = true
+	}
+
+	var sorted []string
+	for n := range names {
+		sorted = append(sorted, n)
+	}
+	sort.Strings(sorted)
+
+	var changes []SliceChange
+	for _, n := range sorted {
+		c := SliceChange{Slice: n}
+
+		for p, h := range m[n] {
+			if ph, ok := previous[n][p]; !ok {
+				c.Added = append(c.Added, p)
+			} else if ph != h {
+				c.Modified = append(c.Modified, p)
+			}
+		}
+
+		for p := range previous[n] {
+			if _, ok := m[n][p]; !ok {
+				c.Removed = append(c.Removed, p)
+			}
+		}
+
+		if len(c.Added)+len(c.Modified)+len(c.Removed) == 0 {
+			continue
+		}
+
+		sort.Strings(c.Added)
+		sort.Strings(c.Modified)
+		sort.Strings(c.Removed)
+		changes = append(changes, c)
+	}
+
+	return changes
+}
+
+// Report logs how each slice changed since the manifest persisted in layer, then persists this manifest in its place.
+func (m SliceManifest) Report(layer layers.Layer, logger events.Logger) error {
+	f := filepath.Join(layer.Root, SliceManifestFile)
+
+	if exists, err := helper.FileExists(f); err != nil {
+		return err
+	} else if exists {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+
+		var previous SliceManifest
+		if err := json.Unmarshal(b, &previous); err != nil {
+			logger.Debug("Ignoring invalid %s: %s", f, err)
+		} else {
+			m.report(previous, logger)
+		}
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	if err := helper.WriteFile(f, 0644, "%s", b); err != nil {
+		return err
+	}
+
+	layer.Touch()
+	return layer.WriteMetadata(struct {
+		Slices int `toml:"slices"`
+	}{len(m)}, layers.Cache)
+}
+
+func (m SliceManifest) report(previous SliceManifest, logger events.Logger) {
+	changes := m.Diff(previous)
+	if len(changes) == 0 {
+		logger.Body("Slices unchanged since previous build")
+		return
+	}
+
+	for _, c := range changes {
+		logger.Body("Slice %s changed: %d added, %d modified, %d removed", c.Slice, len(c.Added), len(c.Modified),
+			len(c.Removed))
+
+		for _, p := range c.Added {
+			logger.Debug("  Added %s", p)
+		}
+		for _, p := range c.Modified {
+			logger.Debug("  Modified %s", p)
+		}
+		for _, p := range c.Removed {
+			logger.Debug("  Removed %s", p)
+		}
+
+		logger.Event("slice-changed", events.Fields{
+			"slice":    c.Slice,
+			"added":    len(c.Added),
+			"modified": len(c.Modified),
+			"removed":  len(c.Removed),
+		})
+	}
+}
+
+// NewSliceManifest creates a new SliceManifest instance from slices, named by names, whose paths are relative to
+// root.  Files are hashed with hashes.
+func NewSliceManifest(root string, names []string, slices layers.Slices, hashes *HashCache) (SliceManifest, error) {
+	if len(names) != len(slices) {
+		return nil, fmt.Errorf("%d slice names for %d slices", len(names), len(slices))
+	}
+
+	m := make(SliceManifest, len(slices))
+
+	for i, s := range slices {
+		files := make(map[string]string, len(s.Paths))
+
+		for _, p := range s.Paths {
+			h, err := sliceHash(filepath.Join(root, p), hashes)
+			if err != nil {
+				return nil, err
+			}
+			files[p] = h
+		}
+
+		m[names[i]] = files
+	}
+
+	return m, nil
+}
+
+func sliceHash(path string, hashes *HashCache) (string, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		t, err := os.Readlink(path)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("-> %s", t), nil
+	}
+
+	return hashes.Hash(path)
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/events"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestSliceDiff(t *testing.T) {
+	spec.Run(t, "Slice Diff", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("creates manifest", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test-file-1"), "test-1")
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test-file-2"), "test-2")
+
+			m, err := springboot.NewSliceManifest(f.Build.Application.Root, []string{"test-slice-1", "test-slice-2"},
+				layers.Slices{{Paths: []string{"test-file-1"}}, {Paths: []string{"test-file-2"}}}, nil)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(m).To(gomega.Equal(springboot.SliceManifest{
+				"test-slice-1": {"test-file-1": "ed1e1dcf971990c1b89676ae785436106f7548b1ae41d174ca9d3bfb9661a477"},
+				"test-slice-2": {"test-file-2": "e063cdf36f817a24e97839b0799c023644dd1c31c668bda6481869027035a655"},
+			}))
+		})
+
+		it("records symlink targets", func() {
+			test.TouchFile(t, f.Build.Application.Root, "test-dir", "test-file")
+			g.Expect(os.Symlink("test-dir", filepath.Join(f.Build.Application.Root, "test-link"))).To(gomega.Succeed())
+
+			m, err := springboot.NewSliceManifest(f.Build.Application.Root, []string{"test-slice"},
+				layers.Slices{{Paths: []string{"test-link"}}}, nil)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(m).To(gomega.Equal(springboot.SliceManifest{"test-slice": {"test-link": "-> test-dir"}}))
+		})
+
+		it("returns error when names do not match slices", func() {
+			_, err := springboot.NewSliceManifest(f.Build.Application.Root, []string{"test-slice"}, layers.Slices{}, nil)
+			g.Expect(err).To(gomega.MatchError("1 slice names for 0 slices"))
+		})
+
+		it("diffs manifests", func() {
+			previous := springboot.SliceManifest{
+				"test-slice-1": {"test-modified": "test-sha256-1", "test-removed": "test-sha256", "test-unchanged": "test-sha256"},
+				"test-slice-2": {"test-unchanged": "test-sha256"},
+				"test-slice-3": {"test-removed": "test-sha256"},
+			}
+			current := springboot.SliceManifest{
+				"test-slice-1": {"test-added": "test-sha256", "test-modified": "test-sha256-2", "test-unchanged": "test-sha256"},
+				"test-slice-2": {"test-unchanged": "test-sha256"},
+			}
+
+			g.Expect(current.Diff(previous)).To(gomega.Equal([]springboot.SliceChange{
+				{Slice: "test-slice-1", Added: []string{"test-added"}, Modified: []string{"test-modified"}, Removed: []string{"test-removed"}},
+				{Slice: "test-slice-3", Removed: []string{"test-removed"}},
+			}))
+		})
+
+		it("does not diff identical manifests", func() {
+			m := springboot.SliceManifest{"test-slice": {"test-file": "test-sha256"}}
+			g.Expect(m.Diff(m)).To(gomega.BeEmpty())
+		})
+
+		it("persists manifest for next build", func() {
+			l, err := events.NewLogger(f.Build.Logger, nil)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			layer := f.Build.Layers.Layer("slice-manifest")
+
+			previous := springboot.SliceManifest{"test-slice": {"test-file": "test-sha256-1"}}
+			g.Expect(previous.Report(layer, l)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(false, true, false))
+			g.Expect(filepath.Join(layer.Root, springboot.SliceManifestFile)).To(test.HaveContent(`{"test-slice":{"test-file":"test-sha256-1"}}`))
+
+			current := springboot.SliceManifest{"test-slice": {"test-file": "test-sha256-2"}}
+			g.Expect(current.Report(layer, l)).To(gomega.Succeed())
+
+			g.Expect(filepath.Join(layer.Root, springboot.SliceManifestFile)).To(test.HaveContent(`{"test-slice":{"test-file":"test-sha256-2"}}`))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+)
+
+// ClassifiedSlice is a named slice and the files classified into it.
+type ClassifiedSlice struct {
+	// Name is the name of the slice.
+	Name string `json:"name"`
+
+	// Paths are the paths, relative to the application root and separated by '/', of the files in the slice.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// Slicer classifies the files of an application into slices.  If the application declares a Spring-Boot-Layers-Index,
+// there is one slice per declared layer, followed by a remainder slice.  Otherwise, files are classified by
+// Metadata.Slice.  If $BP_SPRING_BOOT_DEV is true, Spring-Boot-Classes is its own classes slice, contributed last, so
+// that a volume of classes mounted over it replaces only that slice.
+type Slicer struct {
+	dev      bool
+	index    LayersIndex
+	metadata Metadata
+	provided bool
+}
+
+// Names returns the names of the slices, in the order they are contributed.  The provided dependencies slice is only
+// included if the application has a provided lib directory.
+func (s Slicer) Names() []string {
+	var n []string
+
+	switch {
+	case s.index != nil:
+		for _, l := range s.index {
+			n = append(n, l.Name)
+		}
+	case s.provided:
+		n = []string{LaunchSlice, DependencySlice, ProvidedSlice, SnapshotSlice}
+	default:
+		n = []string{LaunchSlice, DependencySlice, SnapshotSlice}
+	}
+
+	if s.index == nil && !s.dev {
+		n = append(n, ApplicationSlice)
+	}
+
+	n = append(n, RemainderSlice)
+
+	if s.dev {
+		n = append(n, ClassesSlice)
+	}
+
+	return n
+}
+
+// Slice returns the name of the slice that a path, relative to the application root, is contributed to.
+func (s Slicer) Slice(path string) string {
+	if s.dev && s.metadata.Classes != "" && strings.HasPrefix(filepath.ToSlash(path), s.metadata.Classes) {
+		return ClassesSlice
+	}
+
+	if s.index == nil {
+		return s.metadata.Slice(path)
+	}
+
+	if l, ok := s.index.Layer(filepath.ToSlash(path)); ok {
+		return l
+	}
+
+	return RemainderSlice
+}
+
+// NewSlicer creates a new Slicer instance for an application rooted at root.
+func NewSlicer(root string, metadata Metadata) (Slicer, error) {
+	dev, err := devEnabled()
+	if err != nil {
+		return Slicer{}, err
+	}
+
+	s := Slicer{dev: dev, metadata: metadata}
+
+	if metadata.LayersIndex != "" {
+		i, err := NewLayersIndex(filepath.Join(root, metadata.LayersIndex))
+		if err != nil {
+			return Slicer{}, err
+		}
+		s.index = i
+	}
+
+	for _, p := range metadata.ProvidedLibs() {
+		ok, err := helper.FileExists(filepath.Join(root, p))
+		if err != nil {
+			return Slicer{}, err
+		}
+		s.provided = s.provided || ok
+	}
+
+	return s, nil
+}
+
+// ClassifySlices classifies the files of an application rooted at root into slices, in the order they are
+// contributed.  Files excluded by $BP_SPRING_BOOT_EXCLUDE_PATTERNS or the IgnoreFile in root are omitted.  It does not
+// require a build, so that slicing can be verified against sample applications.
+func ClassifySlices(root string, metadata Metadata) ([]ClassifiedSlice, error) {
+	x, err := NewExclusions(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return classifySlices(context.Background(), root, metadata, x)
+}
+
+func classifySlices(ctx context.Context, root string, metadata Metadata, exclusions Exclusions) ([]ClassifiedSlice, error) {
+	sl, err := NewSlicer(root, metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string][]string)
+
+	if err := walk(root, root, false, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return scanError("Slicing", err)
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		if exclusions.Excluded(rel) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		n := sl.Slice(rel)
+		paths[n] = append(paths[n], filepath.ToSlash(rel))
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var c []ClassifiedSlice
+	for _, n := range sl.Names() {
+		c = append(c, ClassifiedSlice{Name: n, Paths: paths[n]})
+	}
+
+	return c, nil
+}
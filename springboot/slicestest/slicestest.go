@@ -0,0 +1,77 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package slicestest provides helpers that verify the slices of sample applications against golden files.
+package slicestest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+)
+
+// Update is the environment variable that, when set to true, rewrites golden files with the current slices rather
+// than comparing against them.
+const Update = "UPDATE_GOLDEN"
+
+// MatchGolden classifies the files of an application rooted at root and fails the test unless the slices equal those
+// in a JSON golden file.
+func MatchGolden(t *testing.T, root string, metadata springboot.Metadata, golden string) {
+	t.Helper()
+	g := gomega.NewWithT(t)
+
+	actual, err := springboot.ClassifySlices(root, metadata)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	if os.Getenv(Update) == "true" {
+		g.Expect(WriteGolden(golden, actual)).To(gomega.Succeed())
+		return
+	}
+
+	expected, err := ReadGolden(golden)
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	g.Expect(actual).To(gomega.Equal(expected))
+}
+
+// ReadGolden reads slices from a JSON golden file.
+func ReadGolden(file string) ([]springboot.ClassifiedSlice, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var s []springboot.ClassifiedSlice
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// WriteGolden writes slices to a JSON golden file.
+func WriteGolden(file string, slices []springboot.ClassifiedSlice) error {
+	b, err := json.MarshalIndent(slices, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return helper.WriteFile(file, 0644, "%s\n", b)
+}
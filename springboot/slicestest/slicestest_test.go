@@ -0,0 +1,57 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package slicestest_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot/slicestest"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestSlicesTest(t *testing.T) {
+	spec.Run(t, "SlicesTest", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		metadata := springboot.Metadata{Classes: "BOOT-INF/classes", Lib: "BOOT-INF/lib"}
+
+		it("matches golden file", func() {
+			slicestest.MatchGolden(t, filepath.Join("testdata", "app"), metadata, filepath.Join("testdata", "app.json"))
+		})
+
+		it("writes golden file", func() {
+			defer test.ReplaceEnv(t, slicestest.Update, "true")()
+			golden := filepath.Join(test.ScratchDir(t, "slicestest"), "app.json")
+
+			slicestest.MatchGolden(t, filepath.Join("testdata", "app"), metadata, golden)
+
+			g.Expect(slicestest.ReadGolden(golden)).To(gomega.Equal([]springboot.ClassifiedSlice{
+				{Name: springboot.LaunchSlice, Paths: []string{"org/springframework/boot/loader/Launcher.class"}},
+				{Name: springboot.DependencySlice, Paths: []string{"BOOT-INF/lib/test-1.0.0.jar"}},
+				{Name: springboot.SnapshotSlice, Paths: []string{"BOOT-INF/lib/test-1.0.0-SNAPSHOT.jar"}},
+				{Name: springboot.ApplicationSlice, Paths: []string{"BOOT-INF/classes/test.class"}},
+				{Name: springboot.RemainderSlice, Paths: []string{"META-INF/MANIFEST.MF"}},
+			}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -17,59 +17,316 @@
 package springboot
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/buildpacks/libbuildpack/v2/application"
 	"github.com/cloudfoundry/libcfbuildpack/v2/build"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
 	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
 	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
-	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/events"
+	"github.com/cloudfoundry/spring-boot-cnb/health"
+	"github.com/cloudfoundry/spring-boot-cnb/launch"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
 	"github.com/mitchellh/mapstructure"
 )
 
-// Dependency indicates that an application is a Spring Boot application.
-const Dependency = "spring-boot"
+const (
+	// ClassPathFile is the name of the file in the Spring Boot layer containing the $CLASSPATH entries, one per line,
+	// for tooling that does not evaluate the environment.
+	ClassPathFile = "classpath.txt"
+
+	// Dependency indicates that an application is a Spring Boot application.
+	Dependency = "spring-boot"
+
+	// DependencyProgressInterval is the number of files scanned for dependencies between progress reports.
+	DependencyProgressInterval = 100
+
+	// SliceTraceSample is the number of paths of each slice whose classification is logged at debug level.
+	SliceTraceSample = 10
+
+	// KotlinVersionLabel is the label that contains the Kotlin version of a Kotlin application.
+	KotlinVersionLabel = "org.springframework.boot.kotlin.version"
+
+	// LanguageLabel is the label that identifies the JVM language of an application when it is not Java.
+	LanguageLabel = "org.springframework.boot.language"
+
+	// SpringBootVersionLabel is the label that contains the Spring-Boot-Version of an application.
+	SpringBootVersionLabel = "org.springframework.boot.version"
+
+	// VersionLabel is the label that contains the Implementation-Version of an application.
+	VersionLabel = "org.opencontainers.image.version"
+
+	// TypeLabel is the label that identifies the Spring Cloud Data Flow type of an application.
+	TypeLabel = "org.springframework.cloud.dataflow.type"
+)
 
 // SpringBoot represents a Spring Boot JVM application.
 type SpringBoot struct {
 	// Metadata is metadata about the Spring Boot application.
 	Metadata Metadata
 
-	application application.Application
-	layer       layers.Layer
-	layers      layers.Layers
-	logger      logger.Logger
+	application    application.Application
+	config         config.Config
+	configLocation ConfigLocation
+	exclusions     Exclusions
+	hashes         *HashCache
+	layer          layers.Layer
+	layers         layers.Layers
+	loader         Loader
+	logger         events.Logger
+	loggingConfig  LoggingConfig
+	shared         SharedDependencies
+	supportWindows SupportWindows
+	workspace      string
 }
 
 // Contribute makes the contribution to build, cache, and launch.
 func (s SpringBoot) Contribute() error {
-	if err := s.layer.Contribute(s.Metadata, func(layer layers.Layer) error {
-		return layer.PrependPathSharedEnv("CLASSPATH", strings.Join(s.Metadata.ClassPath, string(filepath.ListSeparator)))
+	if r, ok, err := s.supportWindows.Check(s.Metadata.Version, time.Now()); err != nil {
+		return err
+	} else if ok {
+		if e, err := enforceSupported(); err != nil {
+			return err
+		} else if e {
+			return fmt.Errorf("%s and %s is true", r, EnforceSupported)
+		}
+		s.logger.BodyWarning("%s.  Upgrade to a supported version to continue receiving fixes.", r)
+	}
+
+	if err := Migrate(s.layer, LayerSchemaVersion); err != nil {
+		return err
+	}
+
+	lm := NewLayerMetadata(s.Metadata)
+
+	reused, err := s.layer.MetadataMatches(lm)
+	if err != nil {
+		return err
+	}
+
+	if err := s.layer.Contribute(lm, func(layer layers.Layer) error {
+		if err := layer.PrependPathSharedEnv("CLASSPATH", strings.Join(s.Metadata.ClassPath, string(filepath.ListSeparator))); err != nil {
+			return err
+		}
+
+		f := filepath.Join(layer.Root, ClassPathFile)
+		if err := helper.WriteFile(f, 0644, "%s\n", strings.Join(s.Metadata.ClassPath, "\n")); err != nil {
+			return err
+		}
+
+		if err := layer.OverrideSharedEnv("SPRING_BOOT_CLASSPATH_FILE", f); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
 	}, layers.Build, layers.Cache, layers.Launch); err != nil {
 		return err
 	}
+	s.logger.Event("layer", events.Fields{"name": Dependency, "reused": reused})
+
+	for _, d := range s.loader.Diagnostics {
+		s.logger.BodyWarning(d)
+	}
+
+	if d, ok, err := NewDevTools(); err != nil {
+		return err
+	} else if ok {
+		if _, ok := FindJARDependency(s.Metadata.ClassPath, "spring-boot-devtools"); !ok {
+			s.logger.BodyWarning("%s is true but spring-boot-devtools is not in %s", Dev, s.Metadata.Lib)
+		}
+
+		if err := d.Contribute(s.layers.Layer(DevToolsLayer)); err != nil {
+			return err
+		}
+	}
+
+	if err := s.shared.Contribute(); err != nil {
+		return err
+	}
 
-	slices, err := s.slices()
+	if err := s.exclusions.Remove(s.application.Root); err != nil {
+		return err
+	}
+
+	var (
+		slices layers.Slices
+		names  []string
+	)
+	if err := s.logger.Time("slices", func() error {
+		ctx, cancel, err := scanContext()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		slices, names, err = s.slices(ctx)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if len(slices) > 0 {
+		m, err := NewSliceManifest(s.workspace, names, slices, s.hashes)
+		if err != nil {
+			return err
+		}
+
+		if err := m.Report(s.layers.Layer("slice-manifest"), s.logger); err != nil {
+			return err
+		}
+
+		if err := s.hashes.Write(); err != nil {
+			return err
+		}
+	}
+
+	n := 0
+	for _, l := range slices {
+		n += len(l.Paths)
+	}
+	s.logger.Event("slices", events.Fields{"slices": len(slices), "paths": n})
+
+	command, err := NewCommand(s.Metadata)
 	if err != nil {
 		return err
 	}
 
-	command := fmt.Sprintf("java -cp $CLASSPATH $JAVA_OPTS %s", s.Metadata.StartClass)
+	if _, ok := FindSecurity(s.Metadata.ClassPath); !ok {
+		if _, web := NewWebApplicationType(s.Metadata); web {
+			if w, err := warnNoSecurity(); err != nil {
+				return err
+			} else if w {
+				s.logger.BodyWarning("Web application does not contain Spring Security")
+			}
+		}
+	}
 
-	return s.layers.WriteApplicationMetadata(layers.Metadata{
-		Slices: slices,
-		Processes: layers.Processes{
+	if e, ok := NewEncryption(s.Metadata); ok && e.Jasypt() {
+		if err := e.Contribute(s.layers.Layer("encryption")); err != nil {
+			return err
+		}
+	}
+
+	md := launch.Metadata{
+		Processes: launch.Processes{
 			{Type: "spring-boot", Command: command},
 			{Type: "task", Command: command},
-			{Type: "web", Command: command},
 		},
-	})
+		Slices: slices,
+	}
+
+	switch {
+	case s.config.Process != "":
+		s.logger.Body("Contributing web process type to run the default %s process type", s.config.Process)
+		md.Processes = append(md.Processes, launch.Process{Type: "web", Command: command})
+	case s.isTask():
+		s.logger.Body("Spring Cloud Task application detected, omitting web process type")
+	default:
+		md.Processes = append(md.Processes, launch.Process{Type: "web", Command: command})
+	}
+
+	if e, err := launch.ProcessTypes(s.layers); err != nil {
+		return err
+	} else if len(e) > 0 {
+		var w []string
+		if md.Processes, w, err = ResolveProcessConflicts(md.Processes, e, processConflictPolicy()); err != nil {
+			return err
+		}
+
+		for _, m := range w {
+			s.logger.BodyWarning(m)
+		}
+	}
+
+	if err := NewDebug().Contribute(s.layers.Layer("debug")); err != nil {
+		return err
+	}
+
+	if err := NewJVMDiagnostics().Contribute(s.layers.Layer("jvm-diagnostics")); err != nil {
+		return err
+	}
+
+	if w, ok := NewWorkingDirectory(s.workspace); ok {
+		if err := w.Contribute(s.layers.Layer("working-directory")); err != nil {
+			return err
+		}
+	}
+
+	if b, ok, err := NewBanner(); err != nil {
+		return err
+	} else if ok {
+		if err := b.Contribute(s.layers.Layer("banner")); err != nil {
+			return err
+		}
+	}
+
+	if w, ok := NewWebApplicationType(s.Metadata); ok {
+		if err := w.Contribute(s.layers.Layer("web-application-type")); err != nil {
+			return err
+		}
+	}
+
+	if err := s.configLocation.Contribute(s.layers.Layer("config-location")); err != nil {
+		return err
+	}
+
+	if m, ok := NewLog4ShellMitigation(s.Metadata); ok {
+		s.logger.BodyWarning("log4j-core %s is vulnerable to Log4Shell (CVE-2021-44228, CVE-2021-45046).  Appending -Dlog4j2.formatMsgNoLookups=true to $JAVA_OPTS, but log4j-core must be upgraded to 2.17.1 or later.", m.Version)
+
+		if err := m.Contribute(s.layers.Layer("log4shell-mitigation")); err != nil {
+			return err
+		}
+	}
+
+	if len(s.loggingConfig.Files) > 0 {
+		if err := s.loggingConfig.Contribute(s.layers.Layer(LoggingConfigLayer)); err != nil {
+			return err
+		}
+	}
+
+	if c, ok := NewConfigServer(s.Metadata); ok {
+		if err := c.Contribute(s.layers.Layer("config-server")); err != nil {
+			return err
+		}
+	}
+
+	if e, ok := NewEureka(s.Metadata); ok {
+		if err := e.Contribute(s.layers.Layer("eureka")); err != nil {
+			return err
+		}
+	}
+
+	if g, ok, err := NewGracefulShutdown(s.Metadata); err != nil {
+		return err
+	} else if ok {
+		if err := g.Contribute(s.layers.Layer("graceful-shutdown")); err != nil {
+			return err
+		}
+	}
+
+	if h, ok, err := NewHealthEndpoint(s.application.Root, s.Metadata); err != nil {
+		return err
+	} else if ok {
+		if err := h.Contribute(s.layers.Layer(health.Layer)); err != nil {
+			return err
+		}
+
+		if err := health.NewHealthProbe(s.layers).Contribute(); err != nil {
+			return err
+		}
+	}
+
+	return launch.WriteApplicationMetadata(s.layers, md)
 }
 
 // Plan returns the dependency information for this application.
@@ -83,54 +340,307 @@ func (s SpringBoot) Plan() (buildpackplan.Plan, error) {
 		return buildpackplan.Plan{}, err
 	}
 
-	if d, err := s.dependencies(); err != nil {
+	l := buildpackplan.Metadata{}
+	if err := mapstructure.Decode(s.loader, &l); err != nil {
+		return buildpackplan.Plan{}, err
+	}
+	p.Metadata["loader"] = l
+
+	h := buildpackplan.Metadata{}
+	for k, v := range s.Metadata.SelectedHeaders() {
+		h[k] = v
+	}
+	p.Metadata["manifest"] = h
+
+	if w, ok := NewWebApplicationType(s.Metadata); ok {
+		p.Metadata["web-application-type"] = w.Type
+	}
+
+	if e, ok, err := NewEmbeddedServer(s.application.Root, s.Metadata); err != nil {
+		return buildpackplan.Plan{}, err
+	} else if ok {
+		m := buildpackplan.Metadata{}
+		if err := mapstructure.Decode(e, &m); err != nil {
+			return buildpackplan.Plan{}, err
+		}
+		p.Metadata["server"] = m
+	}
+
+	if m, ok := NewLog4ShellMitigation(s.Metadata); ok {
+		p.Metadata["log4shell-mitigation"] = m.Version
+	}
+
+	if e, ok := NewEncryption(s.Metadata); ok {
+		p.Metadata["encryption"] = e.Mechanisms
+	}
+
+	if v, ok := s.kotlinVersion(); ok {
+		p.Metadata["language"] = "kotlin"
+		p.Metadata["kotlin-version"] = v
+	}
+
+	if l, err := s.labels(); err != nil {
 		return buildpackplan.Plan{}, err
 	} else {
-		p.Metadata["dependencies"] = d
+		p.Metadata["labels"] = l
+	}
+
+	var (
+		d JARDependencies
+		u UnidentifiedDependencies
+	)
+	if err := s.logger.Time("dependencies", func() error {
+		ctx, cancel, err := scanContext()
+		if err != nil {
+			return err
+		}
+		defer cancel()
+
+		d, u, err = s.dependencies(ctx)
+		return err
+	}); err != nil {
+		return buildpackplan.Plan{}, err
+	}
+	s.logger.Event("dependencies", events.Fields{"count": len(d), "unidentified": len(u)})
+
+	if len(u) > 0 {
+		for _, v := range u {
+			s.logger.BodyWarning("Unable to read %s, recording it as unidentified: %s", v.Name, v.Reason)
+		}
+		p.Metadata["unidentified-dependencies"] = u
+	}
+
+	if err := s.hashes.Write(); err != nil {
+		return buildpackplan.Plan{}, err
+	}
+	s.logger.Event("hash-cache", events.Fields{"hits": s.hashes.Hits, "misses": s.hashes.Misses})
+
+	bom, layer := NewBOM(d), s.layers.Layer("dependencies")
+	if err := bom.PlanMetadata(p.Metadata, layer); err != nil {
+		return buildpackplan.Plan{}, err
+	}
+
+	if err := bom.Contribute(layer); err != nil {
+		return buildpackplan.Plan{}, err
+	}
+
+	jdk, err := jdkModulesEnabled()
+	if err != nil {
+		return buildpackplan.Plan{}, err
+	}
+
+	hints, err := runtimeHintsEnabled()
+	if err != nil {
+		return buildpackplan.Plan{}, err
+	}
+
+	if jdk || hints {
+		var m []string
+		if err := s.logger.Time("jdk-modules", func() (err error) {
+			m, err = AnalyzeJDKModules(s.Metadata.ClassPath, s.layers.Layer("jdk-modules"))
+			return err
+		}); err != nil {
+			return buildpackplan.Plan{}, err
+		}
+		s.logger.Event("jdk-modules", events.Fields{"modules": len(m)})
+
+		if jdk {
+			p.Metadata["jdk-modules"] = m
+		}
+
+		if hints {
+			r, err := NewRuntimeHints(s.Metadata.ClassPath, m)
+			if err != nil {
+				return buildpackplan.Plan{}, err
+			}
+
+			if err := r.Contribute(s.layers.Layer("runtime-hints")); err != nil {
+				return buildpackplan.Plan{}, err
+			}
+			p.Metadata["runtime-hints"] = r
+		}
+	}
+
+	if n, ok, err := NewNativeImageConfig(s.Metadata.ClassPath); err != nil {
+		return buildpackplan.Plan{}, err
+	} else if ok {
+		layer := s.layers.Layer("native-image")
+		if err := n.Contribute(layer); err != nil {
+			return buildpackplan.Plan{}, err
+		}
+		n.PlanMetadata(p.Metadata, layer)
+	}
+
+	if err := s.duplicateClasses(); err != nil {
+		return buildpackplan.Plan{}, err
+	}
+
+	if l, err := NewDenyList(); err != nil {
+		return buildpackplan.Plan{}, err
+	} else if err := l.Check(d); err != nil {
+		return buildpackplan.Plan{}, err
+	}
+
+	if v, ok, err := NewVulnerabilityCheck(s.logger.Logger); err != nil {
+		return buildpackplan.Plan{}, err
+	} else if ok {
+		if err := v.Check(d); err != nil {
+			return buildpackplan.Plan{}, err
+		}
 	}
 
 	return p, nil
 }
 
+// libs returns the directories, relative to the application root, that dependencies are scanned in: the Libs and, if
+// $BP_SPRING_BOOT_LIB_PROVIDED is true, the ProvidedLibs.  The whole application is scanned if it does not declare
+// Spring-Boot-Lib.
+func (s SpringBoot) libs() ([]string, error) {
+	l := s.Metadata.Libs()
+	if len(l) == 0 {
+		return []string{""}, nil
+	}
+
+	if ok, err := libProvidedEnabled(); err != nil {
+		return nil, err
+	} else if ok {
+		l = append(l, s.Metadata.ProvidedLibs()...)
+	}
+
+	return l, nil
+}
+
 type result struct {
-	err   error
-	value JARDependency
+	err          error
+	unidentified *UnidentifiedDependency
+	value        JARDependency
 }
 
-func (s SpringBoot) dependencies() (JARDependencies, error) {
+// dependencies scans the lib directories for dependencies.  Files that cannot be read are returned as unidentified
+// dependencies unless $BP_SPRING_BOOT_UNREADABLE_JARS is fail.  The scan stops when ctx is done or a file cannot be
+// read and the first error is returned.
+func (s SpringBoot) dependencies(ctx context.Context) (JARDependencies, UnidentifiedDependencies, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	ch := make(chan result)
 	var wg sync.WaitGroup
 
-	l := filepath.Join(s.application.Root, s.Metadata.Lib)
-	if exists, err := helper.FileExists(l); err != nil {
-		return JARDependencies{}, err
-	} else if !exists {
-		return JARDependencies{}, nil
+	nested, err := nestedDependenciesEnabled()
+	if err != nil {
+		return JARDependencies{}, nil, err
 	}
 
-	if err := filepath.Walk(l, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	fingerprints, _, err := NewFingerprints()
+	if err != nil {
+		return JARDependencies{}, nil, err
+	}
+
+	dirs, err := s.libs()
+	if err != nil {
+		return JARDependencies{}, nil, err
+	}
+
+	var paths []string
+	for _, d := range dirs {
+		l := filepath.Join(s.application.Root, d)
+		if exists, err := helper.FileExists(l); err != nil {
+			return JARDependencies{}, nil, err
+		} else if !exists {
+			continue
 		}
 
+		if err := walk(s.application.Root, l, true, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return err
+			}
+
+			if err := ctx.Err(); err != nil {
+				return scanError("Scanning dependencies", err)
+			}
+
+			paths = append(paths, path)
+			return nil
+		}); err != nil {
+			return JARDependencies{}, nil, err
+		}
+	}
+
+	for _, d := range s.shared {
+		paths = append(paths, d.Path())
+	}
+
+	send := func(r result) {
+		select {
+		case ch <- r:
+		case <-ctx.Done():
+		}
+	}
+
+	policy := unreadableJARsPolicy()
+	unreadable := func(path string, err error) {
+		if policy == UnreadableJARsFail {
+			send(result{err: fmt.Errorf("unable to read %s: %w", path, err)})
+			return
+		}
+
+		u := newUnidentifiedDependency(path, s.hashes, err)
+		send(result{unidentified: &u})
+	}
+
+	start := time.Now()
+	progress := s.logger.Progress("Scanning dependencies", len(paths), DependencyProgressInterval)
+
+	for _, path := range paths {
+		path := path
+
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			defer progress.Increment()
+
+			if ctx.Err() != nil {
+				return
+			}
 
-			d, ok, err := NewJARDependency(path, s.logger)
+			d, ok, err := newJARDependency(path, s.hashes)
 			if err != nil {
-				ch <- result{err: err}
+				unreadable(path, err)
+				return
+			}
+
+			if !ok {
+				if len(fingerprints) == 0 || filepath.Ext(path) != ".jar" {
+					return
+				}
+
+				f, err := fingerprints.Identify(path)
+				if err != nil {
+					unreadable(path, err)
+					return
+				}
+
+				for _, d := range f {
+					send(result{value: d})
+				}
 				return
 			}
+			send(result{value: d})
 
-			if ok {
-				ch <- result{value: d}
+			if !nested {
+				return
 			}
-		}()
 
-		return nil
-	}); err != nil {
-		return nil, err
+			n, err := NewNestedJARDependencies(path)
+			if err != nil {
+				unreadable(path, err)
+				return
+			}
+
+			for _, d := range n {
+				send(result{value: d})
+			}
+		}()
 	}
 
 	go func() {
@@ -138,76 +648,213 @@ func (s SpringBoot) dependencies() (JARDependencies, error) {
 		close(ch)
 	}()
 
-	var d JARDependencies
+	var (
+		d      JARDependencies
+		u      UnidentifiedDependencies
+		failed error
+	)
+	// Results are received until every scan has returned.  After an error, the remaining scans are cancelled.
 	for r := range ch {
-		if r.err != nil {
-			return JARDependencies{}, r.err
+		switch {
+		case r.err != nil:
+			if failed == nil {
+				failed = r.err
+				cancel()
+			}
+		case r.unidentified != nil:
+			u = append(u, *r.unidentified)
+		default:
+			d = append(d, r.value)
 		}
+	}
+	if failed != nil {
+		return JARDependencies{}, nil, failed
+	}
 
-		d = append(d, r.value)
+	if err := ctx.Err(); err != nil {
+		return JARDependencies{}, nil, scanError("Scanning dependencies", err)
 	}
 	sort.Sort(d)
+	sort.Sort(u)
 
-	return d, nil
-}
+	if v := d.Dedup(); len(v) != len(d) {
+		s.logger.Debug("Ignoring %d duplicate dependencies", len(d)-len(v))
+		d = v
+	}
 
-func (s SpringBoot) isApplicationSlice(path string) bool {
-	return strings.HasPrefix(path, s.Metadata.Classes)
-}
+	s.logger.Body("Scanned %d files for %d dependencies in %s", len(paths), len(d), time.Since(start).Round(time.Millisecond))
 
-func (s SpringBoot) isDependencySlice(path string) bool {
-	return strings.HasPrefix(path, s.Metadata.Lib) && filepath.Ext(path) == ".jar" && !strings.Contains(path, "SNAPSHOT")
+	return d, u, nil
 }
 
-func (s SpringBoot) isLaunchSlice(path string) bool {
-	return !strings.HasPrefix(path, s.Metadata.Classes) && !strings.HasPrefix(path, s.Metadata.Lib) && !strings.HasPrefix(path, "META-INF/")
+func (s SpringBoot) duplicateClasses() error {
+	if ok, err := duplicateClassesEnabled(); err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	var d DuplicateClasses
+	if err := s.logger.Time("duplicate-classes", func() (err error) {
+		d, err = FindDuplicateClasses(s.Metadata.ClassPath)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	n := 0
+	for _, c := range d {
+		n += len(c.Classes)
+	}
+	s.logger.Event("duplicate-classes", events.Fields{"conflicts": len(d), "classes": n})
+
+	if len(d) == 0 {
+		return nil
+	}
+
+	s.logger.HeaderWarning("%d classes are duplicated across %s", n, s.Metadata.Lib)
+	for i, c := range d {
+		if i == DuplicateClassesLimit {
+			s.logger.BodyWarning("... and %d more", len(d)-DuplicateClassesLimit)
+			break
+		}
+		s.logger.BodyWarning("%s", c)
+	}
+
+	return nil
 }
 
-func (s SpringBoot) isSnapshotSlice(path string) bool {
-	return strings.HasPrefix(path, s.Metadata.Lib) && filepath.Ext(path) == ".jar" && strings.Contains(path, "SNAPSHOT")
+// kotlinVersion returns the version of kotlin-stdlib if the application is a Kotlin application.
+func (s SpringBoot) kotlinVersion() (string, bool) {
+	return FindJARDependency(s.Metadata.ClassPath, "kotlin-stdlib")
 }
 
-func (s SpringBoot) slices() (layers.Slices, error) {
-	var app, dep, launch, snap, rem layers.Slice
+// labels returns the labels that describe the application.  Buildpack API 0.2 does not support image labels, so they
+// are recorded as plan metadata, for platforms that label images from the bill of materials.
+func (s SpringBoot) labels() (map[string]string, error) {
+	l := map[string]string{SpringBootVersionLabel: s.Metadata.Version}
 
-	if err := filepath.Walk(s.application.Root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
+	h := s.Metadata.SelectedHeaders()
+	if v, ok := h["Implementation-Version"]; ok {
+		l[VersionLabel] = v
+	}
 
-		if info.IsDir() {
-			return nil
+	for _, k := range ManifestHeaders {
+		if v, ok := h[k]; ok {
+			l[ManifestLabelPrefix+strings.ToLower(k)] = v
 		}
+	}
 
-		rel, err := filepath.Rel(s.application.Root, path)
-		if err != nil {
-			return err
+	if e, ok, err := NewEmbeddedServer(s.application.Root, s.Metadata); err != nil {
+		return nil, err
+	} else if ok {
+		l[ServerLabel] = e.Name
+		l[ServerVersionLabel] = e.Version
+	}
+
+	if p, err := NewPropertyLabels(s.application.Root, s.Metadata); err != nil {
+		return nil, err
+	} else {
+		for k, v := range p {
+			l[k] = v
 		}
+	}
+
+	if v, ok := FindSecurity(s.Metadata.ClassPath); ok {
+		l[SecurityLabel] = v
+	}
+
+	if e, ok := NewEncryption(s.Metadata); ok {
+		l[EncryptionLabel] = strings.Join(e.Mechanisms, ",")
+	}
+
+	if v, ok := s.kotlinVersion(); ok {
+		l[LanguageLabel] = "kotlin"
+		l[KotlinVersionLabel] = v
+	}
+
+	if s.isTask() {
+		l[TypeLabel] = "task"
+	}
+
+	return l, nil
+}
+
+func (s SpringBoot) isTask() bool {
+	_, ok := FindJARDependency(s.Metadata.ClassPath, "spring-cloud-task-core")
+	return ok
+}
+
+func (s SpringBoot) slices(ctx context.Context) (layers.Slices, []string, error) {
+	if r, err := filepath.Rel(s.workspace, s.application.Root); err != nil {
+		return layers.Slices{}, nil, err
+	} else if strings.HasPrefix(r, "..") {
+		s.logger.Body("Application exploded outside of workspace, skipping slices")
+		return layers.Slices{}, nil, nil
+	}
+
+	m := s.Metadata
+	switch s.config.Slices {
+	case config.SlicesNone:
+		return layers.Slices{}, nil, nil
+	case config.SlicesLocation:
+		m.LayersIndex = ""
+	}
+
+	c, err := classifySlices(ctx, s.application.Root, m, s.exclusions)
+	if err != nil {
+		return layers.Slices{}, nil, err
+	}
 
-		if s.isApplicationSlice(rel) {
-			app.Paths = append(app.Paths, rel)
-		} else if s.isDependencySlice(rel) {
-			dep.Paths = append(dep.Paths, rel)
-		} else if s.isLaunchSlice(rel) {
-			launch.Paths = append(launch.Paths, rel)
-		} else if s.isSnapshotSlice(rel) {
-			snap.Paths = append(snap.Paths, rel)
-		} else {
-			rem.Paths = append(rem.Paths, rel)
+	var (
+		slices layers.Slices
+		names  []string
+	)
+	for _, cs := range c {
+		var paths []string
+		for _, p := range cs.Paths {
+			r, err := filepath.Rel(s.workspace, filepath.Join(s.application.Root, filepath.FromSlash(p)))
+			if err != nil {
+				return layers.Slices{}, nil, err
+			}
+			paths = append(paths, r)
 		}
 
-		return nil
-	}); err != nil {
-		return layers.Slices{}, err
+		slices = append(slices, layers.Slice{Paths: paths})
+		names = append(names, cs.Name)
+	}
+
+	if s.logger.IsDebugEnabled() {
+		for i, sl := range slices {
+			for j, p := range sl.Paths {
+				if j == SliceTraceSample {
+					s.logger.Debug("Slice %s: ... and %d more", names[i], len(sl.Paths)-j)
+					break
+				}
+				s.logger.Debug("Slice %s: %s", names[i], p)
+			}
+		}
 	}
 
-	return layers.Slices{launch, dep, snap, app, rem}, nil // intentionally ordered
+	return slices, names, nil
 }
 
-// NewSpringBoot creates a new SpringBoot instance.  OK is true if the build plan contains a "jvm-application"
-// dependency and a "Spring-Boot-Version" manifest key.
+// NewSpringBoot creates a new SpringBoot instance that writes events to stdout.  OK is true if the build plan contains
+// a "jvm-application" dependency and a "Spring-Boot-Version" manifest key.
 func NewSpringBoot(build build.Build) (SpringBoot, bool, error) {
-	md, ok, err := NewMetadata(build.Application, build.Logger)
+	return NewSpringBootWithEvents(build, os.Stdout)
+}
+
+// NewSpringBootWithEvents creates a new SpringBoot instance that writes events to writer, so that tools that print
+// their own output to stdout can separate them from it.  OK is true if the build plan contains a "jvm-application"
+// dependency and a "Spring-Boot-Version" manifest key.
+func NewSpringBootWithEvents(build build.Build, writer io.Writer) (SpringBoot, bool, error) {
+	a, err := NewApplication(build.Application, build.Layers.Layer("application"))
+	if err != nil {
+		return SpringBoot{}, false, err
+	}
+
+	md, ok, err := NewMetadata(a, build.Logger)
 	if err != nil {
 		return SpringBoot{}, false, err
 	}
@@ -216,11 +863,99 @@ func NewSpringBoot(build build.Build) (SpringBoot, bool, error) {
 		return SpringBoot{}, false, nil
 	}
 
+	c, err := config.NewConfig(build.Application.Root)
+	if err != nil {
+		return SpringBoot{}, false, err
+	}
+
+	if c.Version != "" && !md.versionMatches(c.Version) {
+		return SpringBoot{}, false, fmt.Errorf("Spring-Boot-Version %s does not satisfy %s", md.Version, c.Version)
+	}
+
+	sw, err := NewSupportWindows(build.Buildpack)
+	if err != nil {
+		return SpringBoot{}, false, err
+	}
+
+	x, err := NewExclusions(build.Application.Root)
+	if err != nil {
+		return SpringBoot{}, false, err
+	}
+
+	dev, err := devEnabled()
+	if err != nil {
+		return SpringBoot{}, false, err
+	}
+	if dev {
+		x = keepDevTools(a.Root, md.ClassPath, x, build.Logger)
+	}
+	md.ClassPath = x.ClassPath(a.Root, md.ClassPath)
+
+	l, err := NewLoader(a, md, build.Logger)
+	if err != nil {
+		return SpringBoot{}, false, err
+	}
+
+	md.ClassPath = append(md.ClassPath, NewAdditionalClassPath(build)...)
+
+	if dev {
+		md.ClassPath = append(md.ClassPath, build.Layers.Layer(DevToolsLayer).Root)
+	}
+
+	lc, ok := NewLoggingConfig(build)
+	if ok {
+		md.ClassPath = append([]string{build.Layers.Layer(LoggingConfigLayer).Root}, md.ClassPath...)
+	}
+
+	if v, fail, err := verifyStartClass(); err != nil {
+		return SpringBoot{}, false, err
+	} else if v {
+		if err := VerifyStartClass(md); err != nil && fail {
+			return SpringBoot{}, false, err
+		} else if err != nil {
+			build.Logger.BodyWarning("%s.  Set $%s to true to fail the build.", err, StartClassVerification)
+		}
+	}
+
+	sd, ok, err := NewSharedDependencies(a.Root, md, build.Layers)
+	if err != nil {
+		return SpringBoot{}, false, err
+	}
+	if ok {
+		if r, err := filepath.Rel(build.Application.Root, a.Root); err != nil {
+			return SpringBoot{}, false, err
+		} else if strings.HasPrefix(r, "..") {
+			build.Logger.BodyWarning("Application exploded outside of workspace, not relocating %s", md.Lib)
+			sd = nil
+		}
+		md.ClassPath = sd.ClassPath(md.ClassPath)
+	}
+
+	h, err := NewHashCache(build.Layers.Layer("hashes"))
+	if err != nil {
+		return SpringBoot{}, false, err
+	}
+
+	e, err := events.NewLogger(build.Logger, writer)
+	if err != nil {
+		return SpringBoot{}, false, err
+	}
+	e.Event("detected", events.Fields{"type": Dependency, "version": md.Version, "start-class": md.StartClass})
+
 	return SpringBoot{
 		md,
-		build.Application,
+		a,
+		c,
+		NewConfigLocation(build, md),
+		x,
+		h,
 		build.Layers.Layer(Dependency),
 		build.Layers,
-		build.Logger,
+		l,
+		e,
+		lc,
+		sd,
+		sw,
+		build.Application.Root,
 	}, true, nil
 }
@@ -17,13 +17,20 @@
 package springboot_test
 
 import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/BurntSushi/toml"
 	"github.com/cloudfoundry/libcfbuildpack/v2/buildpackplan"
 	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
 	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/launch"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
 	"github.com/cloudfoundry/spring-boot-cnb/springboot"
 	"github.com/onsi/gomega"
 	"github.com/sclevine/spec"
@@ -35,10 +42,18 @@ func TestSpringBoot(t *testing.T) {
 
 		g := gomega.NewWithT(t)
 
-		var f *test.BuildFactory
+		var (
+			f       *test.BuildFactory
+			restore func()
+		)
 
 		it.Before(func() {
 			f = test.NewBuildFactory(t)
+			restore = test.ReplaceEnv(t, springboot.StartClassVerification, "false")
+		})
+
+		it.After(func() {
+			restore()
 		})
 
 		when("NewSpringBoot", func() {
@@ -86,7 +101,7 @@ Spring-Boot-Version: test-version`)
 
 				s = e
 
-				command := "java -cp $CLASSPATH $JAVA_OPTS test-start-class"
+				command := "java -cp $CLASSPATH $JAVA_OPTS test-start-class $BPL_SPRING_BOOT_ARGS"
 				metadata = layers.Metadata{
 					Processes: []layers.Process{
 						{Type: "spring-boot", Command: command},
@@ -111,6 +126,22 @@ Spring-Boot-Version: test-version`)
 				g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(metadata))
 			})
 
+			it("adds provided dependency files to slice", func() {
+				test.TouchFile(t, f.Build.Application.Root, "test-lib-provided", "test-1.2.3.jar")
+
+				metadata.Slices = layers.Slices{
+					{},
+					{},
+					{Paths: []string{"test-lib-provided/test-1.2.3.jar"}},
+					{},
+					{},
+					{Paths: []string{"META-INF/MANIFEST.MF"}},
+				}
+
+				g.Expect(s.Contribute()).To(gomega.Succeed())
+				g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(metadata))
+			})
+
 			it("adds dependency files to slice", func() {
 				test.TouchFile(t, f.Build.Application.Root, "test-lib", "test-1.2.3.jar")
 
@@ -156,6 +187,227 @@ Spring-Boot-Version: test-version`)
 				g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(metadata))
 			})
 
+			it("uses paths relative to workspace for module", func() {
+				defer test.ReplaceEnv(t, springboot.Module, "test-module")()
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test-module", "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+				test.TouchFile(t, f.Build.Application.Root, "test-module", "test-classes", "Test.class")
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				metadata.Slices = layers.Slices{
+					{},
+					{},
+					{},
+					{Paths: []string{"test-module/test-classes/Test.class"}},
+					{Paths: []string{"test-module/META-INF/MANIFEST.MF"}},
+				}
+
+				g.Expect(e.Contribute()).To(gomega.Succeed())
+				g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(metadata))
+			})
+
+			it("returns error when Start-Class does not exist", func() {
+				defer test.ReplaceEnv(t, springboot.StartClassVerification, "true")()
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+				_, _, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(err).To(gomega.MatchError("Start-Class test-start-class not found in test-classes or the JARs in test-lib"))
+			})
+
+			it("does not return error when Start-Class does not exist by default", func() {
+				g.Expect(os.Unsetenv(springboot.StartClassVerification)).To(gomega.Succeed())
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+				_, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+			})
+
+			it("reuses layer when metadata matches", func() {
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+				test.TouchFile(t, f.Build.Application.Root, "test-lib", "test-1.2.3.jar")
+
+				e, _, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(e.Contribute()).To(gomega.Succeed())
+
+				layer := f.Build.Layers.Layer("spring-boot")
+				g.Expect(os.RemoveAll(filepath.Join(layer.Root, "env"))).To(gomega.Succeed())
+
+				e, _, err = springboot.NewSpringBoot(f.Build)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(e.Contribute()).To(gomega.Succeed())
+
+				g.Expect(filepath.Join(layer.Root, "env")).NotTo(gomega.BeADirectory())
+				g.Expect(layer).To(test.HaveLayerMetadata(true, true, true))
+			})
+
+			it("fails when past end of OSS support and enforced", func() {
+				defer test.ReplaceEnv(t, springboot.EnforceSupported, "true")()
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: 1.5.22.RELEASE`)
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(e.Contribute()).To(gomega.MatchError(gomega.ContainSubstring("Spring Boot 1.5 reached the end of OSS support")))
+			})
+
+			it("fails when a process type was contributed by another buildpack and conflicts fail", func() {
+				defer test.ReplaceEnv(t, springboot.ProcessConflict, springboot.ProcessConflictFail)()
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+				test.WriteFile(t, filepath.Join(filepath.Dir(f.Build.Layers.Root), "other-buildpack", "launch.toml"), `[[processes]]
+type = "web"
+command = "test-command"
+`)
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(e.Contribute()).To(gomega.MatchError(gomega.ContainSubstring("process type web was contributed by other-buildpack")))
+			})
+
+			it("slices by Spring-Boot-Layers-Index", func() {
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes/
+Spring-Boot-Lib: test-lib/
+Spring-Boot-Layers-Index: test-index/layers.idx
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test-index", "layers.idx"), `- "dependencies":
+  - "test-lib/"
+- "application":
+  - "test-classes/"
+  - "test-index/"
+`)
+				test.TouchFile(t, f.Build.Application.Root, "test-classes", "Test.class")
+				test.TouchFile(t, f.Build.Application.Root, "test-lib", "test-1.2.3.jar")
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(e.Contribute()).To(gomega.Succeed())
+
+				metadata.Slices = layers.Slices{
+					{Paths: []string{"test-lib/test-1.2.3.jar"}},
+					{Paths: []string{"test-classes/Test.class", "test-index/layers.idx"}},
+					{Paths: []string{"META-INF/MANIFEST.MF"}},
+				}
+				g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(metadata))
+				g.Expect(filepath.Join(f.Build.Layers.Layer("slice-manifest").Root, springboot.SliceManifestFile)).
+					To(gomega.BeARegularFile())
+			})
+
+			it("ignores Spring-Boot-Layers-Index when slicing by location", func() {
+				defer test.ReplaceEnv(t, config.Slices, config.SlicesLocation)()
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes/
+Spring-Boot-Lib: test-lib/
+Spring-Boot-Layers-Index: test-index/layers.idx
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test-index", "layers.idx"), `- "application":
+  - "test-classes/"
+`)
+				test.TouchFile(t, f.Build.Application.Root, "test-classes", "Test.class")
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(e.Contribute()).To(gomega.Succeed())
+
+				metadata.Slices = layers.Slices{
+					{Paths: []string{"test-index/layers.idx"}},
+					{},
+					{},
+					{Paths: []string{"test-classes/Test.class"}},
+					{Paths: []string{"META-INF/MANIFEST.MF"}},
+				}
+				g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(metadata))
+			})
+
+			it("does not slice when slices is none", func() {
+				defer test.ReplaceEnv(t, config.Slices, config.SlicesNone)()
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(e.Contribute()).To(gomega.Succeed())
+
+				metadata.Slices = layers.Slices{}
+				g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(metadata))
+			})
+
+			it("removes excluded paths and excludes them from slices and class path", func() {
+				defer test.ReplaceEnv(t, springboot.ExcludePatterns, "test-lib/excluded-*.jar")()
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, springboot.IgnoreFile), "# fixtures\ntest-fixtures/\n")
+				test.TouchFile(t, f.Build.Application.Root, "test-fixtures", "test-fixture.txt")
+				test.TouchFile(t, f.Build.Application.Root, "test-lib", "excluded-1.2.3.jar")
+				test.TouchFile(t, f.Build.Application.Root, "test-lib", "test-1.2.3.jar")
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(e.Metadata.ClassPath).NotTo(gomega.ContainElement(
+					filepath.Join(f.Build.Application.Root, "test-lib", "excluded-1.2.3.jar")))
+				g.Expect(e.Metadata.ClassPath).To(gomega.ContainElement(
+					filepath.Join(f.Build.Application.Root, "test-lib", "test-1.2.3.jar")))
+
+				g.Expect(e.Contribute()).To(gomega.Succeed())
+
+				var md launch.Metadata
+				_, err = toml.DecodeFile(filepath.Join(f.Build.Layers.Root, "launch.toml"), &md)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(md.Slices).To(gomega.Equal(layers.Slices{
+					{Paths: []string{springboot.IgnoreFile}},
+					{Paths: []string{"test-lib/test-1.2.3.jar"}},
+					{},
+					{},
+					{Paths: []string{"META-INF/MANIFEST.MF"}},
+				}))
+				g.Expect(filepath.Join(f.Build.Application.Root, "test-fixtures")).NotTo(gomega.BeAnExistingFile())
+				g.Expect(filepath.Join(f.Build.Application.Root, "test-lib", "excluded-1.2.3.jar")).NotTo(gomega.BeAnExistingFile())
+				g.Expect(filepath.Join(f.Build.Application.Root, "test-lib", "test-1.2.3.jar")).To(gomega.BeAnExistingFile())
+			})
+
 			it("adds remainder files to slice", func() {
 				test.TouchFile(t, f.Build.Application.Root, "META-INF", "test-file")
 
@@ -172,12 +424,38 @@ Spring-Boot-Version: test-version`)
 			})
 		})
 
-		it("contributes dependencies to BOM", func() {
+		it("refers to BOM above plan dependency limit", func() {
+			defer test.ReplaceEnv(t, springboot.PlanDependencyLimit, "1")()
 			test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"),
 				filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1-1.2.3.jar"))
 			test.CopyFile(t, filepath.Join("testdata", "test-artifact-2-4.5.6-SNAPSHOT.jar"),
 				filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-2-4.5.6-SNAPSHOT.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			bom := filepath.Join(f.Build.Layers.Layer("dependencies").Root, springboot.BOMFile)
+			g.Expect(p.Metadata).NotTo(gomega.HaveKey("dependencies"))
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("dependencies-file", bom))
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("dependencies-count", 2))
+			g.Expect(bom).To(gomega.BeARegularFile())
+		})
 
+		it("reports duplicate dependencies once", func() {
+			test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"),
+				filepath.Join(f.Build.Application.Root, "test-lib", "a", "test-artifact-1-1.2.3.jar"))
+			test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"),
+				filepath.Join(f.Build.Application.Root, "test-lib", "b", "test-artifact-1-1.2.3.jar"))
 			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
 				`
 Spring-Boot-Classes: test-classes
@@ -189,36 +467,15 @@ Spring-Boot-Version: test-version`)
 			g.Expect(ok).To(gomega.BeTrue())
 			g.Expect(err).NotTo(gomega.HaveOccurred())
 
-			g.Expect(e.Plan()).To(gomega.Equal(buildpackplan.Plan{
-				Name:    springboot.Dependency,
-				Version: "",
-				Metadata: buildpackplan.Metadata{
-					"lib":         "test-lib",
-					"start-class": "test-start-class",
-					"version":     "test-version",
-					"classes":     "test-classes",
-					"classpath": []string{
-						filepath.Join(f.Build.Application.Root, "test-classes"),
-						filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1-1.2.3.jar"),
-						filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-2-4.5.6-SNAPSHOT.jar"),
-					},
-					"dependencies": springboot.JARDependencies{
-						{
-							Name:    "test-artifact-1",
-							Version: "1.2.3",
-							SHA256:  "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
-						},
-						{
-							Name:    "test-artifact-2",
-							Version: "4.5.6-SNAPSHOT",
-							SHA256:  "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
-						},
-					},
-				},
-			}))
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata["dependencies"]).To(gomega.HaveLen(1))
 		})
 
-		it("handles no dependencies in Spring-Boot-Lib", func() {
+		it("reports shared dependencies", func() {
+			defer test.ReplaceEnv(t, springboot.SharedDependenciesEnabled, "true")()
+			test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"),
+				filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1-1.2.3.jar"))
 			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
 				`
 Spring-Boot-Classes: test-classes
@@ -230,24 +487,72 @@ Spring-Boot-Version: test-version`)
 			g.Expect(ok).To(gomega.BeTrue())
 			g.Expect(err).NotTo(gomega.HaveOccurred())
 
-			g.Expect(e.Plan()).To(gomega.Equal(buildpackplan.Plan{
-				Name:    springboot.Dependency,
-				Version: "",
-				Metadata: buildpackplan.Metadata{
-					"lib":         "test-lib",
-					"start-class": "test-start-class",
-					"version":     "test-version",
-					"classes":     "test-classes",
-					"classpath": []string{
-						filepath.Join(f.Build.Application.Root, "test-classes"),
-					},
-					"dependencies": springboot.JARDependencies{},
-				},
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+			g.Expect(filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1-1.2.3.jar")).NotTo(gomega.BeAnExistingFile())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata["dependencies"]).To(gomega.HaveLen(1))
+		})
+
+		it("records JDK modules", func() {
+			defer test.ReplaceEnv(t, springboot.JDKModulesEnabled, "true")()
+			test.CopyFile(t, filepath.Join("testdata", "runtime_hints", "test", "Database.class"),
+				filepath.Join(f.Build.Application.Root, "test-classes", "test", "Database.class"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("jdk-modules", []string{"java.base", "java.logging", "java.sql"}))
+			g.Expect(p.Metadata).NotTo(gomega.HaveKey("runtime-hints"))
+		})
+
+		it("reports nested dependencies", func() {
+			defer test.ReplaceEnv(t, springboot.NestedDependencies, "true")()
+			test.CopyFile(t, filepath.Join("testdata", "test-uber-1.0.0.jar"),
+				filepath.Join(f.Build.Application.Root, "test-lib", "test-uber-1.0.0.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata["dependencies"]).To(gomega.HaveLen(2))
+			g.Expect(p.Metadata["dependencies"]).To(gomega.ContainElement(springboot.JARDependency{
+				Name:     "test-nested",
+				Version:  "2.0.0",
+				SHA256:   "ed1e1dcf971990c1b89676ae785436106f7548b1ae41d174ca9d3bfb9661a477",
+				NestedIn: "test-uber-1.0.0.jar",
 			}))
 		})
 
-		it("contributes command", func() {
-			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "test.jar"))
+		it("reports Maven group of dependencies", func() {
+			p := filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1.2.3.jar")
+			g.Expect(os.MkdirAll(filepath.Dir(p), 0755)).To(gomega.Succeed())
+			out, err := os.Create(p)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			z := zip.NewWriter(out)
+			_, err = z.Create("META-INF/maven/test-group/test-artifact/pom.properties")
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(z.Close()).To(gomega.Succeed())
+			g.Expect(out.Close()).To(gomega.Succeed())
+
 			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
 				`
 Spring-Boot-Classes: test-classes
@@ -259,16 +564,800 @@ Spring-Boot-Version: test-version`)
 			g.Expect(ok).To(gomega.BeTrue())
 			g.Expect(err).NotTo(gomega.HaveOccurred())
 
-			g.Expect(e.Contribute()).To(gomega.Succeed())
+			plan, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(plan.Metadata["dependencies"]).To(gomega.ConsistOf(
+				gomega.WithTransform(func(d springboot.JARDependency) string { return d.Group }, gomega.Equal("test-group")),
+			))
+		})
 
-			layer := f.Build.Layers.Layer("spring-boot")
-			g.Expect(layer).To(test.HaveLayerMetadata(true, true, true))
-			g.Expect(layer).To(test.HavePrependPathSharedEnvironment("CLASSPATH", strings.Join([]string{
-				filepath.Join(f.Build.Application.Root, "test-classes"),
-				filepath.Join(f.Build.Application.Root, "test-lib", "test.jar"),
-			}, string(filepath.ListSeparator))))
+		when("symlinks", func() {
+
+			it.Before(func() {
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+			})
+
+			it("follows symlinked lib directory within application", func() {
+				test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"),
+					filepath.Join(f.Build.Application.Root, "bazel-out", "lib", "test-artifact-1-1.2.3.jar"))
+				g.Expect(os.Symlink(filepath.Join("bazel-out", "lib"), filepath.Join(f.Build.Application.Root, "test-lib"))).
+					To(gomega.Succeed())
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				p, err := e.Plan()
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(p.Metadata["dependencies"]).To(gomega.HaveLen(1))
+
+				g.Expect(e.Contribute()).To(gomega.Succeed())
+
+				var md launch.Metadata
+				_, err = toml.DecodeFile(filepath.Join(f.Build.Layers.Root, "launch.toml"), &md)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				var paths []string
+				for _, s := range md.Slices {
+					paths = append(paths, s.Paths...)
+				}
+				g.Expect(paths).To(gomega.ConsistOf("META-INF/MANIFEST.MF", "bazel-out/lib/test-artifact-1-1.2.3.jar", "test-lib"))
+			})
+
+			it("returns error for symlink outside of application", func() {
+				outside := filepath.Join(test.ScratchDir(t, "outside"), "test-artifact-1-1.2.3.jar")
+				test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"), outside)
+				test.TouchFile(t, f.Build.Application.Root, "test-lib", "test-file")
+				g.Expect(os.Symlink(outside, filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1-1.2.3.jar"))).
+					To(gomega.Succeed())
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				_, err = e.Plan()
+				g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("outside of")))
+
+				g.Expect(e.Contribute()).To(gomega.MatchError(gomega.ContainSubstring("outside of")))
+			})
+		})
+
+		it("contributes dependencies to BOM", func() {
+			test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"),
+				filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1-1.2.3.jar"))
+			test.CopyFile(t, filepath.Join("testdata", "test-artifact-2-4.5.6-SNAPSHOT.jar"),
+				filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-2-4.5.6-SNAPSHOT.jar"))
+
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Plan()).To(gomega.Equal(buildpackplan.Plan{
+				Name:    springboot.Dependency,
+				Version: "",
+				Metadata: buildpackplan.Metadata{
+					"layers-index":    "",
+					"lib":             "test-lib",
+					"loader-path":     []string(nil),
+					"start-class":     "test-start-class",
+					"version":         "test-version",
+					"classes":         "test-classes",
+					"classpath-index": "",
+					"classpath": []string{
+						filepath.Join(f.Build.Application.Root, "test-classes"),
+						filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1-1.2.3.jar"),
+						filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-2-4.5.6-SNAPSHOT.jar"),
+					},
+					"manifest": buildpackplan.Metadata{},
+					"labels":   map[string]string{springboot.SpringBootVersionLabel: "test-version"},
+					"loader": buildpackplan.Metadata{
+						"diagnostics": []string{"Spring-Boot-Classes test-classes does not exist"},
+						"lib-version": "",
+						"main-class":  "",
+						"present":     false,
+					},
+					"dependencies": springboot.JARDependencies{
+						{
+							Name:    "test-artifact-1",
+							Version: "1.2.3",
+							SHA256:  "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+						{
+							Name:    "test-artifact-2",
+							Version: "4.5.6-SNAPSHOT",
+							SHA256:  "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+						},
+					},
+				},
+			}))
+		})
+
+		it("contributes dependencies of every lib directory to BOM", func() {
+			defer test.ReplaceEnv(t, springboot.LibProvided, "true")()
+
+			test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"),
+				filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1-1.2.3.jar"))
+			test.CopyFile(t, filepath.Join("testdata", "test-artifact-2-4.5.6-SNAPSHOT.jar"),
+				filepath.Join(f.Build.Application.Root, "test-extra", "test-artifact-2-4.5.6-SNAPSHOT.jar"))
+			test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"),
+				filepath.Join(f.Build.Application.Root, "test-lib-provided", "test-artifact-3-7.8.9.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "test-index", "classpath.idx"), `- "test-lib/test-artifact-1-1.2.3.jar"
+- "test-extra/test-artifact-2-4.5.6-SNAPSHOT.jar"
+`)
+
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Classpath-Index: test-index/classpath.idx
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			var names []string
+			for _, d := range p.Metadata["dependencies"].(springboot.JARDependencies) {
+				names = append(names, d.Name)
+			}
+			g.Expect(names).To(gomega.Equal([]string{"test-artifact-1", "test-artifact-2", "test-artifact-3"}))
+		})
+
+		when("a dependency cannot be read", func() {
+
+			var restoreDatabase func()
+
+			it.Before(func() {
+				d := filepath.Join(test.ScratchDir(t, "fingerprints"), "fingerprints.json")
+				test.WriteFile(t, d, `[{"name": "test-library", "version": "1.0.0", "sha256": "test-sha256"}]`)
+				restoreDatabase = test.ReplaceEnv(t, springboot.FingerprintDatabase, d)
+
+				test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"),
+					filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1-1.2.3.jar"))
+				test.CopyFile(t, filepath.Join("testdata", "test-corrupt.jar"),
+					filepath.Join(f.Build.Application.Root, "test-lib", "test-corrupt.jar"))
+
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+			})
+
+			it.After(func() {
+				restoreDatabase()
+			})
+
+			it("records it as unidentified", func() {
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				p, err := e.Plan()
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(p.Metadata["dependencies"]).To(gomega.HaveLen(1))
+
+				u := p.Metadata["unidentified-dependencies"].(springboot.UnidentifiedDependencies)
+				g.Expect(u).To(gomega.HaveLen(1))
+				g.Expect(u[0].Name).To(gomega.Equal("test-corrupt.jar"))
+				g.Expect(u[0].SHA256).To(gomega.Equal(digest(filepath.Join("testdata", "test-corrupt.jar"))))
+				g.Expect(u[0].Reason).To(gomega.ContainSubstring("checksum error"))
+			})
+
+			it("returns error when configured to fail", func() {
+				defer test.ReplaceEnv(t, springboot.UnreadableJARs, springboot.UnreadableJARsFail)()
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				_, err = e.Plan()
+				g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("test-corrupt.jar")))
+			})
+		})
+
+		when("scans time out", func() {
+
+			it.Before(func() {
+				test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"),
+					filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1-1.2.3.jar"))
+
+				test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+					`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+			})
+
+			it("returns error when scanning dependencies times out", func() {
+				defer test.ReplaceEnv(t, springboot.ScanTimeout, "1ns")()
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				_, err = e.Plan()
+				g.Expect(err).To(gomega.MatchError(gomega.ContainSubstring("Scanning dependencies did not complete within BP_SPRING_BOOT_SCAN_TIMEOUT")))
+			})
+
+			it("returns error when slicing times out", func() {
+				defer test.ReplaceEnv(t, springboot.ScanTimeout, "1ns")()
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				g.Expect(e.Contribute()).To(gomega.MatchError(gomega.ContainSubstring("Slicing did not complete within BP_SPRING_BOOT_SCAN_TIMEOUT")))
+			})
+
+			it("completes within timeout", func() {
+				defer test.ReplaceEnv(t, springboot.ScanTimeout, "1m")()
+
+				e, ok, err := springboot.NewSpringBoot(f.Build)
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+
+				p, err := e.Plan()
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(p.Metadata["dependencies"]).To(gomega.HaveLen(1))
+				g.Expect(e.Contribute()).To(gomega.Succeed())
+			})
+		})
+
+		it("contributes dependencies to BOM layer", func() {
+			test.CopyFile(t, filepath.Join("testdata", "test-artifact-1-1.2.3.jar"),
+				filepath.Join(f.Build.Application.Root, "test-lib", "test-artifact-1-1.2.3.jar"))
+
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			_, err = e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			layer := f.Build.Layers.Layer("dependencies")
+			g.Expect(layer).To(test.HaveLayerMetadata(true, false, true))
+			g.Expect(layer).To(test.HaveOverrideSharedEnvironment("SPRING_BOOT_DEPENDENCIES", filepath.Join(layer.Root, springboot.BOMFile)))
+			g.Expect(filepath.Join(layer.Root, springboot.BOMFile)).To(test.HaveContent(`{
+  "schema-version": "1",
+  "dependencies": [
+    {
+      "name": "test-artifact-1",
+      "version": "1.2.3",
+      "sha256": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+    }
+  ]
+}`))
+		})
+
+		it("handles no dependencies in Spring-Boot-Lib", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Plan()).To(gomega.Equal(buildpackplan.Plan{
+				Name:    springboot.Dependency,
+				Version: "",
+				Metadata: buildpackplan.Metadata{
+					"layers-index":    "",
+					"lib":             "test-lib",
+					"loader-path":     []string(nil),
+					"start-class":     "test-start-class",
+					"version":         "test-version",
+					"classes":         "test-classes",
+					"classpath-index": "",
+					"classpath": []string{
+						filepath.Join(f.Build.Application.Root, "test-classes"),
+					},
+					"manifest": buildpackplan.Metadata{},
+					"labels":   map[string]string{springboot.SpringBootVersionLabel: "test-version"},
+					"loader": buildpackplan.Metadata{
+						"diagnostics": []string{
+							"Spring-Boot-Classes test-classes does not exist",
+							"Spring-Boot-Lib test-lib does not exist",
+						},
+						"lib-version": "",
+						"main-class":  "",
+						"present":     false,
+					},
+					"dependencies": springboot.JARDependencies{},
+				},
+			}))
+		})
+
+		it("labels Spring Cloud Task applications", func() {
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "spring-cloud-task-core-2.2.3.RELEASE.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata["labels"]).To(gomega.HaveKeyWithValue(springboot.TypeLabel, "task"))
+		})
+
+		it("omits web process type for Spring Cloud Task applications", func() {
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "spring-cloud-task-core-2.2.3.RELEASE.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+
+			var md launch.Metadata
+			_, err = toml.DecodeFile(filepath.Join(f.Build.Layers.Root, "launch.toml"), &md)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			command := "java -cp $CLASSPATH $JAVA_OPTS test-start-class $BPL_SPRING_BOOT_ARGS"
+			g.Expect(md.Processes).To(gomega.Equal(launch.Processes{
+				{Type: "spring-boot", Command: command},
+				{Type: "task", Command: command},
+			}))
+		})
+
+		it("records web application type", func() {
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "spring-webflux-5.2.4.RELEASE.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("web-application-type", springboot.Reactive))
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+			g.Expect(f.Build.Layers.Layer("web-application-type")).To(test.HaveDefaultLaunchEnvironment("BPL_THREAD_COUNT", springboot.ReactiveThreadCount))
+		})
+
+		it("records embedded server", func() {
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "tomcat-embed-core-9.0.31.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("server", buildpackplan.Metadata{
+				"name":    "tomcat",
+				"port":    "8080",
+				"version": "9.0.31",
+			}))
+			g.Expect(p.Metadata["labels"]).To(gomega.HaveKeyWithValue(springboot.ServerLabel, "tomcat"))
+			g.Expect(p.Metadata["labels"]).To(gomega.HaveKeyWithValue(springboot.ServerVersionLabel, "9.0.31"))
+		})
+
+		it("labels Spring Security version", func() {
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "spring-security-core-5.3.0.RELEASE.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata["labels"]).To(gomega.HaveKeyWithValue(springboot.SecurityLabel, "5.3.0.RELEASE"))
+		})
+
+		it("returns error for invalid BP_SPRING_BOOT_WARN_NO_SECURITY on web applications", func() {
+			defer test.ReplaceEnv(t, springboot.WarnNoSecurity, "test-value")()
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "spring-webmvc-5.2.4.RELEASE.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Contribute()).To(gomega.MatchError(gomega.ContainSubstring("invalid BP_SPRING_BOOT_WARN_NO_SECURITY")))
+		})
+
+		it("records Kotlin language and version", func() {
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "kotlin-stdlib-1.3.72.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("language", "kotlin"))
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("kotlin-version", "1.3.72"))
+			g.Expect(p.Metadata["labels"]).To(gomega.HaveKeyWithValue(springboot.LanguageLabel, "kotlin"))
+			g.Expect(p.Metadata["labels"]).To(gomega.HaveKeyWithValue(springboot.KotlinVersionLabel, "1.3.72"))
+		})
+
+		it("records property encryption mechanisms", func() {
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "jasypt-spring-boot-3.0.3.jar"))
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "spring-cloud-config-client-2.2.2.RELEASE.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("encryption", []string{"config-server", "jasypt"}))
+			g.Expect(p.Metadata["labels"]).To(gomega.HaveKeyWithValue(springboot.EncryptionLabel, "config-server,jasypt"))
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+			g.Expect(f.Build.Layers.Layer("encryption")).To(test.HaveLayerMetadata(false, false, true))
+		})
+
+		it("returns error when Spring-Boot-Version does not satisfy configured version", func() {
+			defer test.ReplaceEnv(t, config.Version, ">=2.3")()
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: 2.2.5.RELEASE`)
+
+			_, _, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(err).To(gomega.MatchError("Spring-Boot-Version 2.2.5.RELEASE does not satisfy >=2.3"))
+		})
+
+		it("contributes web process type for configured default process type of Spring Cloud Task applications", func() {
+			defer test.ReplaceEnv(t, config.Process, "task")()
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "spring-cloud-task-core-2.2.3.RELEASE.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+
+			var md launch.Metadata
+			_, err = toml.DecodeFile(filepath.Join(f.Build.Layers.Root, "launch.toml"), &md)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			command := "java -cp $CLASSPATH $JAVA_OPTS test-start-class $BPL_SPRING_BOOT_ARGS"
+			g.Expect(md.Processes).To(gomega.Equal(launch.Processes{
+				{Type: "spring-boot", Command: command},
+				{Type: "task", Command: command},
+				{Type: "web", Command: command},
+			}))
+		})
+
+		it("contributes templated command", func() {
+			defer test.ReplaceEnv(t, springboot.CommandTemplate, "test-wrapper {{.StartClass}}")()
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+
+			g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(layers.Metadata{
+				Slices: layers.Slices{{}, {}, {}, {}, {Paths: []string{"META-INF/MANIFEST.MF"}}},
+				Processes: layers.Processes{
+					{Type: "spring-boot", Command: "test-wrapper test-start-class"},
+					{Type: "task", Command: "test-wrapper test-start-class"},
+					{Type: "web", Command: "test-wrapper test-start-class"},
+				},
+			}))
+		})
+
+		it("labels Spring Boot version only without Implementation-Version", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("labels", map[string]string{
+				springboot.SpringBootVersionLabel: "test-version",
+			}))
+		})
+
+		it("labels manifest headers", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Implementation-Title: test-title
+Implementation-Version: 1.2.3
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			p, err := e.Plan()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(p.Metadata).To(gomega.HaveKeyWithValue("labels", map[string]string{
+				springboot.SpringBootVersionLabel:                          "test-version",
+				springboot.VersionLabel:                                    "1.2.3",
+				"org.springframework.boot.manifest.implementation-title":   "test-title",
+				"org.springframework.boot.manifest.implementation-version": "1.2.3",
+			}))
+		})
+
+		it("contributes debug and JMX profile scripts", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("debug")
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+
+			b, err := ioutil.ReadFile(filepath.Join(layer.Root, "profile.d", "debug"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(string(b)).To(gomega.ContainSubstring("-agentlib:jdwp=transport=dt_socket,server=y,address=${DEBUG_ADDRESS},suspend=${DEBUG_SUSPEND}"))
+
+			b, err = ioutil.ReadFile(filepath.Join(layer.Root, "profile.d", "jmx"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(string(b)).To(gomega.ContainSubstring("-Dcom.sun.management.jmxremote.port=${JMX_PORT}"))
+		})
+
+		it("contributes DevTools restart configuration in dev mode", func() {
+			defer test.ReplaceEnv(t, springboot.Dev, "true")()
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "spring-boot-devtools-2.2.5.RELEASE.jar"))
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-classes", "Test.class"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer(springboot.DevToolsLayer)
+			g.Expect(filepath.Join(layer.Root, springboot.TriggerFile)).To(gomega.BeARegularFile())
+			g.Expect(filepath.Join(f.Build.Application.Root, "test-classes", springboot.TriggerFile)).NotTo(gomega.BeAnExistingFile())
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(layer).To(test.HaveOverrideLaunchEnvironment("SPRING_BOOT_TRIGGER_FILE", filepath.Join(layer.Root, springboot.TriggerFile)))
+			g.Expect(layer).To(test.HaveAppendLaunchEnvironment("JAVA_OPTS",
+				" -Dspring.devtools.restart.enabled=true -Dspring.devtools.restart.trigger-file=%s", springboot.TriggerFile))
+
+			g.Expect(e.Metadata.ClassPath).To(gomega.ContainElement(layer.Root))
+			g.Expect(e.Metadata.ClassPath).To(gomega.ContainElement(
+				filepath.Join(f.Build.Application.Root, "test-lib", "spring-boot-devtools-2.2.5.RELEASE.jar")))
+
+			var md launch.Metadata
+			_, err = toml.DecodeFile(filepath.Join(f.Build.Layers.Root, "launch.toml"), &md)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(md.Slices[len(md.Slices)-1].Paths).To(gomega.Equal([]string{filepath.Join("test-classes", "Test.class")}))
+		})
+
+		it("keeps excluded DevTools in dev mode", func() {
+			defer test.ReplaceEnv(t, springboot.Dev, "true")()
+			defer test.ReplaceEnv(t, springboot.ExcludePatterns, "test-lib/spring-boot-devtools-*.jar,test-lib/excluded.jar")()
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "spring-boot-devtools-2.2.5.RELEASE.jar"))
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "excluded.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			devtools := filepath.Join(f.Build.Application.Root, "test-lib", "spring-boot-devtools-2.2.5.RELEASE.jar")
+			excluded := filepath.Join(f.Build.Application.Root, "test-lib", "excluded.jar")
+			g.Expect(e.Metadata.ClassPath).To(gomega.ContainElement(devtools))
+			g.Expect(e.Metadata.ClassPath).NotTo(gomega.ContainElement(excluded))
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+
+			g.Expect(devtools).To(gomega.BeARegularFile())
+			g.Expect(excluded).NotTo(gomega.BeAnExistingFile())
+		})
+
+		it("does not contribute DevTools restart configuration by default", func() {
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+
+			g.Expect(filepath.Join(f.Build.Layers.Layer(springboot.DevToolsLayer).Root, springboot.TriggerFile)).NotTo(gomega.BeAnExistingFile())
+			g.Expect(e.Metadata.ClassPath).NotTo(gomega.ContainElement(f.Build.Layers.Layer(springboot.DevToolsLayer).Root))
+		})
+
+		it("appends additional class path entries to CLASSPATH", func() {
+			defer test.ReplaceEnv(t, springboot.AdditionalClassPath, "/test-agent.jar")()
+			f.AddService("classpath", map[string]interface{}{"classpath": "/test-driver.jar"})
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+
+			g.Expect(f.Build.Layers.Layer("spring-boot")).To(test.HavePrependPathSharedEnvironment("CLASSPATH", strings.Join([]string{
+				filepath.Join(f.Build.Application.Root, "test-classes"),
+				"/test-agent.jar",
+				"/test-driver.jar",
+			}, string(filepath.ListSeparator))))
+		})
+
+		it("prepends logging configuration to CLASSPATH", func() {
+			f.AddService("logging-config", map[string]interface{}{"logback.xml": "<configuration/>"})
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer(springboot.LoggingConfigLayer)
+			g.Expect(filepath.Join(layer.Root, "logback.xml")).To(test.HaveContent("<configuration/>"))
+			g.Expect(f.Build.Layers.Layer("spring-boot")).To(test.HavePrependPathSharedEnvironment("CLASSPATH", strings.Join([]string{
+				layer.Root,
+				filepath.Join(f.Build.Application.Root, "test-classes"),
+			}, string(filepath.ListSeparator))))
+		})
+
+		it("contributes command", func() {
+			test.TouchFile(t, filepath.Join(f.Build.Application.Root, "test-lib", "test.jar"))
+			test.WriteFile(t, filepath.Join(f.Build.Application.Root, "META-INF", "MANIFEST.MF"),
+				`
+Spring-Boot-Classes: test-classes
+Spring-Boot-Lib: test-lib
+Start-Class: test-start-class
+Spring-Boot-Version: test-version`)
+
+			e, ok, err := springboot.NewSpringBoot(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(e.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("spring-boot")
+			g.Expect(layer).To(test.HaveLayerMetadata(true, true, true))
+			g.Expect(layer).To(test.HavePrependPathSharedEnvironment("CLASSPATH", strings.Join([]string{
+				filepath.Join(f.Build.Application.Root, "test-classes"),
+				filepath.Join(f.Build.Application.Root, "test-lib", "test.jar"),
+			}, string(filepath.ListSeparator))))
+			g.Expect(filepath.Join(layer.Root, springboot.ClassPathFile)).To(test.HaveContent(strings.Join([]string{
+				filepath.Join(f.Build.Application.Root, "test-classes"),
+				filepath.Join(f.Build.Application.Root, "test-lib", "test.jar"),
+			}, "\n") + "\n"))
+			g.Expect(layer).To(test.HaveOverrideSharedEnvironment("SPRING_BOOT_CLASSPATH_FILE", filepath.Join(layer.Root, springboot.ClassPathFile)))
+
+			i, err := os.Stat(filepath.Join(layer.Root, "env", "CLASSPATH"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(i.ModTime().UTC()).To(gomega.Equal(reproducible.DefaultTime))
 
-			command := "java -cp $CLASSPATH $JAVA_OPTS test-start-class"
+			command := "java -cp $CLASSPATH $JAVA_OPTS test-start-class $BPL_SPRING_BOOT_ARGS"
 			g.Expect(f.Build.Layers).To(test.HaveApplicationMetadata(layers.Metadata{
 				Slices: layers.Slices{
 					{},
@@ -0,0 +1,406 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+// StartClassVerification is the environment variable that fails the build when the Start-Class cannot be verified
+// when set to true, and disables verification when set to false.  When unset, verification failures are warnings.
+const StartClassVerification = "BP_SPRING_BOOT_VERIFY_START_CLASS"
+
+const (
+	accPublic                 = 0x0001
+	accStatic                 = 0x0008
+	classMagic                = 0xCAFEBABE
+	kotlinSuffix              = "Kt"
+	mainDescriptor            = "([Ljava/lang/String;)V"
+	noArgsMainDescriptor      = "()V"
+	runtimeVisibleAnnotations = "RuntimeVisibleAnnotations"
+	springBootApplication     = "Lorg/springframework/boot/autoconfigure/SpringBootApplication;"
+)
+
+// FindStartClasses returns the fully-qualified names of all classes below root that are annotated with
+// @SpringBootApplication and declare a public static void main(String[]) method.  As Kotlin compiles a top-level main
+// function to a static method of a separate <File>Kt class, a <Name>Kt class that declares a main method is returned
+// for an annotated <Name> class that does not.  Class files are inspected without loading them, and those that cannot
+// be read are skipped and logged at debug level.
+func FindStartClasses(root string, logger logger.Logger) ([]string, error) {
+	classes := make(map[string]classFile)
+
+	if err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || filepath.Ext(path) != ".class" {
+			return nil
+		}
+
+		c, err := readClass(path)
+		if err != nil {
+			logger.Debug("Skipping unreadable class file %s: %s", path, err)
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		classes[strings.ReplaceAll(strings.TrimSuffix(rel, ".class"), string(filepath.Separator), ".")] = c
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	var candidates []string
+	for name, c := range classes {
+		if !c.annotated {
+			continue
+		}
+
+		if c.main {
+			candidates = append(candidates, name)
+		} else if k, ok := classes[name+kotlinSuffix]; ok && k.hasMain(name+kotlinSuffix) {
+			candidates = append(candidates, name+kotlinSuffix)
+		}
+	}
+
+	sort.Strings(candidates)
+	return candidates, nil
+}
+
+func readClass(path string) (classFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return classFile{}, err
+	}
+	defer f.Close()
+
+	return readClassFile(f)
+}
+
+// VerifyStartClass checks that the Start-Class exists in the class path, as a class file in a directory or as an
+// entry of a JAR, and that it, or a superclass in the class path, declares a public static void main(String[])
+// method.  A Kotlin <File>Kt class may instead declare any static main method, with or without arguments.
+// Superclasses that are not in the class path (e.g. in the JDK) cannot be inspected and are assumed to declare one.
+func VerifyStartClass(metadata Metadata) error {
+	if metadata.StartClass == "" {
+		return fmt.Errorf("Start-Class is not set and could not be discovered")
+	}
+
+	start := strings.ReplaceAll(metadata.StartClass, ".", "/")
+	seen := make(map[string]bool)
+
+	for name := start; !seen[name]; {
+		seen[name] = true
+
+		c, ok, err := findClassFile(metadata.ClassPath, name)
+		if err != nil {
+			return err
+		}
+
+		if !ok && name == start {
+			return fmt.Errorf("Start-Class %s not found in %s or the JARs in %s", metadata.StartClass, metadata.Classes, metadata.Lib)
+		} else if !ok || c.hasMain(name) {
+			return nil
+		}
+
+		if c.super == "" || c.super == "java/lang/Object" {
+			break
+		}
+
+		name = c.super
+	}
+
+	return fmt.Errorf("Start-Class %s does not declare a public static void main(String[]) method", metadata.StartClass)
+}
+
+// verifyStartClass returns whether the Start-Class should be verified and whether a verification failure should fail
+// the build.
+func verifyStartClass() (bool, bool, error) {
+	if _, ok := config.Lookup(StartClassVerification); !ok {
+		return true, false, nil
+	}
+
+	v, err := config.LookupBool(StartClassVerification, true)
+	return v, v, err
+}
+
+// classFile is the subset of a class file required to find and verify start classes.
+type classFile struct {
+	annotated  bool
+	kotlinMain bool
+	main       bool
+	super      string
+}
+
+// hasMain returns whether the class named name declares a method that can be launched as its main method.
+func (c classFile) hasMain(name string) bool {
+	return c.main || (strings.HasSuffix(name, kotlinSuffix) && c.kotlinMain)
+}
+
+func findClassFile(classPath []string, name string) (classFile, bool, error) {
+	n := name + ".class"
+
+	for _, e := range classPath {
+		if i, err := os.Stat(e); err != nil {
+			continue
+		} else if i.IsDir() {
+			f, err := os.Open(filepath.Join(e, filepath.FromSlash(n)))
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return classFile{}, false, err
+			}
+
+			c, err := readClassFile(f)
+			_ = f.Close()
+			if err != nil {
+				return classFile{}, false, fmt.Errorf("unable to read class file %s: %w", f.Name(), err)
+			}
+			return c, true, nil
+		}
+
+		if filepath.Ext(e) != ".jar" {
+			continue
+		}
+
+		if c, ok, err := findJARClassFile(e, n); err != nil || ok {
+			return c, ok, err
+		}
+	}
+
+	return classFile{}, false, nil
+}
+
+func findJARClassFile(jar string, name string) (classFile, bool, error) {
+	z, err := zip.OpenReader(jar)
+	if err != nil {
+		return classFile{}, false, nil
+	}
+	defer z.Close()
+
+	for _, f := range z.File {
+		if f.Name != name {
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return classFile{}, false, err
+		}
+		defer r.Close()
+
+		c, err := readClassFile(r)
+		if err != nil {
+			return classFile{}, false, fmt.Errorf("unable to read class file %s in %s: %w", name, jar, err)
+		}
+		return c, true, nil
+	}
+
+	return classFile{}, false, nil
+}
+
+func readClassFile(r io.Reader) (classFile, error) {
+	c := classReader{r: bufio.NewReader(r)}
+
+	if c.u4() != classMagic {
+		return classFile{}, c.invalid("not a class file")
+	}
+	c.skip(4) // minor and major version
+
+	utf8, classes := c.constantPool()
+	c.skip(4) // access flags, this class
+	super := c.u2()
+	c.skip(int(c.u2()) * 2)
+
+	for i, n := 0, int(c.u2()); i < n; i++ { // fields
+		c.skip(6)
+		c.attributes(utf8, nil)
+	}
+
+	var cf classFile
+	cf.super = utf8[classes[super]]
+
+	for i, n := 0, int(c.u2()); i < n; i++ {
+		access, name, descriptor := c.u2(), c.u2(), c.u2()
+		c.attributes(utf8, nil)
+
+		if utf8[name] != "main" || access&accStatic == 0 {
+			continue
+		}
+
+		if utf8[descriptor] == mainDescriptor && access&accPublic != 0 {
+			cf.main = true
+		}
+		if utf8[descriptor] == mainDescriptor || utf8[descriptor] == noArgsMainDescriptor {
+			cf.kotlinMain = true
+		}
+	}
+
+	c.attributes(utf8, func(name string, length uint32) {
+		if name != runtimeVisibleAnnotations {
+			c.skip(int(length))
+			return
+		}
+
+		for i, n := 0, int(c.u2()); i < n; i++ {
+			if utf8[c.annotation()] == springBootApplication {
+				cf.annotated = true
+			}
+		}
+	})
+
+	if c.err != nil {
+		return classFile{}, c.err
+	}
+
+	return cf, nil
+}
+
+// classReader reads the subset of the class file format required to find start classes.  The first error encountered
+// is retained and all subsequent reads return zero values.
+type classReader struct {
+	err error
+	r   io.Reader
+}
+
+func (c *classReader) annotation() uint16 {
+	t := c.u2()
+	for i, n := 0, int(c.u2()); i < n; i++ {
+		c.skip(2) // element name
+		c.elementValue()
+	}
+	return t
+}
+
+// attributes calls f for each attribute, which must consume exactly length bytes.  A nil f skips all attributes.
+func (c *classReader) attributes(utf8 map[uint16]string, f func(name string, length uint32)) {
+	for i, n := 0, int(c.u2()); i < n; i++ {
+		name, length := c.u2(), c.u4()
+
+		if f == nil {
+			c.skip(int(length))
+			continue
+		}
+
+		f(utf8[name], length)
+	}
+}
+
+// constantPool returns the Utf8 entries and the name indices of the Class entries of the constant pool.
+func (c *classReader) constantPool() (map[uint16]string, map[uint16]uint16) {
+	utf8 := make(map[uint16]string)
+	classes := make(map[uint16]uint16)
+
+	n := c.u2()
+	for i := uint16(1); i < n && c.err == nil; i++ {
+		switch t := c.u1(); t {
+		case 1: // Utf8
+			b := make([]byte, c.u2())
+			c.read(b)
+			utf8[i] = string(b)
+		case 7: // Class
+			classes[i] = c.u2()
+		case 8, 16, 19, 20: // String, MethodType, Module, Package
+			c.skip(2)
+		case 15: // MethodHandle
+			c.skip(3)
+		case 3, 4, 9, 10, 11, 12, 17, 18: // Integer, Float, Fieldref, Methodref, InterfaceMethodref, NameAndType, Dynamic, InvokeDynamic
+			c.skip(4)
+		case 5, 6: // Long, Double occupy two entries
+			c.skip(8)
+			i++
+		default:
+			c.err = c.invalid(fmt.Sprintf("unknown constant pool tag %d", t))
+		}
+	}
+
+	return utf8, classes
+}
+
+func (c *classReader) elementValue() {
+	switch t := c.u1(); t {
+	case 'B', 'C', 'D', 'F', 'I', 'J', 'S', 'Z', 's', 'c':
+		c.skip(2)
+	case 'e':
+		c.skip(4)
+	case '@':
+		c.annotation()
+	case '[':
+		for i, n := 0, int(c.u2()); i < n; i++ {
+			c.elementValue()
+		}
+	default:
+		if c.err == nil {
+			c.err = c.invalid(fmt.Sprintf("unknown element value tag %c", t))
+		}
+	}
+}
+
+func (c *classReader) invalid(message string) error {
+	return fmt.Errorf("invalid class file: %s", message)
+}
+
+func (c *classReader) read(b []byte) {
+	if c.err != nil {
+		return
+	}
+
+	_, c.err = io.ReadFull(c.r, b)
+}
+
+func (c *classReader) skip(n int) {
+	if c.err != nil {
+		return
+	}
+
+	_, c.err = io.CopyN(ioutil.Discard, c.r, int64(n))
+}
+
+func (c *classReader) u1() uint8 {
+	b := make([]byte, 1)
+	c.read(b)
+	return b[0]
+}
+
+func (c *classReader) u2() uint16 {
+	b := make([]byte, 2)
+	c.read(b)
+	return binary.BigEndian.Uint16(b)
+}
+
+func (c *classReader) u4() uint32 {
+	b := make([]byte, 4)
+	c.read(b)
+	return binary.BigEndian.Uint32(b)
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bpLogger "github.com/buildpacks/libbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestStartClass(t *testing.T) {
+	spec.Run(t, "StartClass", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		it("finds only annotated classes with main methods", func() {
+			g.Expect(springboot.FindStartClasses(filepath.Join("testdata", "main_class"), logger.Logger{})).
+				To(gomega.Equal([]string{"test.Application"}))
+		})
+
+		it("finds Kotlin classes with main methods of annotated classes", func() {
+			g.Expect(springboot.FindStartClasses(filepath.Join("testdata", "kotlin_main_class"), logger.Logger{})).
+				To(gomega.Equal([]string{"test.ApplicationKt"}))
+		})
+
+		it("skips unreadable class files", func() {
+			root := test.ScratchDir(t, "start-class")
+			g.Expect(helper.CopyDirectory(filepath.Join("testdata", "main_class"), root)).To(gomega.Succeed())
+			test.WriteFile(t, filepath.Join(root, "test", "Invalid.class"), "test-content")
+
+			b := &bytes.Buffer{}
+			g.Expect(springboot.FindStartClasses(root, logger.Logger{Logger: bpLogger.NewLogger(b, nil)})).
+				To(gomega.Equal([]string{"test.Application"}))
+			g.Expect(b.String()).To(gomega.ContainSubstring("Skipping unreadable class file %s", filepath.Join(root, "test", "Invalid.class")))
+		})
+
+		when("VerifyStartClass", func() {
+
+			classes := filepath.Join("testdata", "main_class")
+
+			it("verifies class file in directory", func() {
+				g.Expect(springboot.VerifyStartClass(springboot.Metadata{
+					ClassPath:  []string{filepath.Join("testdata", "missing"), classes},
+					StartClass: "test.Application",
+				})).To(gomega.Succeed())
+			})
+
+			it("verifies class file in JAR", func() {
+				jar := filepath.Join(test.ScratchDir(t, "start-class"), "test-1.2.3.jar")
+				w, err := os.Create(jar)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				z := zip.NewWriter(w)
+				e, err := z.Create("test/Application.class")
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				b, err := ioutil.ReadFile(filepath.Join(classes, "test", "Application.class"))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				_, err = e.Write(b)
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(z.Close()).To(gomega.Succeed())
+				g.Expect(w.Close()).To(gomega.Succeed())
+
+				g.Expect(springboot.VerifyStartClass(springboot.Metadata{
+					ClassPath:  []string{jar},
+					StartClass: "test.Application",
+				})).To(gomega.Succeed())
+			})
+
+			it("verifies Kotlin class file with main method without arguments", func() {
+				g.Expect(springboot.VerifyStartClass(springboot.Metadata{
+					ClassPath:  []string{filepath.Join("testdata", "kotlin_main_class")},
+					StartClass: "test.NoArgsKt",
+				})).To(gomega.Succeed())
+			})
+
+			it("returns error when Start-Class is not set", func() {
+				g.Expect(springboot.VerifyStartClass(springboot.Metadata{ClassPath: []string{classes}})).
+					To(gomega.MatchError("Start-Class is not set and could not be discovered"))
+			})
+
+			it("returns error when Start-Class does not exist", func() {
+				g.Expect(springboot.VerifyStartClass(springboot.Metadata{
+					Classes:    "test-classes",
+					ClassPath:  []string{classes},
+					Lib:        "test-lib",
+					StartClass: "test.Aplication",
+				})).To(gomega.MatchError("Start-Class test.Aplication not found in test-classes or the JARs in test-lib"))
+			})
+
+			it("returns error when Start-Class has no main method", func() {
+				g.Expect(springboot.VerifyStartClass(springboot.Metadata{
+					ClassPath:  []string{classes},
+					StartClass: "test.NoMain",
+				})).To(gomega.MatchError("Start-Class test.NoMain does not declare a public static void main(String[]) method"))
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver"
+	"github.com/cloudfoundry/libcfbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/mitchellh/mapstructure"
+)
+
+const (
+	// EnforceSupported is the environment variable that fails the build, rather than warning, when the Spring Boot
+	// version is past the end of OSS support.
+	EnforceSupported = "BP_SPRING_BOOT_ENFORCE_SUPPORTED"
+
+	// SupportMetadata is the buildpack.toml metadata key of support windows that add to or replace the embedded ones.
+	SupportMetadata = "spring-boot-support"
+)
+
+// SupportWindow is the end of OSS support of a Spring Boot minor version.
+type SupportWindow struct {
+	// Version is the minor version, e.g. 2.3.
+	Version string `mapstructure:"version"`
+
+	// EndOfSupport is the last day of OSS support, formatted as YYYY-MM-DD.
+	EndOfSupport string `mapstructure:"end-of-support"`
+}
+
+// SupportWindows are the support windows of Spring Boot minor versions keyed by minor version.
+type SupportWindows map[string]SupportWindow
+
+// DefaultSupportWindows are the embedded support windows, as published at https://spring.io/projects/spring-boot.
+var DefaultSupportWindows = SupportWindows{
+	"1.5": {"1.5", "2019-08-06"},
+	"2.0": {"2.0", "2019-04-03"},
+	"2.1": {"2.1", "2020-10-30"},
+	"2.2": {"2.2", "2020-10-16"},
+	"2.3": {"2.3", "2021-05-20"},
+	"2.4": {"2.4", "2021-11-18"},
+	"2.5": {"2.5", "2022-05-19"},
+	"2.6": {"2.6", "2022-11-24"},
+	"2.7": {"2.7", "2023-11-24"},
+	"3.0": {"3.0", "2023-12-31"},
+	"3.1": {"3.1", "2024-06-30"},
+	"3.2": {"3.2", "2024-12-31"},
+	"3.3": {"3.3", "2025-06-30"},
+	"3.4": {"3.4", "2025-12-31"},
+}
+
+// Check returns a description of why the Spring-Boot-Version is unsupported at a time.  A version without a known
+// support window is unsupported if it is older than the newest version with one, as only lines that are still
+// supported are missing from the published windows.  OK is false if the version is supported or newer than every
+// known support window.
+func (s SupportWindows) Check(version string, now time.Time) (string, bool, error) {
+	n := numeric.FindString(version)
+	if n == "" {
+		return "", false, nil
+	}
+
+	v, err := semver.NewVersion(n)
+	if err != nil {
+		return "", false, nil
+	}
+
+	m := fmt.Sprintf("%d.%d", v.Major(), v.Minor())
+
+	w, ok := s[m]
+	if !ok {
+		if l, ok := s.newest(); ok && v.LessThan(l) {
+			return fmt.Sprintf("Spring Boot %s has no known support window and is older than Spring Boot %d.%d", m, l.Major(), l.Minor()), true, nil
+		}
+		return "", false, nil
+	}
+
+	e, err := time.Parse("2006-01-02", w.EndOfSupport)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid end-of-support %s for Spring Boot %s: %w", w.EndOfSupport, w.Version, err)
+	}
+
+	if !now.After(e.AddDate(0, 0, 1)) {
+		return "", false, nil
+	}
+
+	return fmt.Sprintf("Spring Boot %s reached the end of OSS support on %s", w.Version, w.EndOfSupport), true, nil
+}
+
+func (s SupportWindows) newest() (*semver.Version, bool) {
+	var n *semver.Version
+
+	for k := range s {
+		v, err := semver.NewVersion(k)
+		if err != nil {
+			continue
+		}
+
+		if n == nil || v.GreaterThan(n) {
+			n = v
+		}
+	}
+
+	return n, n != nil
+}
+
+// NewSupportWindows creates a new SupportWindows instance from DefaultSupportWindows and the spring-boot-support
+// buildpack.toml metadata, which takes precedence.
+func NewSupportWindows(buildpack buildpack.Buildpack) (SupportWindows, error) {
+	s := SupportWindows{}
+	for k, v := range DefaultSupportWindows {
+		s[k] = v
+	}
+
+	m, ok := buildpack.Metadata[SupportMetadata]
+	if !ok {
+		return s, nil
+	}
+
+	var w []SupportWindow
+	if err := mapstructure.Decode(m, &w); err != nil {
+		return nil, fmt.Errorf("invalid %s metadata: %w", SupportMetadata, err)
+	}
+
+	for _, v := range w {
+		s[v.Version] = v
+	}
+
+	return s, nil
+}
+
+func enforceSupported() (bool, error) {
+	return config.LookupBool(EnforceSupported, false)
+}
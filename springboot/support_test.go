@@ -0,0 +1,111 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/buildpacks/libbuildpack/v2/buildpack"
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestSupportWindows(t *testing.T) {
+	spec.Run(t, "SupportWindows", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("returns default support windows", func() {
+			s, err := springboot.NewSupportWindows(f.Build.Buildpack)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(s).To(gomega.Equal(springboot.DefaultSupportWindows))
+		})
+
+		it("returns support windows from buildpack metadata", func() {
+			f.Build.Buildpack.Metadata = buildpack.Metadata{
+				springboot.SupportMetadata: []map[string]interface{}{
+					{"version": "2.7", "end-of-support": "2030-01-01"},
+					{"version": "9.9", "end-of-support": "2040-01-01"},
+				},
+			}
+
+			s, err := springboot.NewSupportWindows(f.Build.Buildpack)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(s["2.7"]).To(gomega.Equal(springboot.SupportWindow{Version: "2.7", EndOfSupport: "2030-01-01"}))
+			g.Expect(s["9.9"]).To(gomega.Equal(springboot.SupportWindow{Version: "9.9", EndOfSupport: "2040-01-01"}))
+			g.Expect(s["2.6"]).To(gomega.Equal(springboot.DefaultSupportWindows["2.6"]))
+		})
+
+		it("returns error for invalid buildpack metadata", func() {
+			f.Build.Buildpack.Metadata = buildpack.Metadata{springboot.SupportMetadata: "test-value"}
+
+			_, err := springboot.NewSupportWindows(f.Build.Buildpack)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		when("Check", func() {
+
+			s := springboot.SupportWindows{"2.7": {Version: "2.7", EndOfSupport: "2023-11-24"}}
+
+			it("returns false before end of support", func() {
+				_, ok, err := s.Check("2.7.18", time.Date(2023, 11, 24, 12, 0, 0, 0, time.UTC))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(ok).To(gomega.BeFalse())
+			})
+
+			it("returns true after end of support", func() {
+				r, ok, err := s.Check("2.7.18.RELEASE", time.Date(2023, 11, 26, 0, 0, 0, 0, time.UTC))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(r).To(gomega.Equal("Spring Boot 2.7 reached the end of OSS support on 2023-11-24"))
+			})
+
+			it("returns true for unknown versions older than the newest known version", func() {
+				r, ok, err := s.Check("2.6.15", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(ok).To(gomega.BeTrue())
+				g.Expect(r).To(gomega.Equal("Spring Boot 2.6 has no known support window and is older than Spring Boot 2.7"))
+			})
+
+			it("returns false for unknown versions newer than the newest known version", func() {
+				_, ok, err := s.Check("3.0.0", time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(ok).To(gomega.BeFalse())
+
+				_, ok, err = s.Check("test-version", time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC))
+				g.Expect(err).NotTo(gomega.HaveOccurred())
+				g.Expect(ok).To(gomega.BeFalse())
+			})
+
+			it("returns error for invalid end of support", func() {
+				_, _, err := springboot.SupportWindows{"2.7": {Version: "2.7", EndOfSupport: "test"}}.
+					Check("2.7.0", time.Now())
+				g.Expect(err).To(gomega.HaveOccurred())
+			})
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"path/filepath"
+
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+const (
+	// UnreadableJARs is the environment variable that configures how files in the lib directories that cannot be read
+	// (e.g. corrupt JARs) are handled when dependencies are scanned.
+	UnreadableJARs = "BP_SPRING_BOOT_UNREADABLE_JARS"
+
+	// UnreadableJARsFail fails the build.
+	UnreadableJARsFail = "fail"
+
+	// UnreadableJARsWarn warns and records the file as an UnidentifiedDependency.
+	UnreadableJARsWarn = "warn"
+)
+
+// UnidentifiedDependency is a file in the lib directories that could not be read when dependencies were scanned.
+type UnidentifiedDependency struct {
+	// Name is the name of the file.
+	Name string `json:"name" toml:"name"`
+
+	// SHA256 is the SHA256 of the file, if it could be read.
+	SHA256 string `json:"sha256,omitempty" toml:"sha256,omitempty"`
+
+	// Reason is the error that the file could not be read with.
+	Reason string `json:"reason" toml:"reason"`
+}
+
+// UnidentifiedDependencies are unidentified dependencies, ordered by name and SHA256.
+type UnidentifiedDependencies []UnidentifiedDependency
+
+func (u UnidentifiedDependencies) Len() int {
+	return len(u)
+}
+
+func (u UnidentifiedDependencies) Less(i, j int) bool {
+	if u[i].Name != u[j].Name {
+		return u[i].Name < u[j].Name
+	}
+
+	return u[i].SHA256 < u[j].SHA256
+}
+
+func (u UnidentifiedDependencies) Swap(i, j int) {
+	u[i], u[j] = u[j], u[i]
+}
+
+// newUnidentifiedDependency creates a new UnidentifiedDependency for a file that could not be read.
+func newUnidentifiedDependency(path string, hashes *HashCache, reason error) UnidentifiedDependency {
+	u := UnidentifiedDependency{Name: filepath.Base(path), Reason: reason.Error()}
+
+	if h, err := hashes.Hash(path); err == nil {
+		u.SHA256 = h
+	}
+
+	return u
+}
+
+func unreadableJARsPolicy() string {
+	if p, ok := config.Lookup(UnreadableJARs); ok {
+		return p
+	}
+
+	return UnreadableJARsWarn
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestUnidentifiedDependency(t *testing.T) {
+	spec.Run(t, "Unidentified Dependency", func(t *testing.T, _ spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		it("orders by name and SHA256", func() {
+			u := springboot.UnidentifiedDependencies{
+				{Name: "test-2.jar", SHA256: "test-sha256-1"},
+				{Name: "test-1.jar", SHA256: "test-sha256-2"},
+				{Name: "test-1.jar", SHA256: "test-sha256-1"},
+			}
+
+			sort.Sort(u)
+
+			g.Expect(u).To(gomega.Equal(springboot.UnidentifiedDependencies{
+				{Name: "test-1.jar", SHA256: "test-sha256-1"},
+				{Name: "test-1.jar", SHA256: "test-sha256-2"},
+				{Name: "test-2.jar", SHA256: "test-sha256-1"},
+			}))
+		})
+	}, spec.Report(report.Terminal{}))
+}
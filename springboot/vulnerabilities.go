@@ -0,0 +1,200 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/logger"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+)
+
+const (
+	// VulnerabilityEndpoint is the environment variable that configures the OSV /v1/querybatch endpoint (e.g.
+	// https://api.osv.dev/v1/querybatch) that dependencies are checked against.
+	VulnerabilityEndpoint = "BP_SPRING_BOOT_VULN_ENDPOINT"
+
+	// VulnerabilityPolicy is the environment variable that configures the behavior when vulnerabilities are found.
+	// Valid values are "warn" and "fail".
+	VulnerabilityPolicy = "BP_SPRING_BOOT_VULN_POLICY"
+
+	// VulnerabilityTimeout is the environment variable that configures how long checking dependencies against the
+	// endpoint may take.
+	VulnerabilityTimeout = "BP_SPRING_BOOT_VULN_TIMEOUT"
+
+	// DefaultVulnerabilityTimeout is the default time that checking dependencies against the endpoint may take.
+	DefaultVulnerabilityTimeout = 30 * time.Second
+
+	// VulnerabilityBatchSize is the maximum number of dependencies checked in a single request.
+	VulnerabilityBatchSize = 1000
+)
+
+// Vulnerability represents a vulnerability reported for a JAR dependency.
+type Vulnerability struct {
+	ID string `json:"id"`
+}
+
+// VulnerabilityCheck checks JAR dependencies against an OSV vulnerability database.
+type VulnerabilityCheck struct {
+	client   *http.Client
+	endpoint string
+	logger   logger.Logger
+	policy   string
+}
+
+type osvPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+type osvQuery struct {
+	Package osvPackage `json:"package"`
+	Version string     `json:"version"`
+}
+
+// Check queries the configured OSV /v1/querybatch endpoint for the dependencies with Maven coordinates, logging any
+// vulnerabilities reported and returning an error if the policy is "fail".  If the policy is "warn", failures to query
+// the endpoint are logged and the build continues.
+func (v VulnerabilityCheck) Check(dependencies JARDependencies) error {
+	var (
+		c JARDependencies
+		q []osvQuery
+	)
+
+	for _, d := range dependencies {
+		if d.Group == "" {
+			v.logger.Debug("Skipping vulnerability check of %s %s without a Maven group", d.Name, d.Version)
+			continue
+		}
+
+		c = append(c, d)
+		q = append(q, osvQuery{
+			Package: osvPackage{Ecosystem: "Maven", Name: fmt.Sprintf("%s:%s", d.Group, d.Name)},
+			Version: d.Version,
+		})
+	}
+
+	var s []string
+	for i := 0; i < len(q); i += VulnerabilityBatchSize {
+		j := i + VulnerabilityBatchSize
+		if j > len(q) {
+			j = len(q)
+		}
+
+		r, err := v.query(q[i:j])
+		if err != nil && v.policy == "warn" {
+			v.logger.BodyWarning("Unable to check vulnerabilities: %s", err)
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("unable to check vulnerabilities: %w", err)
+		}
+
+		for k, u := range r {
+			d := c[i+k]
+			for _, w := range u {
+				s = append(s, fmt.Sprintf("%s:%s %s: %s", d.Group, d.Name, d.Version, w.ID))
+			}
+		}
+	}
+
+	if len(s) == 0 {
+		v.logger.Body("No vulnerabilities found in %d dependencies", len(c))
+		return nil
+	}
+
+	if v.policy == "fail" {
+		return fmt.Errorf("%d vulnerabilities found:\n%s", len(s), strings.Join(s, "\n"))
+	}
+
+	v.logger.HeaderWarning("%d vulnerabilities found", len(s))
+	v.logger.BodyWarning(strings.Join(s, "\n"))
+	return nil
+}
+
+// query returns the vulnerabilities reported for each query, in order.
+func (v VulnerabilityCheck) query(queries []osvQuery) ([][]Vulnerability, error) {
+	b, err := json.Marshal(struct {
+		Queries []osvQuery `json:"queries"`
+	}{queries})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := v.client.Post(v.endpoint, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%s returned %d", v.endpoint, resp.StatusCode)
+	}
+
+	var r struct {
+		Results []struct {
+			Vulns []Vulnerability `json:"vulns"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	if len(r.Results) != len(queries) {
+		return nil, fmt.Errorf("%s returned %d results for %d queries", v.endpoint, len(r.Results), len(queries))
+	}
+
+	u := make([][]Vulnerability, len(r.Results))
+	for i, s := range r.Results {
+		u[i] = s.Vulns
+	}
+
+	return u, nil
+}
+
+// NewVulnerabilityCheck creates a new VulnerabilityCheck instance.  OK is true if BP_SPRING_BOOT_VULN_POLICY is set.
+func NewVulnerabilityCheck(logger logger.Logger) (VulnerabilityCheck, bool, error) {
+	p, ok := config.Lookup(VulnerabilityPolicy)
+	if !ok {
+		return VulnerabilityCheck{}, false, nil
+	}
+
+	if p != "warn" && p != "fail" {
+		return VulnerabilityCheck{}, false, fmt.Errorf("%s must be one of warn or fail: %s", VulnerabilityPolicy, p)
+	}
+
+	e, ok := config.Lookup(VulnerabilityEndpoint)
+	if !ok {
+		return VulnerabilityCheck{}, false, fmt.Errorf("%s must be set when %s is set", VulnerabilityEndpoint, VulnerabilityPolicy)
+	}
+
+	t, err := config.LookupDuration(VulnerabilityTimeout, DefaultVulnerabilityTimeout)
+	if err != nil {
+		return VulnerabilityCheck{}, false, err
+	}
+
+	return VulnerabilityCheck{
+		&http.Client{Transport: &http.Transport{Proxy: http.ProxyFromEnvironment}, Timeout: t},
+		e,
+		logger,
+		p,
+	}, true, nil
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestVulnerabilityCheck(t *testing.T) {
+	spec.Run(t, "VulnerabilityCheck", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			f        *test.BuildFactory
+			received []interface{}
+			response string
+			server   *httptest.Server
+		)
+
+		dependencies := springboot.JARDependencies{
+			{Name: "test-name", Version: "test-version", SHA256: "test-sha256", Group: "test-group"},
+			{Name: "test-ungrouped", Version: "test-version", SHA256: "test-sha256"},
+		}
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var in struct {
+					Queries []interface{} `json:"queries"`
+				}
+				g.Expect(json.NewDecoder(r.Body).Decode(&in)).To(gomega.Succeed())
+				received = in.Queries
+
+				_, _ = w.Write([]byte(response))
+			}))
+		})
+
+		it.After(func() {
+			server.Close()
+		})
+
+		it("returns false if BP_SPRING_BOOT_VULN_POLICY is not set", func() {
+			_, ok, err := springboot.NewVulnerabilityCheck(f.Build.Logger)
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns error if BP_SPRING_BOOT_VULN_POLICY is invalid", func() {
+			defer test.ReplaceEnv(t, springboot.VulnerabilityPolicy, "test-value")()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityEndpoint, server.URL)()
+
+			_, _, err := springboot.NewVulnerabilityCheck(f.Build.Logger)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("returns error if BP_SPRING_BOOT_VULN_ENDPOINT is not set", func() {
+			defer test.ReplaceEnv(t, springboot.VulnerabilityPolicy, "warn")()
+
+			_, _, err := springboot.NewVulnerabilityCheck(f.Build.Logger)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("returns error if BP_SPRING_BOOT_VULN_TIMEOUT is invalid", func() {
+			defer test.ReplaceEnv(t, springboot.VulnerabilityPolicy, "warn")()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityEndpoint, server.URL)()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityTimeout, "test-value")()
+
+			_, _, err := springboot.NewVulnerabilityCheck(f.Build.Logger)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("returns error if the endpoint does not respond within BP_SPRING_BOOT_VULN_TIMEOUT", func() {
+			slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+			}))
+			defer slow.Close()
+
+			defer test.ReplaceEnv(t, springboot.VulnerabilityPolicy, "fail")()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityEndpoint, slow.URL)()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityTimeout, "10ms")()
+
+			v, _, err := springboot.NewVulnerabilityCheck(f.Build.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(v.Check(dependencies)).To(gomega.MatchError(gomega.ContainSubstring("unable to check vulnerabilities")))
+		})
+
+		it("returns no error if the endpoint cannot be reached with warn policy", func() {
+			slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+			}))
+			defer slow.Close()
+
+			defer test.ReplaceEnv(t, springboot.VulnerabilityPolicy, "warn")()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityEndpoint, slow.URL)()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityTimeout, "10ms")()
+
+			v, _, err := springboot.NewVulnerabilityCheck(f.Build.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(v.Check(dependencies)).To(gomega.Succeed())
+		})
+
+		it("returns no error if the endpoint returns an error with warn policy", func() {
+			failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer failing.Close()
+
+			defer test.ReplaceEnv(t, springboot.VulnerabilityPolicy, "warn")()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityEndpoint, failing.URL)()
+
+			v, _, err := springboot.NewVulnerabilityCheck(f.Build.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(v.Check(dependencies)).To(gomega.Succeed())
+		})
+
+		it("returns error if the endpoint returns an error with fail policy", func() {
+			failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			}))
+			defer failing.Close()
+
+			defer test.ReplaceEnv(t, springboot.VulnerabilityPolicy, "fail")()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityEndpoint, failing.URL)()
+
+			v, _, err := springboot.NewVulnerabilityCheck(f.Build.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(v.Check(dependencies)).To(gomega.MatchError(gomega.ContainSubstring("returned 500")))
+		})
+
+		it("queries dependencies with Maven coordinates", func() {
+			defer test.ReplaceEnv(t, springboot.VulnerabilityPolicy, "fail")()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityEndpoint, server.URL)()
+			response = `{ "results": [ {} ] }`
+
+			v, ok, err := springboot.NewVulnerabilityCheck(f.Build.Logger)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(v.Check(dependencies)).To(gomega.Succeed())
+			g.Expect(received).To(gomega.Equal([]interface{}{
+				map[string]interface{}{
+					"package": map[string]interface{}{"ecosystem": "Maven", "name": "test-group:test-name"},
+					"version": "test-version",
+				},
+			}))
+		})
+
+		it("warns on vulnerabilities with warn policy", func() {
+			defer test.ReplaceEnv(t, springboot.VulnerabilityPolicy, "warn")()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityEndpoint, server.URL)()
+			response = `{ "results": [ { "vulns": [ { "id": "test-id", "modified": "2020-01-01T00:00:00Z" } ] } ] }`
+
+			v, _, err := springboot.NewVulnerabilityCheck(f.Build.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(v.Check(dependencies)).To(gomega.Succeed())
+		})
+
+		it("fails on vulnerabilities with fail policy", func() {
+			defer test.ReplaceEnv(t, springboot.VulnerabilityPolicy, "fail")()
+			defer test.ReplaceEnv(t, springboot.VulnerabilityEndpoint, server.URL)()
+			response = `{ "results": [ { "vulns": [ { "id": "test-id", "modified": "2020-01-01T00:00:00Z" } ] } ] }`
+
+			v, _, err := springboot.NewVulnerabilityCheck(f.Build.Logger)
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+
+			g.Expect(v.Check(dependencies)).To(gomega.MatchError(gomega.ContainSubstring("test-id")))
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,85 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// walk walks the file tree rooted at path, like filepath.Walk, resolving symbolic links.  Links that resolve outside of
+// root, or that cannot be resolved, are an error.  Links to files are reported with the information of their target.
+// Links to directories are descended into, reporting paths beneath the link, if follow is true and are otherwise
+// reported as the link itself.  Each directory is descended into at most once through links, so cycles terminate.
+func walk(root string, path string, follow bool, fn filepath.WalkFunc) error {
+	r, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return err
+	}
+
+	w := walker{root: r, follow: follow, fn: fn, visited: make(map[string]bool)}
+	return w.walk(path, path)
+}
+
+type walker struct {
+	root    string
+	follow  bool
+	fn      filepath.WalkFunc
+	visited map[string]bool
+}
+
+// walk walks the file tree rooted at real, reporting paths relative to it beneath logical.
+func (w walker) walk(real string, logical string) error {
+	return filepath.Walk(real, func(path string, info os.FileInfo, err error) error {
+		rel, e := filepath.Rel(real, path)
+		if e != nil {
+			return e
+		}
+		path = filepath.Join(logical, rel)
+
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			return w.fn(path, info, err)
+		}
+
+		target, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return fmt.Errorf("unable to resolve symlink %s: %w", path, err)
+		}
+
+		if r, err := filepath.Rel(w.root, target); err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("symlink %s resolves to %s, outside of %s", path, target, w.root)
+		}
+
+		t, err := os.Stat(target)
+		if err != nil {
+			return w.fn(path, info, err)
+		}
+
+		if !t.IsDir() {
+			return w.fn(path, t, nil)
+		}
+
+		if (!w.follow && rel != ".") || w.visited[target] {
+			return w.fn(path, info, nil)
+		}
+
+		w.visited[target] = true
+		return w.walk(target, path)
+	})
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+const (
+	// Reactive indicates that an application is a reactive web application.
+	Reactive = "reactive"
+
+	// ReactiveThreadCount is the default thread count used to calculate memory for reactive web applications.  Reactive
+	// applications use a small, fixed number of event loop threads rather than a thread per request.
+	ReactiveThreadCount = "50"
+
+	// Servlet indicates that an application is a servlet web application.
+	Servlet = "servlet"
+
+	// ThreadCount is the environment variable that the memory calculator reads the thread count from.
+	ThreadCount = "BPL_THREAD_COUNT"
+)
+
+// WebApplicationType describes the web stack of a Spring Boot application.
+type WebApplicationType struct {
+	// Type is either Reactive or Servlet.
+	Type string `toml:"type"`
+}
+
+func (w WebApplicationType) Identity() (string, string) {
+	return "Web Application Type", w.Type
+}
+
+// Contribute writes default launch environment variables, tuned to the web application type, to a layer marked launch.
+// Values configured on the running image take precedence.
+func (w WebApplicationType) Contribute(layer layers.Layer) error {
+	if w.Type != Reactive {
+		return nil
+	}
+
+	return layer.Contribute(webApplicationTypeMetadata{w.Type, ThreadCount}, func(layer layers.Layer) error {
+		if err := layer.DefaultLaunchEnv(ThreadCount, ReactiveThreadCount); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+type webApplicationTypeMetadata struct {
+	Type        string `toml:"type"`
+	ThreadCount string `toml:"thread-count"`
+}
+
+func (w webApplicationTypeMetadata) Identity() (string, string) {
+	return "Web Application Type", w.Type
+}
+
+// NewWebApplicationType creates a new WebApplicationType instance.  As with Spring Boot itself, an application is a
+// reactive web application if spring-webflux is present without spring-webmvc or Jersey, and otherwise a servlet web
+// application if spring-webmvc or a servlet container is present.  OK is false if the application is not a web
+// application.
+func NewWebApplicationType(metadata Metadata) (WebApplicationType, bool) {
+	has := func(names ...string) bool {
+		for _, n := range names {
+			if _, ok := FindJARDependency(metadata.ClassPath, n); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if has("spring-webflux") && !has("spring-webmvc", "jersey-server") {
+		return WebApplicationType{Reactive}, true
+	}
+
+	if has("spring-webmvc", "jersey-server", "tomcat-embed-core", "jetty-server", "undertow-servlet") {
+		return WebApplicationType{Servlet}, true
+	}
+
+	return WebApplicationType{}, false
+}
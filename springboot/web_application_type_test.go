@@ -0,0 +1,84 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestWebApplicationType(t *testing.T) {
+	spec.Run(t, "WebApplicationType", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		it("returns false for non-web applications", func() {
+			_, ok := springboot.NewWebApplicationType(springboot.Metadata{ClassPath: []string{"/test-lib/spring-core-5.2.4.RELEASE.jar"}})
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("classifies spring-webflux as reactive", func() {
+			w, ok := springboot.NewWebApplicationType(springboot.Metadata{ClassPath: []string{
+				"/test-lib/spring-webflux-5.2.4.RELEASE.jar",
+				"/test-lib/tomcat-embed-core-9.0.31.jar",
+			}})
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(w.Type).To(gomega.Equal(springboot.Reactive))
+		})
+
+		it("classifies spring-webmvc as servlet", func() {
+			w, ok := springboot.NewWebApplicationType(springboot.Metadata{ClassPath: []string{
+				"/test-lib/spring-webflux-5.2.4.RELEASE.jar",
+				"/test-lib/spring-webmvc-5.2.4.RELEASE.jar",
+			}})
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(w.Type).To(gomega.Equal(springboot.Servlet))
+		})
+
+		it("classifies servlet containers as servlet", func() {
+			w, ok := springboot.NewWebApplicationType(springboot.Metadata{ClassPath: []string{"/test-lib/jetty-server-9.4.27.v20200227.jar"}})
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(w.Type).To(gomega.Equal(springboot.Servlet))
+		})
+
+		it("contributes reactive thread count", func() {
+			f := test.NewBuildFactory(t)
+			layer := f.Build.Layers.Layer("web-application-type")
+
+			g.Expect(springboot.WebApplicationType{Type: springboot.Reactive}.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(layer).To(test.HaveDefaultLaunchEnvironment("BPL_THREAD_COUNT", springboot.ReactiveThreadCount))
+			g.Expect(filepath.Join(layer.Root, "env.launch", "BPL_JVM_THREAD_COUNT.default")).NotTo(gomega.BeAnExistingFile())
+		})
+
+		it("does not contribute for servlet", func() {
+			f := test.NewBuildFactory(t)
+			layer := f.Build.Layers.Layer("web-application-type")
+
+			g.Expect(springboot.WebApplicationType{Type: springboot.Servlet}.Contribute(layer)).To(gomega.Succeed())
+
+			g.Expect(layer.Metadata).NotTo(gomega.BeAnExistingFile())
+		})
+	}, spec.Report(report.Terminal{}))
+}
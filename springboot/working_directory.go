@@ -0,0 +1,70 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/config"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+// WorkDir is the environment variable that configures the working directory of the launch process.  Relative paths
+// are resolved against the workspace.
+const WorkDir = "BP_SPRING_BOOT_WORKDIR"
+
+// WorkingDirectory contributes a profile.d script that changes the working directory of the launch process, for
+// applications that load resources by paths relative to it.
+type WorkingDirectory struct {
+	// Path is the absolute path of the working directory.
+	Path string `toml:"path"`
+}
+
+func (w WorkingDirectory) Identity() (string, string) {
+	return "Working Directory", w.Path
+}
+
+// Contribute writes the profile.d script to a layer marked launch.
+func (w WorkingDirectory) Contribute(layer layers.Layer) error {
+	return layer.Contribute(w, func(layer layers.Layer) error {
+		if err := layer.WriteProfile("working-directory", "cd %s\n", shellQuote(w.Path)); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewWorkingDirectory creates a new WorkingDirectory instance.  OK is true if $BP_SPRING_BOOT_WORKDIR is set.
+func NewWorkingDirectory(workspace string) (WorkingDirectory, bool) {
+	p, ok := config.Lookup(WorkDir)
+	if !ok || strings.TrimSpace(p) == "" {
+		return WorkingDirectory{}, false
+	}
+
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(workspace, p)
+	}
+
+	return WorkingDirectory{filepath.Clean(p)}, true
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package springboot_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/springboot"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestWorkingDirectory(t *testing.T) {
+	spec.Run(t, "WorkingDirectory", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var f *test.BuildFactory
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+		})
+
+		it("returns false when $BP_SPRING_BOOT_WORKDIR is not set", func() {
+			_, ok := springboot.NewWorkingDirectory(f.Build.Application.Root)
+			g.Expect(ok).To(gomega.BeFalse())
+		})
+
+		it("resolves relative paths against the workspace", func() {
+			defer test.ReplaceEnv(t, springboot.WorkDir, "test-directory/")()
+
+			w, ok := springboot.NewWorkingDirectory(f.Build.Application.Root)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(w.Path).To(gomega.Equal(filepath.Join(f.Build.Application.Root, "test-directory")))
+		})
+
+		it("uses absolute paths", func() {
+			defer test.ReplaceEnv(t, springboot.WorkDir, "/test-directory")()
+
+			w, ok := springboot.NewWorkingDirectory(f.Build.Application.Root)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(w.Path).To(gomega.Equal("/test-directory"))
+		})
+
+		it("contributes profile.d script", func() {
+			d := filepath.Join(test.ScratchDir(t, "working-directory"), "test 'directory'")
+			test.TouchFile(t, d, "test-file")
+
+			layer := f.Build.Layers.Layer("working-directory")
+			g.Expect(springboot.WorkingDirectory{Path: d}.Contribute(layer)).To(gomega.Succeed())
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+
+			c := exec.Command("sh", "-c", `. "$0" && pwd`, filepath.Join(layer.Root, "profile.d", "working-directory"))
+			b, err := c.Output()
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(string(b)).To(gomega.Equal(d + "\n"))
+		})
+	}, spec.Report(report.Terminal{}))
+}
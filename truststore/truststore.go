@@ -0,0 +1,153 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package truststore
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/build"
+	"github.com/cloudfoundry/libcfbuildpack/v2/helper"
+	"github.com/cloudfoundry/libcfbuildpack/v2/layers"
+	"github.com/cloudfoundry/spring-boot-cnb/reproducible"
+)
+
+// Service is the filter used to find a binding that provides CA certificates.  Every credential of the binding that
+// contains PEM encoded certificates is added to the truststore.
+const Service = "ca-certificates"
+
+// TrustStore represents a truststore, assembled at launch from the JVM's default truststore and bound CA
+// certificates.
+type TrustStore struct {
+	// Certificates are the PEM encoded bound certificates.
+	Certificates []string
+
+	layer layers.Layer
+}
+
+// Contribute writes the bound certificates and a profile.d script that assembles the truststore to a layer marked
+// launch.
+func (t TrustStore) Contribute() error {
+	h := sha256.New()
+	for _, c := range t.Certificates {
+		_, _ = h.Write([]byte(c))
+	}
+
+	return t.layer.Contribute(marker{len(t.Certificates), hex.EncodeToString(h.Sum(nil))}, func(layer layers.Layer) error {
+		for i, c := range t.Certificates {
+			if err := helper.WriteFile(filepath.Join(layer.Root, "certs", fmt.Sprintf("%03d.pem", i)), 0644, "%s", c); err != nil {
+				return err
+			}
+		}
+
+		if err := layer.WriteProfile("truststore", `TRUSTSTORE="${TMPDIR:-/tmp}/truststore.jks"
+
+CACERTS="${JAVA_HOME}/lib/security/cacerts"
+if [ ! -f "${CACERTS}" ]; then
+  CACERTS="${JAVA_HOME}/jre/lib/security/cacerts"
+fi
+
+cp "${CACERTS}" "${TRUSTSTORE}"
+chmod u+w "${TRUSTSTORE}"
+
+for CERT in "%s"/*.pem; do
+  "${JAVA_HOME}/bin/keytool" -importcert -noprompt -keystore "${TRUSTSTORE}" -storepass changeit -alias "bound-$(basename "${CERT}" .pem)" -file "${CERT}" > /dev/null
+done
+
+export JAVA_OPTS="${JAVA_OPTS} -Djavax.net.ssl.trustStore=${TRUSTSTORE} -Djavax.net.ssl.trustStorePassword=changeit"
+`, filepath.Join(layer.Root, "certs")); err != nil {
+			return err
+		}
+
+		return reproducible.Normalize(layer.Root)
+	}, layers.Launch)
+}
+
+// NewTrustStore creates a new TrustStore instance.  OK is true if a "ca-certificates" binding with at least one
+// certificate exists.
+func NewTrustStore(build build.Build) (TrustStore, bool, error) {
+	c, ok := build.Services.FindServiceCredentials(Service)
+	if !ok {
+		return TrustStore{}, false, nil
+	}
+
+	var keys []string
+	for k := range c {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var certs []string
+	for _, k := range keys {
+		s, ok := c[k].(string)
+		if !ok {
+			continue
+		}
+
+		b, err := parse(s)
+		if err != nil {
+			return TrustStore{}, false, fmt.Errorf("invalid certificate in %s binding credential %s: %w", Service, k, err)
+		}
+
+		certs = append(certs, b...)
+	}
+
+	if len(certs) == 0 {
+		build.Logger.BodyWarning("%s binding contains no PEM encoded certificates", Service)
+		return TrustStore{}, false, nil
+	}
+
+	return TrustStore{certs, build.Layers.Layer("truststore")}, true, nil
+}
+
+func parse(s string) ([]string, error) {
+	var certs []string
+
+	rest := []byte(s)
+	for {
+		var b *pem.Block
+		if b, rest = pem.Decode(rest); b == nil {
+			break
+		}
+
+		if b.Type != "CERTIFICATE" {
+			continue
+		}
+
+		if _, err := x509.ParseCertificate(b.Bytes); err != nil {
+			return nil, err
+		}
+
+		certs = append(certs, string(pem.EncodeToMemory(b)))
+	}
+
+	return certs, nil
+}
+
+type marker struct {
+	Count  int    `toml:"count"`
+	SHA256 string `toml:"sha256"`
+}
+
+func (m marker) Identity() (string, string) {
+	return "CA Certificates", fmt.Sprintf("(%d certificates)", m.Count)
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2018-2020 the original author or authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      https://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package truststore_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudfoundry/libcfbuildpack/v2/test"
+	"github.com/cloudfoundry/spring-boot-cnb/truststore"
+	"github.com/onsi/gomega"
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+)
+
+func TestTrustStore(t *testing.T) {
+	spec.Run(t, "TrustStore", func(t *testing.T, when spec.G, it spec.S) {
+
+		g := gomega.NewWithT(t)
+
+		var (
+			cert string
+			f    *test.BuildFactory
+		)
+
+		it.Before(func() {
+			f = test.NewBuildFactory(t)
+
+			b, err := ioutil.ReadFile(filepath.Join("testdata", "test-certificate.pem"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			cert = string(b)
+		})
+
+		it("returns false without binding", func() {
+			_, ok, err := truststore.NewTrustStore(f.Build)
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns false when binding has no certificates", func() {
+			f.AddService("ca-certificates", map[string]interface{}{"test-key": "test-value"})
+
+			_, ok, err := truststore.NewTrustStore(f.Build)
+			g.Expect(ok).To(gomega.BeFalse())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		it("returns error for invalid certificate", func() {
+			f.AddService("ca-certificates", map[string]interface{}{
+				"test-key": "-----BEGIN CERTIFICATE-----\ndGVzdA==\n-----END CERTIFICATE-----\n",
+			})
+
+			_, _, err := truststore.NewTrustStore(f.Build)
+			g.Expect(err).To(gomega.HaveOccurred())
+		})
+
+		it("contributes bound certificates", func() {
+			f.AddService("ca-certificates", map[string]interface{}{
+				"test-key-1": cert,
+				"test-key-2": cert + cert,
+			})
+
+			s, ok, err := truststore.NewTrustStore(f.Build)
+			g.Expect(ok).To(gomega.BeTrue())
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(s.Certificates).To(gomega.HaveLen(3))
+
+			g.Expect(s.Contribute()).To(gomega.Succeed())
+
+			layer := f.Build.Layers.Layer("truststore")
+			g.Expect(layer).To(test.HaveLayerMetadata(false, false, true))
+			g.Expect(filepath.Join(layer.Root, "certs", "000.pem")).To(test.HaveContent(cert))
+			g.Expect(filepath.Join(layer.Root, "certs", "002.pem")).To(test.HaveContent(cert))
+
+			b, err := ioutil.ReadFile(filepath.Join(layer.Root, "profile.d", "truststore"))
+			g.Expect(err).NotTo(gomega.HaveOccurred())
+			g.Expect(string(b)).To(gomega.ContainSubstring(filepath.Join(layer.Root, "certs")))
+			g.Expect(string(b)).To(gomega.ContainSubstring("-Djavax.net.ssl.trustStore=${TRUSTSTORE}"))
+		})
+	}, spec.Report(report.Terminal{}))
+}